@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiling starts a CPU profile in dir and returns a function that stops
+// it and writes a heap profile alongside
+func startProfiling(dir string) (func() error, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	cpuFile, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		cpuFile.Close()
+		return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+		if err := cpuFile.Close(); err != nil {
+			return fmt.Errorf("failed to close CPU profile: %w", err)
+		}
+
+		heapFile, err := os.Create(filepath.Join(dir, "heap.pprof"))
+		if err != nil {
+			return fmt.Errorf("failed to create heap profile: %w", err)
+		}
+		defer heapFile.Close()
+		runtime.GC() // get up-to-date statistics
+		if err := pprof.WriteHeapProfile(heapFile); err != nil {
+			return fmt.Errorf("failed to write heap profile: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -12,6 +12,7 @@ import (
 )
 
 var cfgFile string
+var profileDir string
 
 var rootCmd = &cobra.Command{
 	Use:   "foodatasim",
@@ -31,14 +32,31 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading menu dish data: %v", err)
 		}
+		stopProfiling := func() error { return nil }
+		if profileDir != "" {
+			stopProfiling, err = startProfiling(profileDir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error starting profiler: %v\n", err)
+				os.Exit(1)
+			}
+		}
 		sim := simulator.NewSimulator(cfg)
-		sim.Run()
+		runErr := sim.Run()
+		// stopped explicitly rather than deferred, since os.Exit skips deferred calls
+		if err := stopProfiling(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing profiles: %v\n", err)
+		}
+		if runErr != nil {
+			fmt.Fprintf(os.Stderr, "Simulation failed: %v\n", runErr)
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.foodatasim.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileDir, "profile", "", "write CPU and heap profiles (cpu.pprof, heap.pprof) to this directory")
 
 	rootCmd.Flags().Int("seed", 42, "Random seed for simulation")
 	rootCmd.Flags().String("start-date", time.Now().Format(time.RFC3339), "Start date for simulation")
@@ -56,8 +74,11 @@ func init() {
 	rootCmd.Flags().String("kafka-broker-list", "localhost:9092", "Kafka broker list")
 	rootCmd.Flags().String("output-file", "", "Output file path (if not using Kafka)")
 	rootCmd.Flags().Bool("continuous", false, "Run simulation in continuous mode")
+	rootCmd.Flags().Int("max-events", 0, "Stop after dispatching this many events (0 runs to the end date)")
+	rootCmd.Flags().Int("workers", 0, "Number of output workers (defaults to the CPU count, 1 for fully ordered output)")
 
 	viper.BindPFlags(rootCmd.Flags())
+	viper.BindPFlag("max_events", rootCmd.Flags().Lookup("max-events"))
 }
 
 func initConfig() {
@@ -0,0 +1,10 @@
+package cloudwriter
+
+// gcsEndpoint is the S3-compatible XML API endpoint of Google Cloud Storage
+const gcsEndpoint = "https://storage.googleapis.com"
+
+// NewGCSWriterFactory creates a writer factory for Google Cloud Storage using
+// its S3-compatible XML API, authenticated with HMAC keys
+func NewGCSWriterFactory(accessKeyID, secretAccessKey string) (*S3WriterFactory, error) {
+	return NewS3CompatibleWriterFactory("auto", gcsEndpoint, accessKeyID, secretAccessKey)
+}
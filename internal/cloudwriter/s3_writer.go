@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
@@ -21,13 +22,30 @@ type S3WriterFactory struct {
 }
 
 func NewS3WriterFactory(region string) (*S3WriterFactory, error) {
+	return NewS3CompatibleWriterFactory(region, "", "", "")
+}
+
+// NewS3CompatibleWriterFactory creates a writer factory for S3 or any storage
+// exposing the S3 API at endpoint. Static credentials are used when given,
+// otherwise the default AWS credential chain applies.
+func NewS3CompatibleWriterFactory(region, endpoint, accessKeyID, secretAccessKey string) (*S3WriterFactory, error) {
 	ctx := context.Background()
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if accessKeyID != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load SDK config: %v", err)
 	}
 
-	client := s3.NewFromConfig(cfg)
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
 	return &S3WriterFactory{client: client}, nil
 }
 
@@ -2,28 +2,33 @@ package factories
 
 import (
 	"github.com/chrisdamba/foodatasim/internal/models"
-	"github.com/lucsky/cuid"
 	"math"
-	"math/rand"
 )
 
 type DeliveryPartnerFactory struct{}
 
 func (df *DeliveryPartnerFactory) CreateDeliveryPartner(config *models.Config) *models.DeliveryPartner {
-	// calculate city bounds
-	latRange := config.UrbanRadius / 111.0 // Approx. conversion from km to degrees
-	lonRange := latRange / math.Cos(config.CityLat*math.Pi/180.0)
+	var lat, lon float64
+	if config.PartnerPlacement == models.PartnerPlacementDemand {
+		lat, lon = demandWeightedLocation(config)
+	} else if config.PartnerPlacement == models.PartnerPlacementHome {
+		lat, lon = homeLocation(config)
+	} else {
+		// calculate city bounds
+		latRange := config.UrbanRadius / 111.0 // Approx. conversion from km to degrees
+		lonRange := latRange / math.Cos(config.CityLat*math.Pi/180.0)
 
-	// generate random offsets within the urban radius
-	latOffset := (rand.Float64()*2 - 1) * latRange
-	lonOffset := (rand.Float64()*2 - 1) * lonRange
+		// generate random offsets within the urban radius
+		latOffset := (rng.Float64()*2 - 1) * latRange
+		lonOffset := (rng.Float64()*2 - 1) * lonRange
 
-	// calculate final latitude and longitude
-	lat := config.CityLat + latOffset
-	lon := config.CityLon + lonOffset
+		// calculate final latitude and longitude
+		lat = config.CityLat + latOffset
+		lon = config.CityLon + lonOffset
+	}
 
 	return &models.DeliveryPartner{
-		ID:           cuid.New(),
+		ID:           newID(),
 		Name:         fake.Person().Name(),
 		JoinDate:     fake.Time().TimeBetween(config.StartDate.AddDate(-1, 0, 0), config.StartDate),
 		Rating:       fake.Float64(1, 1, 5),
@@ -35,7 +40,69 @@ func (df *DeliveryPartnerFactory) CreateDeliveryPartner(config *models.Config) *
 			Lat: lat,
 			Lon: lon,
 		},
+		HomeLocation: models.Location{
+			Lat: lat,
+			Lon: lon,
+		},
 		Status:         models.PartnerStatusAvailable,
 		LastUpdateTime: config.StartDate,
 	}
 }
+
+// homeLocation places a partner's home the way user addresses are placed, but
+// spread over partner_home_radius km of the city centre when it is set
+func homeLocation(config *models.Config) (float64, float64) {
+	radius := config.PartnerHomeRadius
+	if radius <= 0 {
+		radius = config.UrbanRadius
+	}
+	latRange := radius / 111.0
+	lonRange := latRange / math.Cos(config.CityLat*math.Pi/180.0)
+
+	lat := config.CityLat + (rng.Float64()*2-1)*latRange
+	lon := config.CityLon + (rng.Float64()*2-1)*lonRange
+	return lat, lon
+}
+
+// demandWeightedLocation places a partner near a demand hotspot, picked in
+// proportion to its weight, scattered within the hotspot radius and kept
+// inside the urban radius
+func demandWeightedLocation(config *models.Config) (float64, float64) {
+	hotspots := config.DemandHotspots()
+	totalWeight := 0.0
+	for _, hotspot := range hotspots {
+		totalWeight += math.Max(0, hotspot.Weight)
+	}
+
+	center := models.Location{Lat: config.CityLat, Lon: config.CityLon}
+	r := rng.Float64() * totalWeight
+	for _, hotspot := range hotspots {
+		r -= math.Max(0, hotspot.Weight)
+		if r <= 0 {
+			center = hotspot.Location
+			break
+		}
+	}
+
+	radius := config.HotspotRadius
+	if radius <= 0 {
+		radius = 2.0
+	}
+	// uniform over a disc around the hotspot
+	distance := radius * math.Sqrt(rng.Float64())
+	bearing := rng.Float64() * 2 * math.Pi
+	lat := center.Lat + distance*math.Cos(bearing)/111.0
+	lon := center.Lon + distance*math.Sin(bearing)/(111.0*math.Cos(center.Lat*math.Pi/180.0))
+
+	// pull the partner back inside the city if the hotspot sits near its edge
+	if config.UrbanRadius > 0 {
+		dLat := (lat - config.CityLat) * 111.0
+		dLon := (lon - config.CityLon) * 111.0 * math.Cos(config.CityLat*math.Pi/180.0)
+		if d := math.Hypot(dLat, dLon); d > config.UrbanRadius {
+			scale := config.UrbanRadius / d
+			lat = config.CityLat + (lat-config.CityLat)*scale
+			lon = config.CityLon + (lon-config.CityLon)*scale
+		}
+	}
+	return lat, lon
+}
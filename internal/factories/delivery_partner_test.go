@@ -0,0 +1,56 @@
+package factories
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// kmBetween is the equirectangular distance between two nearby points in km
+func kmBetween(a, b models.Location) float64 {
+	dLat := (a.Lat - b.Lat) * 111.0
+	dLon := (a.Lon - b.Lon) * 111.0 * math.Cos(a.Lat*math.Pi/180.0)
+	return math.Hypot(dLat, dLon)
+}
+
+func TestDemandPlacementStaysWithinHotspotRadius(t *testing.T) {
+	hotspot := models.Location{Lat: 53.01, Lon: -2.17}
+	config := &models.Config{
+		CityLat:          53.0,
+		CityLon:          -2.18,
+		UrbanRadius:      10,
+		HotspotRadius:    1.5,
+		PartnerPlacement: models.PartnerPlacementDemand,
+		Hotspots:         []models.Hotspot{{Name: "Station", Location: hotspot, Weight: 1}},
+	}
+	Seed(1, "partners")
+	factory := &DeliveryPartnerFactory{}
+	for i := 0; i < 500; i++ {
+		partner := factory.CreateDeliveryPartner(config)
+		if d := kmBetween(partner.CurrentLocation, hotspot); d > config.HotspotRadius+0.01 {
+			t.Fatalf("partner placed %.2f km from the hotspot, want within %.1f km", d, config.HotspotRadius)
+		}
+	}
+}
+
+func TestDemandPlacementStaysInsideCity(t *testing.T) {
+	city := models.Location{Lat: 53.0, Lon: -2.18}
+	config := &models.Config{
+		CityLat:          city.Lat,
+		CityLon:          city.Lon,
+		UrbanRadius:      3,
+		HotspotRadius:    2,
+		PartnerPlacement: models.PartnerPlacementDemand,
+		// the hotspot sits on the city's edge, so half its disc is outside
+		Hotspots: []models.Hotspot{{Name: "Ring road", Location: models.Location{Lat: city.Lat + 3/111.0, Lon: city.Lon}, Weight: 1}},
+	}
+	Seed(1, "partners")
+	factory := &DeliveryPartnerFactory{}
+	for i := 0; i < 500; i++ {
+		partner := factory.CreateDeliveryPartner(config)
+		if d := kmBetween(partner.CurrentLocation, city); d > config.UrbanRadius+0.01 {
+			t.Fatalf("partner placed %.2f km from the centre, outside the %.0f km urban radius", d, config.UrbanRadius)
+		}
+	}
+}
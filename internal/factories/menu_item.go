@@ -2,52 +2,85 @@ package factories
 
 import (
 	"github.com/chrisdamba/foodatasim/internal/models"
-	"github.com/lucsky/cuid"
-	"math/rand"
+	"math"
 	"strings"
 )
 
 type MenuItemFactory struct{}
 
 func (mf *MenuItemFactory) CreateMenuItem(restaurant *models.Restaurant, config *models.Config) models.MenuItem {
-	menuItemName := generateRandomMenuItem(restaurant.Cuisines, config)
+	// one of the restaurant's cuisines sets the item's name, price and dietary tags
+	var cuisine string
+	if len(restaurant.Cuisines) > 0 {
+		cuisine = restaurant.Cuisines[rng.Intn(len(restaurant.Cuisines))]
+	}
+	menuItemName := generateRandomMenuItem(cuisine, config)
 	if len(menuItemName) > 255 {
 		menuItemName = menuItemName[:252] + "..."
 	}
 	return models.MenuItem{
-		ID:                 cuid.New(),
+		ID:                 newID(),
 		RestaurantID:       restaurant.ID,
 		Name:               sanitiseString(menuItemName),
 		Description:        sanitiseString(fake.Lorem().Sentence(10)),
-		Price:              fake.Float64(2, 5, 50),
+		Price:              generateMenuItemPrice(cuisine, config),
 		PrepTime:           fake.Float64(0, 5, 30),
 		Category:           sanitiseString(fake.Lorem().Word()),
 		Type:               generateRandomMenuItemType(),
 		Popularity:         fake.Float64(2, 0, 100) / 100,
 		PrepComplexity:     fake.Float64(2, 0, 100) / 100,
 		Ingredients:        generateRandomIngredients(),
+		DietaryTags:        generateDietaryTags(config.DietaryTagsFor(cuisine)),
 		IsDiscountEligible: fake.Bool(),
 	}
 }
 
+func generateMenuItemPrice(cuisine string, config *models.Config) float64 {
+	priceRange := models.DefaultPriceRange
+	if cuisine != "" {
+		priceRange = config.CuisinePriceRange(cuisine)
+	}
+	price := priceRange.Min + rng.Float64()*(priceRange.Max-priceRange.Min)
+	return math.Round(price*100) / 100
+}
+
+// generateDietaryTags draws an item's dietary tags from the cuisine's tag
+// probabilities. Vegan items are always vegetarian and dairy-free.
+func generateDietaryTags(probabilities map[string]float64) []string {
+	var tags []string
+	vegan := rng.Float64() < probabilities[models.DietVegan]
+	for _, tag := range []string{
+		models.DietVegetarian, models.DietVegan, models.DietGlutenFree, models.DietDairyFree,
+		models.DietNutFree, models.DietHalal, models.DietKosher,
+	} {
+		switch {
+		case tag == models.DietVegan && vegan,
+			vegan && (tag == models.DietVegetarian || tag == models.DietDairyFree),
+			tag != models.DietVegan && rng.Float64() < probabilities[tag]:
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 func generateRandomIngredients() []string {
 	allIngredients := []string{"Chicken", "Beef", "Pork", "Fish", "Tofu", "Cheese", "Tomato", "Lettuce", "Onion", "Garlic", "Bread", "Rice", "Pasta", "Egg", "Milk"}
-	ingredientCount := rand.Intn(5) + 2 // 2 to 6 ingredients
+	ingredientCount := rng.Intn(5) + 2 // 2 to 6 ingredients
 	ingredients := make([]string, ingredientCount)
 	for i := 0; i < ingredientCount; i++ {
-		ingredients[i] = allIngredients[rand.Intn(len(allIngredients))]
+		ingredients[i] = allIngredients[rng.Intn(len(allIngredients))]
 	}
 	return ingredients
 }
 
-func generateRandomMenuItem(cuisines []string, config *models.Config) string {
+func generateRandomMenuItem(cuisine string, config *models.Config) string {
 	// check if config has menu dishes
 	if len(config.MenuDishes) > 0 {
 		// randomly choose between 1 and 5 dishes from the config
-		dishCount := rand.Intn(5) + 1
+		dishCount := rng.Intn(5) + 1
 		menuDishes := make([]string, dishCount)
 		for i := 0; i < dishCount; i++ {
-			menuDishes[i] = config.MenuDishes[rand.Intn(len(config.MenuDishes))].Name
+			menuDishes[i] = config.MenuDishes[rng.Intn(len(config.MenuDishes))].Name
 		}
 		return strings.Join(menuDishes, ", ")
 	}
@@ -71,16 +104,15 @@ func generateRandomMenuItem(cuisines []string, config *models.Config) string {
 		"French":        {"Coq au Vin", "Beef Bourguignon", "Ratatouille", "Crème Brûlée"},
 		"Mediterranean": {"Falafel", "Hummus", "Tabbouleh", "Grilled Halloumi"},
 	}
-	cuisine := cuisines[rand.Intn(len(cuisines))]
 	if items, ok := items[cuisine]; ok {
-		return items[rand.Intn(len(items))]
+		return items[rng.Intn(len(items))]
 	}
 	return "Special of the Day"
 }
 
 func generateRandomMenuItemType() string {
 	types := []string{"appetizer", "main course", "side dish", "dessert", "drink"}
-	return types[rand.Intn(len(types))]
+	return types[rng.Intn(len(types))]
 }
 
 func sanitiseString(s string) string {
@@ -0,0 +1,48 @@
+package factories
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestMenuItemTakesNamePriceAndTagsFromOneCuisine(t *testing.T) {
+	Seed(42, "menus")
+	config := &models.Config{
+		CuisinePriceRanges: map[string]models.PriceRange{
+			"japanese": {Min: 40, Max: 50},
+			"burgers":  {Min: 5, Max: 10},
+		},
+		CuisineDietaryTags: map[string]map[string]float64{
+			"japanese": {models.DietHalal: 1},
+			"burgers":  {},
+		},
+	}
+	restaurant := &models.Restaurant{ID: "r1", Cuisines: []string{"Japanese", "Burgers"}}
+	japanese := []string{"Sushi Roll", "Ramen", "Tempura", "Miso Soup"}
+
+	seen := make(map[bool]int)
+	for i := 0; i < 500; i++ {
+		item := (&MenuItemFactory{}).CreateMenuItem(restaurant, config)
+		isJapanese := false
+		for _, name := range japanese {
+			isJapanese = isJapanese || item.Name == name
+		}
+		seen[isJapanese]++
+
+		if isJapanese && (item.Price < 40 || item.Price > 50) {
+			t.Fatalf("%q priced %.2f, want the japanese range 40-50", item.Name, item.Price)
+		}
+		if !isJapanese && (item.Price < 5 || item.Price > 10) {
+			t.Fatalf("%q priced %.2f, want the burgers range 5-10", item.Name, item.Price)
+		}
+		halal := strings.Join(item.DietaryTags, ",") == models.DietHalal
+		if halal != isJapanese {
+			t.Fatalf("%q tagged %v, want halal only on japanese dishes", item.Name, item.DietaryTags)
+		}
+	}
+	if seen[true] == 0 || seen[false] == 0 {
+		t.Errorf("generated %d japanese and %d burger items, want both cuisines used", seen[true], seen[false])
+	}
+}
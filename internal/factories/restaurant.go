@@ -2,11 +2,10 @@ package factories
 
 import (
 	"github.com/chrisdamba/foodatasim/internal/models"
-	"github.com/lucsky/cuid"
 	"math"
-	"math/rand"
 	"strings"
 	"sync"
+	"time"
 )
 
 type RestaurantFactory struct{ usedSlugs sync.Map }
@@ -22,7 +21,7 @@ func (rf *RestaurantFactory) generateUniqueSlug() string {
 		slug = strings.ReplaceAll(slug, " ", "-")
 		slug = strings.ReplaceAll(slug, ",", "")
 		slug = strings.ReplaceAll(slug, ".", "")
-		slug = slug + "-" + strings.ToLower(cuid.New()[:6])
+		slug = slug + "-" + strings.ToLower(newID()[:6])
 
 		if _, exists := rf.usedSlugs.LoadOrStore(slug, true); !exists {
 			return slug
@@ -36,8 +35,8 @@ func (rf *RestaurantFactory) CreateRestaurant(config *models.Config) *models.Res
 	lonRange := latRange / math.Cos(config.CityLat*math.Pi/180.0)
 
 	// generate random offsets within the urban radius
-	latOffset := (rand.Float64()*2 - 1) * latRange
-	lonOffset := (rand.Float64()*2 - 1) * lonRange
+	latOffset := (rng.Float64()*2 - 1) * latRange
+	lonOffset := (rng.Float64()*2 - 1) * lonRange
 
 	// calculate final latitude and longitude
 	lat := config.CityLat + latOffset
@@ -46,8 +45,19 @@ func (rf *RestaurantFactory) CreateRestaurant(config *models.Config) *models.Res
 	// Use config for time-related fields
 	avgPrepTime := fake.Float64(0, config.MinPrepTime, config.MaxPrepTime)
 
-	return &models.Restaurant{
-		ID:             cuid.New(),
+	// assume a restaurant collects a couple of ratings a day, so ones with few
+	// ratings opened recently
+	totalRatings := fake.Float64(0, 0, 1000)
+	rating := fake.Float64(1, 1, 5)
+	if dist := config.RestaurantRatingDistribution; dist.Enabled() {
+		rating = dist.Sample(rng.NormFloat64)
+	}
+	openedAt := config.StartDate.Add(-time.Duration(totalRatings/2*24) * time.Hour)
+	cuisines := generateRandomCuisines()
+	baseCapacity := generateBaseCapacity(config, cuisines)
+
+	restaurant := &models.Restaurant{
+		ID:             newID(),
 		Host:           fake.Internet().Domain(),
 		Name:           fake.Company().Name(),
 		Currency:       1, // assuming 1 represents the default currency
@@ -55,30 +65,96 @@ func (rf *RestaurantFactory) CreateRestaurant(config *models.Config) *models.Res
 		Town:           fake.Address().City(),
 		SlugName:       rf.generateUniqueSlug(),
 		WebsiteLogoURL: fake.Internet().URL(),
-		Offline:        "DISABLED",
+		Offline:        models.RestaurantOfflineDisabled,
 		Location: models.Location{
 			Lat: lat,
 			Lon: lon,
 		},
-		Cuisines:         generateRandomCuisines(),
-		Rating:           fake.Float64(1, 1, 5),
-		TotalRatings:     fake.Float64(0, 0, 1000),
+		Cuisines:         cuisines,
+		Rating:           rating,
+		TotalRatings:     totalRatings,
 		PrepTime:         fake.Float64(0, 10, 60),
 		MinPrepTime:      fake.Float64(0, config.MinPrepTime, int(avgPrepTime)),
 		AvgPrepTime:      fake.Float64(0, 15, 45),
 		PickupEfficiency: fake.Float64(2, 50, 150) / 100,
-		Capacity:         fake.IntBetween(10, 50),
+		Capacity:         baseCapacity,
 		MenuItems:        make([]string, 0),
 		CurrentOrders:    []models.Order{},
+		OpenedAt:         openedAt,
+		BaseCapacity:     baseCapacity,
+
+		AcceptedPaymentMethods: generateAcceptedPaymentMethods(config),
+	}
+	restaurant.BadActor = rng.Float64() < config.BadActorRestaurantRate
+	restaurant.CancelRate = config.RestaurantCancelRate
+	if restaurant.BadActor {
+		restaurant.CancelRate = config.BadActorCancelRate
+	}
+	return restaurant
+}
+
+// cuisineCapacityFactors scale the size of venues serving a cuisine: cafes
+// and street food stalls lean small and fast food kitchens lean large
+var cuisineCapacityFactors = map[string]float64{
+	"Cafe":        0.7,
+	"Street Food": 0.7,
+	"Homemade":    0.7,
+	"Fast Food":   1.4,
+}
+
+// generateBaseCapacity sizes a venue between the configured capacity bounds.
+// Sizes are log-normal around the middle of the range, so most venues are
+// mid-sized with a few small cafes and large chain kitchens, scaled by
+// cuisineCapacityFactor.
+func generateBaseCapacity(config *models.Config, cuisines []string) int {
+	lo, hi := float64(config.MinCapacity), float64(config.MaxCapacity)
+	if lo < 1 || hi < lo {
+		lo, hi = 10, 50
+	}
+	if config.RestaurantCapacitySpread <= 0 {
+		return int(lo) + rng.Intn(int(hi-lo)+1)
+	}
+
+	size := math.Sqrt(lo*hi) * math.Exp(rng.NormFloat64()*config.RestaurantCapacitySpread) * cuisineCapacityFactor(cuisines)
+	return int(math.Round(math.Max(lo, math.Min(hi, size))))
+}
+
+// cuisineCapacityFactor is the strongest single size factor among a venue's
+// cuisines, so a cuisine listed twice, or two that pull the same way, count
+// only once
+func cuisineCapacityFactor(cuisines []string) float64 {
+	factor := 1.0
+	for _, cuisine := range cuisines {
+		if f, ok := cuisineCapacityFactors[cuisine]; ok && math.Abs(math.Log(f)) > math.Abs(math.Log(factor)) {
+			factor = f
+		}
+	}
+	return factor
+}
+
+// generateAcceptedPaymentMethods decides which payment methods a restaurant
+// takes: some are cashless, some cash only, and the rest take everything
+func generateAcceptedPaymentMethods(config *models.Config) []string {
+	if config.CashlessRestaurantRate <= 0 && config.CashOnlyRestaurantRate <= 0 {
+		return []string{models.PaymentCard, models.PaymentCash, models.PaymentWallet}
+	}
+	roll := rng.Float64()
+	switch {
+	case roll < config.CashlessRestaurantRate:
+		return []string{models.PaymentCard, models.PaymentWallet}
+	case roll < config.CashlessRestaurantRate+config.CashOnlyRestaurantRate:
+		return []string{models.PaymentCash}
+	default:
+		return []string{models.PaymentCard, models.PaymentCash, models.PaymentWallet}
 	}
 }
 
 func generateRandomCuisines() []string {
 	allCuisines := []string{"Italian", "Cafe", "Indian", "American", "European", "Japanese", "Mexican", "Native American", "Carribean", "Contemporary", "Continental", "Chinese", "Thai", "Vietnamese", "Greek", "French", "Mediterranean", "Moroccan", "Fast Food", "Street Food", "Homemade"}
-	cuisineCount := rand.Intn(4) + 1 // 1 to 4 cuisines
+	cuisineCount := rng.Intn(4) + 1 // 1 to 4 cuisines
 	cuisines := make([]string, cuisineCount)
 	for i := 0; i < cuisineCount; i++ {
-		cuisines[i] = allCuisines[rand.Intn(len(allCuisines))]
+		cuisines[i] = allCuisines[rng.Intn(len(allCuisines))]
 	}
 	return cuisines
 }
@@ -0,0 +1,23 @@
+package factories
+
+import "testing"
+
+func TestCuisineCapacityFactorCountsEachCuisineOnce(t *testing.T) {
+	tests := []struct {
+		cuisines []string
+		want     float64
+	}{
+		{[]string{"Italian"}, 1},
+		{[]string{"Fast Food"}, 1.4},
+		{[]string{"Fast Food", "Fast Food"}, 1.4},
+		{[]string{"Cafe", "Street Food"}, 0.7},
+		{[]string{"Burgers", "Fast Food", "Italian"}, 1.4},
+		{[]string{"Fast Food", "Cafe"}, 0.7},
+		{[]string{"Cafe", "Fast Food"}, 0.7},
+	}
+	for _, tt := range tests {
+		if got := cuisineCapacityFactor(tt.cuisines); got != tt.want {
+			t.Errorf("cuisineCapacityFactor(%v) = %v, want %v", tt.cuisines, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package factories
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"github.com/jaswdr/faker"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rng drives all entity generation. It is separate from the simulator's
+// runtime RNG, so reseeding it before each entity group produces the same
+// users, restaurants, menus and partners however the simulation itself runs.
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+var fake = faker.NewWithSeed(rng)
+
+// Seed resets the entity generator to a stream derived from seed and group
+// (e.g. "users" or "restaurants"), so each group is reproducible on its own
+// and changing how many of one entity is generated doesn't shift the others.
+// A zero seed picks a random stream.
+func Seed(seed int64, group string) {
+	rng = groupRand(seed, group)
+	fake = faker.NewWithSeed(rng)
+}
+
+func groupRand(seed int64, group string) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	h := fnv.New64a()
+	h.Write([]byte(group))
+	return rand.New(rand.NewSource(seed ^ int64(h.Sum64())))
+}
+
+// streamMu stops two streams generating at once, as both draw through the
+// package's generator
+var streamMu sync.Mutex
+
+// Stream is an entity stream kept apart from the up-front groups, for
+// entities generated while a simulation runs
+type Stream struct {
+	rng  *rand.Rand
+	fake faker.Faker
+}
+
+// NewStream returns a stream derived from seed and group in the same way as
+// Seed. A zero seed picks a random stream.
+func NewStream(seed int64, group string) *Stream {
+	r := groupRand(seed, group)
+	return &Stream{rng: r, fake: faker.NewWithSeed(r)}
+}
+
+// Generate runs fn with the factories drawing from the stream, then puts the
+// package's generator back as it was
+func (s *Stream) Generate(fn func()) {
+	streamMu.Lock()
+	defer streamMu.Unlock()
+	savedRng, savedFake := rng, fake
+	rng, fake = s.rng, s.fake
+	defer func() { rng, fake = savedRng, savedFake }()
+	fn()
+}
+
+const idAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// newID returns a cuid-style identifier drawn from the entity RNG, so
+// reseeded runs also reproduce entity IDs
+func newID() string {
+	var b strings.Builder
+	b.Grow(25)
+	b.WriteByte('c')
+	for i := 0; i < 24; i++ {
+		b.WriteByte(idAlphabet[rng.Intn(len(idAlphabet))])
+	}
+	return b.String()
+}
+
+// MenuSize returns how many items to generate for the menu of a restaurant
+// serving cuisines, uniformly within its configured menu size range
+func MenuSize(config *models.Config, cuisines []string) int {
+	r := config.MenuSizeFor(cuisines)
+	return r.Min + rng.Intn(r.Max-r.Min+1)
+}
@@ -0,0 +1,76 @@
+package factories
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestMenuSizeFollowsConfiguredRange(t *testing.T) {
+	Seed(42, "menus")
+	config := &models.Config{
+		MenuSizeMin: 12,
+		MenuSizeMax: 18,
+		CuisineMenuSizes: map[string]models.MenuSizeRange{
+			"fine dining": {Min: 5, Max: 8},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		cuisines []string
+		min, max int
+	}{
+		{"default range", []string{"pizza"}, 12, 18},
+		{"cuisine range", []string{"Fine Dining", "french"}, 5, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const menus = 5000
+			counts := make(map[int]int)
+			total := 0
+			for i := 0; i < menus; i++ {
+				n := MenuSize(config, tt.cuisines)
+				if n < tt.min || n > tt.max {
+					t.Fatalf("menu size %d outside [%d, %d]", n, tt.min, tt.max)
+				}
+				counts[n]++
+				total += n
+			}
+			if len(counts) != tt.max-tt.min+1 {
+				t.Errorf("saw %d distinct sizes, want every size from %d to %d", len(counts), tt.min, tt.max)
+			}
+			mean := float64(total) / menus
+			if want := float64(tt.min+tt.max) / 2; math.Abs(mean-want) > 0.15 {
+				t.Errorf("mean menu size %.2f, want about %.1f for a uniform range", mean, want)
+			}
+		})
+	}
+}
+
+func TestStreamLeavesEntityGroupsAlone(t *testing.T) {
+	config := &models.Config{CityLat: 53.0, CityLon: -2.18, UrbanRadius: 10}
+	factory := &DeliveryPartnerFactory{}
+
+	Seed(42, "partners")
+	first := factory.CreateDeliveryPartner(config)
+	want := factory.CreateDeliveryPartner(config)
+
+	Seed(42, "partners")
+	factory.CreateDeliveryPartner(config)
+	var grown *models.DeliveryPartner
+	NewStream(42, "growth").Generate(func() { grown = factory.CreateDeliveryPartner(config) })
+	if got := factory.CreateDeliveryPartner(config); got.ID != want.ID {
+		t.Errorf("partner after growth is %s, want %s as if nothing had grown", got.ID, want.ID)
+	}
+	if grown.ID == first.ID || grown.ID == want.ID {
+		t.Errorf("grown partner %s repeats one from the partners group", grown.ID)
+	}
+
+	var again *models.DeliveryPartner
+	NewStream(42, "growth").Generate(func() { again = factory.CreateDeliveryPartner(config) })
+	if again.ID != grown.ID {
+		t.Errorf("same growth stream made %s then %s, want the same partner", grown.ID, again.ID)
+	}
+}
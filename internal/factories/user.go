@@ -2,14 +2,9 @@ package factories
 
 import (
 	"github.com/chrisdamba/foodatasim/internal/models"
-	"github.com/jaswdr/faker"
-	"github.com/lucsky/cuid"
 	"math"
-	"math/rand"
 )
 
-var fake = faker.New()
-
 type UserFactory struct{}
 
 func (uf *UserFactory) CreateUser(config *models.Config) *models.User {
@@ -18,15 +13,15 @@ func (uf *UserFactory) CreateUser(config *models.Config) *models.User {
 	lonRange := latRange / math.Cos(config.CityLat*math.Pi/180.0)
 
 	// generate random offsets within the urban radius
-	latOffset := (rand.Float64()*2 - 1) * latRange
-	lonOffset := (rand.Float64()*2 - 1) * lonRange
+	latOffset := (rng.Float64()*2 - 1) * latRange
+	lonOffset := (rng.Float64()*2 - 1) * lonRange
 
 	// calculate final latitude and longitude
 	lat := config.CityLat + latOffset
 	lon := config.CityLon + lonOffset
 
 	return &models.User{
-		ID:       cuid.New(),
+		ID:       newID(),
 		Name:     fake.Person().Name(),
 		JoinDate: fake.Time().TimeBetween(config.StartDate.AddDate(-1, 0, 0), config.StartDate),
 		Location: models.Location{
@@ -36,15 +31,16 @@ func (uf *UserFactory) CreateUser(config *models.Config) *models.User {
 		Preferences:         generateRandomPreferences(),
 		DietaryRestrictions: generateRandomDietaryRestrictions(),
 		OrderFrequency:      fake.Float64(2, 50, 100) / 100 * config.OrderFrequency,
+		HardToFindAddress:   rng.Float64() < 0.1,
 	}
 }
 
 func generateRandomPreferences() []string {
 	allCuisines := []string{"Italian", "Indian", "Chinese", "Mexican", "Japanese", "Thai", "American", "French", "Greek", "Spanish", "Pizza", "Curry", "Burgers", "Sushi", "Tacos", "Pasta", "Salad", "Steak", "Seafood"}
-	prefCount := rand.Intn(3) + 1 // 1 to 3 preferences
+	prefCount := rng.Intn(3) + 1 // 1 to 3 preferences
 	preferences := make([]string, prefCount)
 	for i := 0; i < prefCount; i++ {
-		preferences[i] = allCuisines[rand.Intn(len(allCuisines))]
+		preferences[i] = allCuisines[rng.Intn(len(allCuisines))]
 	}
 	return preferences
 }
@@ -55,5 +51,5 @@ func generateRandomDietaryRestrictions() []string {
 	if restrictCount == 0 {
 		return nil
 	}
-	return []string{restrictions[rand.Intn(len(restrictions))]}
+	return []string{restrictions[rng.Intn(len(restrictions))]}
 }
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	AcceptanceStatusAccepted     = "accepted"
+	AcceptanceStatusAutoRejected = "auto_rejected"
+)
+
+// DefaultAcceptanceTime is the median time a restaurant takes to accept an
+// order when none of its cuisines has an acceptance time of its own
+const DefaultAcceptanceTime = 90 * time.Second
+
+// DefaultAcceptanceTimes is the median time restaurants take to accept an
+// order, keyed by lower-case cuisine name: counter-service kitchens accept in
+// seconds, kitchens that run a pass take minutes
+var DefaultAcceptanceTimes = map[string]time.Duration{
+	"fast food":    15 * time.Second,
+	"street food":  30 * time.Second,
+	"cafe":         45 * time.Second,
+	"contemporary": 3 * time.Minute,
+	"continental":  3 * time.Minute,
+	"french":       4 * time.Minute,
+}
+
+// OrderAcceptance is a restaurant responding to a new order, or the platform
+// auto-rejecting it when the restaurant doesn't respond within the SLA
+type OrderAcceptance struct {
+	OrderID      string
+	CustomerID   string
+	RestaurantID string
+	PlacedAt     time.Time
+	Latency      time.Duration // Time the restaurant took, or would have taken, to accept
+	SLA          time.Duration
+	Rejected     bool // The SLA passed first and the platform rejected the order
+}
+
+// AcceptanceTime returns the median acceptance time for a restaurant serving
+// cuisines: the slowest of its cuisines, preferring the configured times over
+// the built-in defaults
+func (cfg *Config) AcceptanceTime(cuisines []string) time.Duration {
+	var median time.Duration
+	for _, cuisine := range cuisines {
+		cuisine = strings.ToLower(cuisine)
+		t, ok := cfg.AcceptanceTimes[cuisine]
+		if !ok {
+			t, ok = DefaultAcceptanceTimes[cuisine]
+		}
+		if ok && t > median {
+			median = t
+		}
+	}
+	if median == 0 {
+		return DefaultAcceptanceTime
+	}
+	return median
+}
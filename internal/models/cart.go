@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AbandonedCart is a basket a user built at a restaurant but never checked out
+type AbandonedCart struct {
+	ID           string
+	UserID       string
+	RestaurantID string
+	Items        []string
+	Subtotal     float64
+	DeliveryFee  float64
+	Reason       string // One of the AbandonReason constants
+	AbandonedAt  time.Time
+}
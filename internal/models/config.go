@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -23,10 +24,14 @@ type MenuDish struct {
 }
 
 type CloudStorageConfig struct {
-	Provider      string `mapstructure:"provider"`
-	BucketName    string `mapstructure:"bucket_name"`
-	ContainerName string `mapstructure:"container_name"`
-	Region        string `mapstructure:"region"`
+	Provider        string `mapstructure:"provider"`
+	BucketName      string `mapstructure:"bucket_name"`
+	ContainerName   string `mapstructure:"container_name"`
+	Region          string `mapstructure:"region"`
+	Prefix          string `mapstructure:"prefix"`            // Object key prefix for all output files
+	Endpoint        string `mapstructure:"endpoint"`          // Custom endpoint for S3-compatible storage
+	AccessKeyID     string `mapstructure:"access_key_id"`     // Static credentials; S3 falls back to the default AWS credential chain
+	SecretAccessKey string `mapstructure:"secret_access_key"` // For GCS these are HMAC keys
 }
 
 type DatabaseConfig struct {
@@ -40,6 +45,7 @@ type DatabaseConfig struct {
 
 type Config struct {
 	Seed                  int                `mapstructure:"seed"`
+	EntitySeed            int64              `mapstructure:"entity_seed"` // Seed for generating users, restaurants, menus and partners, derived from seed when 0
 	StartDate             time.Time          `mapstructure:"start_date"`
 	EndDate               time.Time          `mapstructure:"end_date"`
 	InitialUsers          int                `mapstructure:"initial_users"`
@@ -64,16 +70,67 @@ type Config struct {
 	OutputFolder          string             `mapstructure:"output_folder"`
 	Continuous            bool               `mapstructure:"continuous"`
 	OutputDestination     string             `mapstructure:"output_destination"`
-	OutputTypes           []string           `mapstructure:"output_types"` // e.g. ["parquet", "postgres"
+	OutputTypes           []string           `mapstructure:"output_types"`       // e.g. ["parquet", "postgres"
+	OutputCompression     string             `mapstructure:"output_compression"` // "none" (default) or "gzip", applies to JSON and CSV files
+	OutputMode            string             `mapstructure:"output_mode"`        // "truncate" (default) replaces existing output files, "append" adds to them
 	Database              DatabaseConfig     `mapstructure:"database"`
 	CloudStorage          CloudStorageConfig `mapstructure:"cloud_storage"`
+
+	CuisineDietaryTags map[string]map[string]float64 `mapstructure:"cuisine_dietary_tags"` // Chance a menu item carries each dietary tag, per cuisine; overrides the defaults
+
+	TrafficSpeedImpact float64 `mapstructure:"traffic_speed_impact"` // Fraction of partner speed lost at full traffic density, 0 ignores traffic
+
+	MaxDeliveryRadius  float64 `mapstructure:"max_delivery_radius"`  // Furthest a customer searches for restaurants, in km
+	DistanceReportPath string  `mapstructure:"distance_report_path"` // Where to write the end-of-run delivery distance report as JSON, empty only logs it
+
+	QueueDepthLogInterval      time.Duration `mapstructure:"queue_depth_log_interval"`      // How often, in simulated time, to log the event queue depth; 0 disables
+	QueueDepthWarningThreshold int           `mapstructure:"queue_depth_warning_threshold"` // Warn when the event queue holds more events than this, 0 disables
+
+	FeaturedDishDailyRate float64       `mapstructure:"featured_dish_daily_rate"` // Probability per restaurant per day of starting to feature a dish, 0 disables
+	FeaturedDishDuration  time.Duration `mapstructure:"featured_dish_duration"`   // How long a dish stays featured
+	FeaturedDishBoost     float64       `mapstructure:"featured_dish_boost"`      // Popularity multiplier for the featured dish
+
+	HomepageFeatureSlots     int                       `mapstructure:"homepage_feature_slots"`      // Restaurants featured on the homepage at once, picked at random; 0 disables random featuring
+	HomepageFeatureDailyRate float64                   `mapstructure:"homepage_feature_daily_rate"` // Chance per day that an empty slot is filled
+	HomepageFeatureDuration  time.Duration             `mapstructure:"homepage_feature_duration"`   // How long a randomly picked restaurant stays featured
+	HomepageFeatureBoost     float64                   `mapstructure:"homepage_feature_boost"`      // Selection score multiplier for featured restaurants
+	HomepageFeatures         []HomepageFeatureSchedule `mapstructure:"homepage_features"`           // Restaurants to feature at set times, on top of the random slots
+
+	ComplaintRate       float64            `mapstructure:"complaint_rate"`       // Base probability a delivered order gets a support ticket, raised for late, large or poorly rated orders; 0 disables
+	ComplaintCategories map[string]float64 `mapstructure:"complaint_categories"` // Relative share of each complaint category, overrides the defaults
+
+	FragilityComplaintMultiplier float64 `mapstructure:"fragility_complaint_multiplier"` // Complaint odds multiplier for the most fragile orders, scaled down for sturdier ones; 1 disables
+	FragilityHandlingMinutes     float64 `mapstructure:"fragility_handling_minutes"`     // Extra handling time at the door for the most fragile orders
+	SpillRate                    float64 `mapstructure:"spill_rate"`                     // Chance the most fragile orders with drinks arrive spilled
+
+	WeatherObservationInterval time.Duration `mapstructure:"weather_observation_interval"` // How often to emit a standalone weather observation, 0 disables
+
+	WeatherRegions []WeatherRegion `mapstructure:"weather_regions"` // Parts of the city with their own weather, empty for one city-wide weather
+	Climate        string          `mapstructure:"climate"`         // Climate the temperature curve follows, e.g. "mediterranean"; empty uses a generic temperate curve
+
+	CashlessRestaurantRate float64 `mapstructure:"cashless_restaurant_rate"`  // Share of restaurants that only take card and wallet payments
+	CashOnlyRestaurantRate float64 `mapstructure:"cash_only_restaurant_rate"` // Share of restaurants that only take cash
+
+	DeliveryInstructions []DeliveryInstruction `mapstructure:"delivery_instructions"` // Drop-off instruction types with their probability and average delay, none by default
+
+	PrepProgressInterval time.Duration `mapstructure:"prep_progress_interval"` // How often to emit prep progress while an order is being prepared, 0 disables
+
+	RestaurantRatingDistribution RatingDistribution `mapstructure:"restaurant_rating_distribution"` // Initial restaurant ratings, uniform 1–5 when unset
+
+	TipProbability        float64 `mapstructure:"tip_probability"`         // Chance a customer adds a tip at checkout
+	PartnerRatesCustomers bool    `mapstructure:"partner_rates_customers"` // Partners rate the customer on delivery
+
+	AbandonedCartRate float64 `mapstructure:"abandoned_cart_rate"` // Base chance a user drops out at checkout, 0 disables abandoned carts
+
+	Units string `mapstructure:"units"` // Units of distances, speeds and temperatures in emitted events: "metric" (default) or "imperial"
+
 	// Additional fields
 	CityName              string        `mapstructure:"city_name"`
 	DefaultCurrency       int           `mapstructure:"default_currency"`
 	MinPrepTime           int           `mapstructure:"min_prep_time"`
 	MaxPrepTime           int           `mapstructure:"max_prep_time"`
-	MinRating             float64       `mapstructure:"min_rating"`
-	MaxRating             float64       `mapstructure:"max_rating"`
+	MinRating             float64       `mapstructure:"min_rating"` // Bottom of the rating scale used in emitted reviews
+	MaxRating             float64       `mapstructure:"max_rating"` // Top of the rating scale used in emitted reviews
 	MaxInitialRatings     float64       `mapstructure:"max_initial_ratings"`
 	MinEfficiency         float64       `mapstructure:"min_efficiency"`
 	MaxEfficiency         float64       `mapstructure:"max_efficiency"`
@@ -94,6 +151,46 @@ type Config struct {
 	ReviewData            []ReviewData  `mapstructure:"review_data"`
 	MenuDishes            []MenuDish    `mapstructure:"menu_dishes"`
 
+	CuisinePriceRanges    map[string]PriceRange `mapstructure:"cuisine_price_ranges"`    // Menu item price range per cuisine, overrides the defaults
+	CuisineDemandProfiles map[string][]float64  `mapstructure:"cuisine_demand_profiles"` // 24 hourly demand multipliers per cuisine, overrides the defaults
+	DeliveryFeeTiers      []DeliveryFeeTier     `mapstructure:"delivery_fee_tiers"`      // Distance surcharges on top of the base delivery fee
+
+	SeasonalCuisineModifiers map[string]map[string]float64 `mapstructure:"seasonal_cuisine_modifiers"` // Appeal multiplier per season and cuisine; a configured season replaces that season's defaults
+	SeasonalCuisineStrength  float64                       `mapstructure:"seasonal_cuisine_strength"`  // Scales how far the modifiers move from 1; 0 disables seasonal cuisine shifts
+
+	// Local competition, applied to menu prices when restaurants are created
+	CompetitionRadius           float64 `mapstructure:"competition_radius"`            // Distance in km within which restaurants compete
+	CompetitionCuisineOverlap   string  `mapstructure:"competition_cuisine_overlap"`   // Which nearby restaurants compete: "shared" cuisine (default), "primary" cuisine or "any"
+	CompetitionPricePressure    float64 `mapstructure:"competition_price_pressure"`    // Price change per competitor per square km away from the reference density, 0 disables
+	CompetitionReferenceDensity float64 `mapstructure:"competition_reference_density"` // Competitors per square km at which prices are unchanged
+
+	// Demand response to competitors' prices, applied when customers pick a restaurant
+	CrossPriceSensitivity float64 `mapstructure:"cross_price_sensitivity"` // Elasticity of a restaurant's share of orders to its menu price relative to its competitors, 0 disables
+
+	MinOrderSubtotal float64 `mapstructure:"min_order_subtotal"` // Minimum item subtotal, smaller baskets are topped up; 0 disables
+	MaxOrderSubtotal float64 `mapstructure:"max_order_subtotal"` // Maximum item subtotal, larger baskets are trimmed; 0 disables
+
+	DeliveryBehaviourWeight float64 `mapstructure:"delivery_behaviour_weight"` // Weight of partner behaviour vs timing in delivery ratings, 0-1
+
+	ReviewEditProbability float64       `mapstructure:"review_edit_probability"` // Chance a posted review is later edited
+	ReviewEditWindow      time.Duration `mapstructure:"review_edit_window"`      // How long after posting a review can be edited
+
+	ReviewPriceSentimentStrength float64 `mapstructure:"review_price_sentiment_strength"` // Max stars price expectations shift a food rating by, 0 disables
+
+	MinReviewWords       int     `mapstructure:"min_review_words"`        // Fewest words in a review comment, 0 allows any length
+	RatingOnlyReviewRate float64 `mapstructure:"rating_only_review_rate"` // Share of reviews left without a comment
+
+	MaxEvents int `mapstructure:"max_events"` // Stop after dispatching this many events, 0 runs to the end date
+	Workers   int `mapstructure:"workers"`    // Number of output workers, defaults to the CPU count; 1 writes every event in dispatch order
+
+	NewRestaurantBoost        float64 `mapstructure:"new_restaurant_boost"`         // Extra selection score for newly opened restaurants, 0 disables
+	NewRestaurantBoostDays    int     `mapstructure:"new_restaurant_boost_days"`    // How many days after opening the boost lasts
+	NewRestaurantBoostRatings float64 `mapstructure:"new_restaurant_boost_ratings"` // Ratings after which the boost has fully faded
+
+	RestaurantMetricsInterval time.Duration `mapstructure:"restaurant_metrics_interval"` // How often restaurant_metrics_events are emitted, 0 disables
+
+	RatingRecomputeInterval time.Duration `mapstructure:"rating_recompute_interval"` // How often ratings are rebuilt from all reviews, 0 disables
+
 	NearLocationThreshold float64 `mapstructure:"near_location_threshold"`
 	CityLat               float64 `mapstructure:"city_latitude"`
 	CityLon               float64 `mapstructure:"city_longitude"`
@@ -104,6 +201,186 @@ type Config struct {
 	UserBehaviourWindow   int     `mapstructure:"user_behaviour_window"` // Number of orders to consider for adjusting frequency
 	RestaurantLoadFactor  float64 `mapstructure:"restaurant_load_factor"`
 	EfficiencyAdjustRate  float64 `mapstructure:"efficiency_adjust_rate"`
+
+	Hotspots         []Hotspot `mapstructure:"hotspots"`          // Demand hotspots, defaults to five around the city centre
+	PartnerPlacement string    `mapstructure:"partner_placement"` // Where partners start: "uniform" (default), "demand" (near hotspots) or "home" (each shift from home)
+
+	PartnerDeclineRate        float64       `mapstructure:"partner_decline_rate"`         // Chance a partner declines an offered order
+	PartnerShiftLength        time.Duration `mapstructure:"partner_shift_length"`         // Length of a partner shift for shift summaries, 0 disables them
+	PartnerShiftSummaryFields []string      `mapstructure:"partner_shift_summary_fields"` // Fields to include in shift summaries, empty includes all
+
+	// Partner pay, settled at the end of each shift
+	PartnerPayModel       string  `mapstructure:"partner_pay_model"`        // "per_delivery", "hourly" or "hybrid" (per-delivery pay topped up to an hourly floor)
+	PartnerPerDeliveryPay float64 `mapstructure:"partner_per_delivery_pay"` // Flat pay per delivery
+	PartnerPerKmPay       float64 `mapstructure:"partner_per_km_pay"`       // Pay per km from restaurant to customer
+	PartnerHourlyRate     float64 `mapstructure:"partner_hourly_rate"`      // Hourly pay under the hourly model
+	PartnerHourlyFloor    float64 `mapstructure:"partner_hourly_floor"`     // Minimum hourly earnings under the hybrid model
+
+	OutageRate          float64       `mapstructure:"outage_rate"`            // Platform-wide outages per simulated day, 0 disables
+	OutageDuration      time.Duration `mapstructure:"outage_duration"`        // Average length of an outage
+	OutageCatchUpShare  float64       `mapstructure:"outage_catch_up_share"`  // Share of customers who couldn't order during an outage who try again after it; 0 disables the catch-up surge
+	OutageCatchUpWindow time.Duration `mapstructure:"outage_catch_up_window"` // How long after an outage the catch-up surge lasts
+
+	ReorderRate               float64            `mapstructure:"reorder_rate"`                // Chance a regular customer repeats a recent order instead of browsing, 0 disables
+	ReorderSegmentMultipliers map[string]float64 `mapstructure:"reorder_segment_multipliers"` // Scales reorder_rate per user segment
+
+	FeeRegions             []FeeRegion            `mapstructure:"fee_regions"`              // Jurisdictions with their own tax and service fee rates
+	RestaurantFeeOverrides map[string]FeeOverride `mapstructure:"restaurant_fee_overrides"` // Tax and service fee rates per restaurant ID, over any region's
+
+	ReviewLinks           bool `mapstructure:"review_links"`            // Emit a link from each order to its review when the review is written
+	ReviewLinksUnreviewed bool `mapstructure:"review_links_unreviewed"` // Also write a link with no review for every order still unreviewed at the end of the run
+
+	DoorDwellTime            time.Duration `mapstructure:"door_dwell_time"`             // Average time at the customer's door finding it and handing over, 0 disables
+	DoorDwellSpread          float64       `mapstructure:"door_dwell_spread"`           // Log-normal spread of door dwell times
+	DoorDwellUrbanMultiplier float64       `mapstructure:"door_dwell_urban_multiplier"` // Door dwell time multiplier for flats and addresses in the urban core
+
+	ViralityRate      float64       `mapstructure:"virality_rate"`      // Chance per day that a random restaurant goes viral, 0 disables
+	ViralityMagnitude float64       `mapstructure:"virality_magnitude"` // Selection score multiplier of a restaurant that just went viral
+	ViralityHalfLife  time.Duration `mapstructure:"virality_half_life"` // How long a viral restaurant's extra demand takes to halve
+
+	PrepStations int `mapstructure:"prep_stations"` // Orders a median-sized kitchen prepares at once before new ones queue, scaled by venue size; 0 keeps the load-based slowdown
+
+	PartnerStatusPings bool `mapstructure:"partner_status_pings"` // Emit a partner location ping at every assignment, pickup and delivery
+
+	OrderTraceIDs bool `mapstructure:"order_trace_ids"` // Tag every event about an order with a traceId shared by all of them
+
+	CuisineRatingOffsets map[string]float64 `mapstructure:"cuisine_rating_offsets"` // Stars added to food ratings per cuisine, e.g. positive for desserts
+
+	SettlementEvents         bool    `mapstructure:"settlement_events"`          // Emit a settlement for every delivered order
+	RestaurantCommissionRate float64 `mapstructure:"restaurant_commission_rate"` // Share of each order's subtotal the platform keeps as commission
+
+	WeatherConditionLabels map[string]string `mapstructure:"weather_condition_labels"` // Name each generated weather condition is emitted under, to match a downstream taxonomy
+
+	PartnerDualAppRate     float64       `mapstructure:"partner_dual_app_rate"`     // Chance per hour an idle partner goes off to a job from another app, 0 disables
+	PartnerDualAppDuration time.Duration `mapstructure:"partner_dual_app_duration"` // Average time a partner is away on another app's job
+
+	ETADisplayBucket time.Duration `mapstructure:"eta_display_bucket"` // Rounding step of the delivery estimate range shown to customers, 0 disables the range
+	ETADisplayWidth  time.Duration `mapstructure:"eta_display_width"`  // Width of the displayed delivery estimate range
+
+	MenuSizeMin      int                      `mapstructure:"menu_size_min"`      // Fewest items on a generated menu
+	MenuSizeMax      int                      `mapstructure:"menu_size_max"`      // Most items on a generated menu
+	CuisineMenuSizes map[string]MenuSizeRange `mapstructure:"cuisine_menu_sizes"` // Menu size range per cuisine, overriding menu_size_min and menu_size_max
+
+	TargetOrders      int    `mapstructure:"target_orders"`      // Orders the run should place in total; initial_users or order_frequency is scaled to match, 0 disables
+	DemandCalibration string `mapstructure:"demand_calibration"` // What target_orders scales: "users" (initial_users) or "frequency" (order_frequency)
+
+	PIIScrubFields       []string `mapstructure:"pii_scrub_fields"`       // Identifying fields to scrub from emitted events: "address", "delivery_note", "comment", "restaurant_name", "location"; empty disables
+	PIIScrubMode         string   `mapstructure:"pii_scrub_mode"`         // How scrubbed text is replaced: "hash" (stable) or "randomize"
+	PIILocationPrecision int      `mapstructure:"pii_location_precision"` // Decimal places scrubbed coordinates are rounded to
+
+	PartnerHomeRadius float64 `mapstructure:"partner_home_radius"` // Distance in km from the city centre partners' homes are spread over, 0 uses urban_radius
+
+	PartnerAcceptanceRadius float64 `mapstructure:"partner_acceptance_radius"` // Farthest in km a partner will travel to a restaurant to accept an order, on top of near_location_threshold; 0 disables
+
+	OrderPlacementSmoothing bool `mapstructure:"order_placement_smoothing"` // Spread order placements across each time step instead of placing them all at its start
+
+	ReviewRatingBias map[string]ReviewRatingBias `mapstructure:"review_rating_bias"` // Food rating lean per user segment, for calibrating rating distributions
+
+	PrepTimeLearningRate   float64 `mapstructure:"prep_time_learning_rate"`   // How fast a restaurant's average prep time moves toward its realized prep times each step, 0 disables
+	PrepTimeLearningWindow int     `mapstructure:"prep_time_learning_window"` // Number of recent orders per restaurant the realized prep time is averaged over
+
+	ETAExperienceCalibration bool               `mapstructure:"eta_experience_calibration"` // Stretch delivery estimates for less experienced partners
+	ETAExperienceBias        map[string]float64 `mapstructure:"eta_experience_bias"`        // Estimate multiplier per partner experience tier, overriding the defaults
+
+	ItemRatingRate        float64 `mapstructure:"item_rating_rate"`        // Share of reviews that rate the food which also rate each dish
+	ItemRatingCorrelation float64 `mapstructure:"item_rating_correlation"` // How closely dish ratings follow the review's food rating, from 0 to 1
+
+	ReviewFoodOnlyRate     float64 `mapstructure:"review_food_only_rate"`     // Share of reviews with a food rating but no delivery rating
+	ReviewDeliveryOnlyRate float64 `mapstructure:"review_delivery_only_rate"` // Share of reviews with a delivery rating but no food rating
+
+	AcceptanceSLA        time.Duration            `mapstructure:"acceptance_sla"`         // Time a restaurant has to accept an order before the platform rejects it; 0 disables acceptance modelling
+	AcceptanceTimes      map[string]time.Duration `mapstructure:"acceptance_times"`       // Median acceptance time per cuisine, overriding the built-in defaults
+	AcceptanceTimeSpread float64                  `mapstructure:"acceptance_time_spread"` // Log-normal spread of acceptance times around the median
+
+	GPSNoiseMeters float64       `mapstructure:"gps_noise_meters"` // Standard deviation of the position error in emitted partner locations, 0 keeps them exact
+	GPSDropoutRate float64       `mapstructure:"gps_dropout_rate"` // Share of partner location pings that are never emitted
+	GPSStaleRate   float64       `mapstructure:"gps_stale_rate"`   // Share of partner location pings that repeat the previous reported position
+	GPSClockSkew   time.Duration `mapstructure:"gps_clock_skew"`   // Standard deviation of the device clock error on emitted update times
+
+	PartnerCashLimit        float64       `mapstructure:"partner_cash_limit"`         // Most cash a partner may carry; cash orders that would exceed it go to other partners. 0 disables
+	PartnerCashDropShare    float64       `mapstructure:"partner_cash_drop_share"`    // Share of the cash limit at which an idle partner goes to drop off their cash
+	PartnerCashDropDuration time.Duration `mapstructure:"partner_cash_drop_duration"` // Time a partner spends offline dropping off cash
+
+	Timezone             string   `mapstructure:"timezone"`               // IANA time zone of the city, used for local day boundaries
+	DailySummaryEntities []string `mapstructure:"daily_summary_entities"` // Entities to emit daily summaries for: "restaurants", "partners"; empty disables
+
+	LocalTimeDemand bool `mapstructure:"local_time_demand"` // Drive time-of-day, weekday and seasonal patterns from the city's local clock rather than UTC
+
+	ItemPrepTimeWeight float64 `mapstructure:"item_prep_time_weight"` // How much an order's prep time follows its items' own prep times rather than the restaurant average, 0 to 1
+
+	RestaurantOfflineRate     float64       `mapstructure:"restaurant_offline_rate"`     // Chance per hour an open restaurant goes offline, raised in extreme weather and when over capacity; 0 disables
+	RestaurantOfflineDuration time.Duration `mapstructure:"restaurant_offline_duration"` // Average time a restaurant stays offline
+
+	RestaurantCapacitySpread float64 `mapstructure:"restaurant_capacity_spread"` // Log-normal spread of venue size between min_capacity and max_capacity, 0 draws uniformly
+
+	PrepTimeOutlierRate      float64 `mapstructure:"prep_time_outlier_rate"`       // Share of orders whose preparation runs far over, 0 disables
+	PrepTimeOutlierShape     float64 `mapstructure:"prep_time_outlier_shape"`      // Pareto shape of outlier slowdowns; lower gives a heavier tail
+	PrepTimeOutlierMaxFactor float64 `mapstructure:"prep_time_outlier_max_factor"` // Largest outlier slowdown, as a multiple of the usual prep time
+
+	// Order lifecycle timeouts
+	OrderMaxPrepTime        time.Duration `mapstructure:"order_max_prep_time"`       // Longest an order can take to prepare
+	PickupTimeout           time.Duration `mapstructure:"pickup_timeout"`            // How long after an order is ready to check it has been picked up
+	InTransitCheckInterval  time.Duration `mapstructure:"in_transit_check_interval"` // How often to check on an order in transit
+	StaleOrderTimeout       time.Duration `mapstructure:"stale_order_timeout"`       // Orders still open this long after being placed are cancelled
+	AssignmentRetryInterval time.Duration `mapstructure:"assignment_retry_interval"` // How long to wait before offering an unassigned order again
+
+	TopicRateLimits map[string]float64 `mapstructure:"topic_rate_limits"` // Maximum messages per second per Kafka topic
+	ThrottleMode    string             `mapstructure:"throttle_mode"`     // What to do with messages over a topic's limit: "buffer" (default) or "drop"
+
+	PartnerGhostRate float64 `mapstructure:"partner_ghost_rate"` // Chance a partner accepts an order then never shows up
+
+	// User notifications about their orders
+	NotificationsEnabled bool               `mapstructure:"notifications_enabled"`
+	NotificationTypes    []string           `mapstructure:"notification_types"`    // Order updates users are notified of, defaults to all of them
+	NotificationChannels map[string]float64 `mapstructure:"notification_channels"` // Share of notifications sent by "push", "sms" and "email"
+	ReviewReminderDelay  time.Duration      `mapstructure:"review_reminder_delay"` // How long after delivery an unreviewed order gets a review reminder
+	ReviewReminderLift   float64            `mapstructure:"review_reminder_lift"`  // Chance an order still unreviewed when reminded then gets a review
+
+	RefundSettlementDelays map[string]time.Duration `mapstructure:"refund_settlement_delays"` // How long refunds take to settle per payment method
+
+	PartnerDeactivationRating     float64 `mapstructure:"partner_deactivation_rating"`      // Rating below which a partner is deactivated, 0 disables
+	PartnerDeactivationMinRatings int     `mapstructure:"partner_deactivation_min_ratings"` // Ratings a partner needs before they can be deactivated
+
+	EnrichOrderEvents bool `mapstructure:"enrich_order_events"` // Attach restaurant name, cuisines and user segment to order placed events
+
+	FeeElasticity             map[string]float64 `mapstructure:"fee_elasticity"`               // Price elasticity of demand to delivery fees per user segment: "occasional", "regular", "frequent"; empty disables
+	FeeElasticityReferenceFee float64            `mapstructure:"fee_elasticity_reference_fee"` // Delivery fee at which demand is unaffected
+
+	UpsellRate              float64            `mapstructure:"upsell_rate"`               // Share of checkouts shown an add-on suggestion, 0 disables
+	UpsellTypes             map[string]float64 `mapstructure:"upsell_types"`              // Acceptance rate per suggested menu item type, e.g. "dessert", "drink"; overrides the defaults
+	UpsellSegmentAcceptance map[string]float64 `mapstructure:"upsell_segment_acceptance"` // Acceptance multiplier per user segment, overrides the defaults
+
+	MultiRestaurantOrdersEnabled    bool    `mapstructure:"multi_restaurant_orders_enabled"`    // Allow baskets with items from two restaurants
+	MultiRestaurantOrderProbability float64 `mapstructure:"multi_restaurant_order_probability"` // Chance an order adds items from a second restaurant
+	MultiRestaurantMaxDistance      float64 `mapstructure:"multi_restaurant_max_distance"`      // Furthest apart, in km, the two restaurants can be
+
+	ReviewTemplatesFile string           `mapstructure:"review_templates_file"` // JSON, YAML or TOML file of review text templates, empty uses the built-in ones
+	ReviewTemplates     *ReviewTemplates `mapstructure:"-"`
+
+	TipPriorityWeight float64 `mapstructure:"tip_priority_weight"` // How strongly partners favour high tip+fee orders, 0 offers orders in turn
+
+	RestaurantCancelRate   float64 `mapstructure:"restaurant_cancel_rate"`    // Chance an ordinary restaurant cancels an order it has accepted
+	BadActorRestaurantRate float64 `mapstructure:"bad_actor_restaurant_rate"` // Share of restaurants that habitually cancel accepted orders
+	BadActorCancelRate     float64 `mapstructure:"bad_actor_cancel_rate"`     // Chance a bad-actor restaurant cancels an accepted order
+
+	PartnerLearningDays          float64 `mapstructure:"partner_learning_days"`           // Tenure over which a new partner closes about two thirds of the gap to full experience, 0 keeps experience fixed
+	PartnerExperienceSpeedImpact float64 `mapstructure:"partner_experience_speed_impact"` // How much slower a brand new partner is than a fully experienced one
+	PartnerNavigationErrorRate   float64 `mapstructure:"partner_navigation_error_rate"`   // Chance a brand new partner loses time finding the drop-off, falling with experience
+
+	// Kitchen degradation ("slow kitchen") incidents
+	KitchenDegradationEnabled        bool    `mapstructure:"kitchen_degradation_enabled"`
+	KitchenDegradationDailyRate      float64 `mapstructure:"kitchen_degradation_daily_rate"`      // Probability per restaurant per day of an incident starting
+	KitchenDegradationMinMinutes     float64 `mapstructure:"kitchen_degradation_min_minutes"`     // Shortest incident
+	KitchenDegradationMaxMinutes     float64 `mapstructure:"kitchen_degradation_max_minutes"`     // Longest incident
+	KitchenDegradationPrepFactor     float64 `mapstructure:"kitchen_degradation_prep_factor"`     // Prep time multiplier at full severity
+	KitchenDegradationCapacityFactor float64 `mapstructure:"kitchen_degradation_capacity_factor"` // Capacity multiplier at full severity
+
+	// Restaurant reputation recovery
+	ReputationRecoveryEnabled bool    `mapstructure:"reputation_recovery_enabled"`
+	ReputationRecoveryRate    float64 `mapstructure:"reputation_recovery_rate"` // Fraction of the gap between recent and historical rating closed per day
+	ReputationRecentWeight    float64 `mapstructure:"reputation_recent_weight"` // Weight of the recent rating window in the displayed rating
+	ReviewResponseRate        float64 `mapstructure:"review_response_rate"`     // Probability a restaurant responds to a negative review
+	ReviewResponseDamping     float64 `mapstructure:"review_response_damping"`  // Fraction of a responded-to review's negative impact that is absorbed
 }
 
 // LoadConfig initializes and reads the configuration using Viper
@@ -129,6 +406,7 @@ func LoadConfig(cfgFile string) (*Config, error) {
 
 	// set default for start time as the current time if not provided
 	viper.SetDefault("start-time", time.Now().Format(time.RFC3339))
+	setDefaults()
 
 	// read in the config file (optional)
 	if err := viper.ReadInConfig(); err != nil {
@@ -149,6 +427,537 @@ func LoadConfig(cfgFile string) (*Config, error) {
 		return nil, fmt.Errorf("unable to decode into struct, %w", err)
 	}
 
+	for season, modifiers := range config.SeasonalCuisineModifiers {
+		switch season {
+		case SeasonWinter, SeasonSpring, SeasonSummer, SeasonAutumn:
+		default:
+			return nil, fmt.Errorf("unknown seasonal_cuisine_modifiers season %q", season)
+		}
+		for cuisine, modifier := range modifiers {
+			if modifier < 0 {
+				return nil, fmt.Errorf("seasonal_cuisine_modifiers.%s.%s must not be negative, got %.2f", season, cuisine, modifier)
+			}
+		}
+	}
+	if config.SeasonalCuisineStrength < 0 {
+		return nil, fmt.Errorf("seasonal_cuisine_strength must not be negative, got %.2f", config.SeasonalCuisineStrength)
+	}
+
+	for cuisine, profile := range config.CuisineDemandProfiles {
+		if len(profile) != 24 {
+			return nil, fmt.Errorf("cuisine_demand_profiles.%s must have 24 hourly values, got %d", cuisine, len(profile))
+		}
+		for _, multiplier := range profile {
+			if multiplier < 0 {
+				return nil, fmt.Errorf("cuisine_demand_profiles.%s must not contain negative values", cuisine)
+			}
+		}
+	}
+
+	for _, tier := range config.DeliveryFeeTiers {
+		if tier.FromKm < 0 || tier.FeePerKm < 0 {
+			return nil, fmt.Errorf("delivery_fee_tiers must have non-negative from_km and fee_per_km")
+		}
+	}
+	config.sortDeliveryFeeTiers()
+
+	if config.MaxOrderSubtotal > 0 && config.MinOrderSubtotal > config.MaxOrderSubtotal {
+		return nil, fmt.Errorf("min_order_subtotal (%.2f) must not exceed max_order_subtotal (%.2f)", config.MinOrderSubtotal, config.MaxOrderSubtotal)
+	}
+
+	switch config.PartnerPlacement {
+	case "", PartnerPlacementUniform, PartnerPlacementDemand, PartnerPlacementHome:
+	default:
+		return nil, fmt.Errorf("unsupported partner placement: %s", config.PartnerPlacement)
+	}
+	for _, region := range config.FeeRegions {
+		if region.RadiusKm <= 0 {
+			return nil, fmt.Errorf("radius_km for fee region %s must be positive, got %.2f", region.Name, region.RadiusKm)
+		}
+		if !region.validRates() {
+			return nil, fmt.Errorf("tax_rate and service_fee_percentage for fee region %s must be between 0 and 1", region.Name)
+		}
+	}
+	for restaurantID, override := range config.RestaurantFeeOverrides {
+		if !override.validRates() {
+			return nil, fmt.Errorf("tax_rate and service_fee_percentage in restaurant_fee_overrides.%s must be between 0 and 1", restaurantID)
+		}
+	}
+	for cuisine, offset := range config.CuisineRatingOffsets {
+		if offset < -4 || offset > 4 {
+			return nil, fmt.Errorf("cuisine_rating_offsets.%s must be between -4 and 4, got %.2f", cuisine, offset)
+		}
+	}
+	if config.ReviewLinksUnreviewed && !config.ReviewLinks {
+		return nil, fmt.Errorf("review_links_unreviewed needs review_links")
+	}
+	if config.DoorDwellTime < 0 || config.DoorDwellSpread < 0 || config.DoorDwellUrbanMultiplier < 0 {
+		return nil, fmt.Errorf("door_dwell_time, door_dwell_spread and door_dwell_urban_multiplier must not be negative")
+	}
+	if config.ViralityRate < 0 {
+		return nil, fmt.Errorf("virality_rate must not be negative, got %.2f", config.ViralityRate)
+	}
+	if config.ViralityRate > 0 && (config.ViralityMagnitude < 1 || config.ViralityHalfLife <= 0) {
+		return nil, fmt.Errorf("virality_magnitude must be at least 1 and virality_half_life positive when virality_rate is set")
+	}
+	if config.PrepStations < 0 {
+		return nil, fmt.Errorf("prep_stations must not be negative, got %d", config.PrepStations)
+	}
+	if config.RestaurantCommissionRate < 0 || config.RestaurantCommissionRate > 1 {
+		return nil, fmt.Errorf("restaurant_commission_rate must be between 0 and 1, got %.2f", config.RestaurantCommissionRate)
+	}
+	for condition, label := range config.WeatherConditionLabels {
+		known := false
+		for _, c := range WeatherConditions {
+			known = known || c == condition
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown weather_condition_labels condition %q, expected one of %v", condition, WeatherConditions)
+		}
+		if label == "" {
+			return nil, fmt.Errorf("weather_condition_labels.%s must not be empty", condition)
+		}
+	}
+	if config.ReviewReminderLift < 0 || config.ReviewReminderLift > 1 {
+		return nil, fmt.Errorf("review_reminder_lift must be between 0 and 1, got %.2f", config.ReviewReminderLift)
+	}
+	if config.PartnerDualAppRate < 0 {
+		return nil, fmt.Errorf("partner_dual_app_rate must not be negative, got %.2f", config.PartnerDualAppRate)
+	}
+	if config.PartnerDualAppRate > 0 && config.PartnerDualAppDuration <= 0 {
+		return nil, fmt.Errorf("partner_dual_app_duration must be positive, got %s", config.PartnerDualAppDuration)
+	}
+	if config.ETADisplayBucket < 0 || config.ETADisplayWidth < 0 {
+		return nil, fmt.Errorf("eta_display_bucket and eta_display_width must not be negative, got %s and %s", config.ETADisplayBucket, config.ETADisplayWidth)
+	}
+	if config.MenuSizeMin < 1 || config.MenuSizeMax < config.MenuSizeMin {
+		return nil, fmt.Errorf("menu_size_min must be at least 1 and no more than menu_size_max, got %d and %d", config.MenuSizeMin, config.MenuSizeMax)
+	}
+	for cuisine, r := range config.CuisineMenuSizes {
+		if r.Min < 1 || r.Max < r.Min {
+			return nil, fmt.Errorf("cuisine_menu_sizes.%s min must be at least 1 and no more than max, got %d and %d", cuisine, r.Min, r.Max)
+		}
+	}
+	if config.TargetOrders < 0 {
+		return nil, fmt.Errorf("target_orders must not be negative, got %d", config.TargetOrders)
+	}
+	if config.DemandCalibration != DemandCalibrationUsers && config.DemandCalibration != DemandCalibrationFrequency {
+		return nil, fmt.Errorf("demand_calibration must be %q or %q, got %q", DemandCalibrationUsers, DemandCalibrationFrequency, config.DemandCalibration)
+	}
+	for _, field := range config.PIIScrubFields {
+		switch field {
+		case PIIFieldAddress, PIIFieldDeliveryNote, PIIFieldComment, PIIFieldRestaurantName, PIIFieldLocation:
+		default:
+			return nil, fmt.Errorf("unknown pii_scrub_fields entry %q", field)
+		}
+	}
+	if config.PIIScrubMode != PIIScrubHash && config.PIIScrubMode != PIIScrubRandomize {
+		return nil, fmt.Errorf("pii_scrub_mode must be %q or %q, got %q", PIIScrubHash, PIIScrubRandomize, config.PIIScrubMode)
+	}
+	if config.PIILocationPrecision < 0 || config.PIILocationPrecision > 6 {
+		return nil, fmt.Errorf("pii_location_precision must be between 0 and 6, got %d", config.PIILocationPrecision)
+	}
+	if config.PartnerHomeRadius < 0 {
+		return nil, fmt.Errorf("partner_home_radius must not be negative, got %.2f", config.PartnerHomeRadius)
+	}
+
+	if dist := config.RestaurantRatingDistribution; dist.Enabled() {
+		if dist.Min == 0 && dist.Max == 0 {
+			config.RestaurantRatingDistribution.Min, config.RestaurantRatingDistribution.Max = 1, 5
+		} else if dist.Min >= dist.Max || dist.Mean < dist.Min || dist.Mean > dist.Max || dist.StdDev < 0 {
+			return nil, fmt.Errorf("invalid restaurant_rating_distribution: mean %.2f must lie within min %.2f and max %.2f, and stddev must not be negative", dist.Mean, dist.Min, dist.Max)
+		}
+	}
+
+	if config.MaxDeliveryRadius < 5 {
+		return nil, fmt.Errorf("max_delivery_radius must be at least 5 km, got %.1f", config.MaxDeliveryRadius)
+	}
+
+	if config.CashlessRestaurantRate < 0 || config.CashOnlyRestaurantRate < 0 || config.CashlessRestaurantRate+config.CashOnlyRestaurantRate > 1 {
+		return nil, fmt.Errorf("cashless_restaurant_rate and cash_only_restaurant_rate must be non-negative and add up to no more than 1")
+	}
+
+	instructionTotal := 0.0
+	for _, instruction := range config.DeliveryInstructions {
+		if instruction.Probability < 0 || instruction.DelayMinutes < 0 {
+			return nil, fmt.Errorf("delivery instruction %q must not have a negative probability or delay", instruction.Type)
+		}
+		instructionTotal += instruction.Probability
+	}
+	if instructionTotal > 1 {
+		return nil, fmt.Errorf("delivery instruction probabilities must not add up to more than 1, got %.2f", instructionTotal)
+	}
+
+	if config.ComplaintRate < 0 || config.ComplaintRate > 1 {
+		return nil, fmt.Errorf("complaint_rate must be between 0 and 1, got %.2f", config.ComplaintRate)
+	}
+	categoryTotal := 0.0
+	for category, weight := range config.ComplaintCategories {
+		if weight < 0 {
+			return nil, fmt.Errorf("complaint category %q must not have a negative weight", category)
+		}
+		categoryTotal += weight
+	}
+	if len(config.ComplaintCategories) > 0 && categoryTotal == 0 {
+		return nil, fmt.Errorf("complaint_categories must have at least one positive weight")
+	}
+
+	for name, rate := range map[string]float64{
+		"restaurant_cancel_rate":    config.RestaurantCancelRate,
+		"bad_actor_restaurant_rate": config.BadActorRestaurantRate,
+		"bad_actor_cancel_rate":     config.BadActorCancelRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("%s must be between 0 and 1, got %.2f", name, rate)
+		}
+	}
+
+	if _, err := time.LoadLocation(config.Timezone); err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", config.Timezone, err)
+	}
+	for _, entity := range config.DailySummaryEntities {
+		if entity != SummaryEntityRestaurants && entity != SummaryEntityPartners {
+			return nil, fmt.Errorf("unknown daily summary entity %q, expected %q or %q", entity, SummaryEntityRestaurants, SummaryEntityPartners)
+		}
+	}
+
+	for name, timeout := range map[string]time.Duration{
+		"order_max_prep_time":       config.OrderMaxPrepTime,
+		"pickup_timeout":            config.PickupTimeout,
+		"in_transit_check_interval": config.InTransitCheckInterval,
+		"stale_order_timeout":       config.StaleOrderTimeout,
+		"assignment_retry_interval": config.AssignmentRetryInterval,
+	} {
+		if timeout <= 0 {
+			return nil, fmt.Errorf("%s must be positive, got %s", name, timeout)
+		}
+	}
+
+	for segment, elasticity := range config.FeeElasticity {
+		if segment != UserSegmentOccasional && segment != UserSegmentRegular && segment != UserSegmentFrequent {
+			return nil, fmt.Errorf("unknown fee_elasticity segment %q, expected %q, %q or %q", segment, UserSegmentOccasional, UserSegmentRegular, UserSegmentFrequent)
+		}
+		if elasticity < 0 {
+			return nil, fmt.Errorf("fee_elasticity for %s must not be negative, got %.2f", segment, elasticity)
+		}
+	}
+	if len(config.FeeElasticity) > 0 && config.FeeElasticityReferenceFee <= 0 {
+		return nil, fmt.Errorf("fee_elasticity_reference_fee must be positive, got %.2f", config.FeeElasticityReferenceFee)
+	}
+
+	if config.UpsellRate < 0 || config.UpsellRate > 1 {
+		return nil, fmt.Errorf("upsell_rate must be between 0 and 1, got %.2f", config.UpsellRate)
+	}
+	for itemType, rate := range config.UpsellTypes {
+		switch itemType {
+		case "appetizer", "main course", "side dish", "dessert", "drink":
+		default:
+			return nil, fmt.Errorf("unknown upsell_types item type %q", itemType)
+		}
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("upsell_types rate for %s must be between 0 and 1, got %.2f", itemType, rate)
+		}
+	}
+	for segment, multiplier := range config.UpsellSegmentAcceptance {
+		if segment != UserSegmentOccasional && segment != UserSegmentRegular && segment != UserSegmentFrequent {
+			return nil, fmt.Errorf("unknown upsell_segment_acceptance segment %q, expected %q, %q or %q", segment, UserSegmentOccasional, UserSegmentRegular, UserSegmentFrequent)
+		}
+		if multiplier < 0 {
+			return nil, fmt.Errorf("upsell_segment_acceptance for %s must not be negative, got %.2f", segment, multiplier)
+		}
+	}
+
+	if _, ok := ClimateProfiles[config.Climate]; config.Climate != "" && !ok {
+		return nil, fmt.Errorf("unknown climate %q", config.Climate)
+	}
+
+	regionNames := make(map[string]bool)
+	for _, region := range config.WeatherRegions {
+		if region.Name == "" {
+			return nil, fmt.Errorf("weather regions need a name")
+		}
+		if regionNames[region.Name] {
+			return nil, fmt.Errorf("duplicate weather region %q", region.Name)
+		}
+		regionNames[region.Name] = true
+		if region.RainChanceOffset < -1 || region.RainChanceOffset > 1 {
+			return nil, fmt.Errorf("rain_chance_offset for weather region %s must be between -1 and 1, got %.2f", region.Name, region.RainChanceOffset)
+		}
+	}
+
+	if config.MultiRestaurantOrderProbability < 0 || config.MultiRestaurantOrderProbability > 1 {
+		return nil, fmt.Errorf("multi_restaurant_order_probability must be between 0 and 1, got %.2f", config.MultiRestaurantOrderProbability)
+	}
+	if config.MultiRestaurantOrdersEnabled && config.MultiRestaurantMaxDistance <= 0 {
+		return nil, fmt.Errorf("multi_restaurant_max_distance must be positive, got %.2f", config.MultiRestaurantMaxDistance)
+	}
+
+	config.ReviewTemplates = DefaultReviewTemplates()
+	if config.ReviewTemplatesFile != "" {
+		templates, err := LoadReviewTemplates(config.ReviewTemplatesFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ReviewTemplates = templates
+	}
+
+	for topic, rate := range config.TopicRateLimits {
+		if rate < 0 {
+			return nil, fmt.Errorf("rate limit for topic %s must not be negative, got %.2f", topic, rate)
+		}
+	}
+	if config.ThrottleMode != ThrottleModeBuffer && config.ThrottleMode != ThrottleModeDrop {
+		return nil, fmt.Errorf("throttle_mode must be %q or %q, got %q", ThrottleModeBuffer, ThrottleModeDrop, config.ThrottleMode)
+	}
+
+	switch config.CompetitionCuisineOverlap {
+	case CuisineOverlapShared, CuisineOverlapPrimary, CuisineOverlapAny:
+	default:
+		return nil, fmt.Errorf("competition_cuisine_overlap must be %q, %q or %q, got %q", CuisineOverlapShared, CuisineOverlapPrimary, CuisineOverlapAny, config.CompetitionCuisineOverlap)
+	}
+	if config.CompetitionPricePressure < 0 || config.CompetitionReferenceDensity < 0 {
+		return nil, fmt.Errorf("competition_price_pressure and competition_reference_density must not be negative")
+	}
+	if config.CrossPriceSensitivity < 0 {
+		return nil, fmt.Errorf("cross_price_sensitivity must not be negative, got %.2f", config.CrossPriceSensitivity)
+	}
+	if (config.CompetitionPricePressure > 0 || config.CrossPriceSensitivity > 0) && config.CompetitionRadius <= 0 {
+		return nil, fmt.Errorf("competition_radius must be positive, got %.2f", config.CompetitionRadius)
+	}
+
+	if config.MinReviewWords < 0 {
+		return nil, fmt.Errorf("min_review_words must not be negative, got %d", config.MinReviewWords)
+	}
+	if config.RatingOnlyReviewRate < 0 || config.RatingOnlyReviewRate > 1 {
+		return nil, fmt.Errorf("rating_only_review_rate must be between 0 and 1, got %.2f", config.RatingOnlyReviewRate)
+	}
+	if config.ItemPrepTimeWeight < 0 || config.ItemPrepTimeWeight > 1 {
+		return nil, fmt.Errorf("item_prep_time_weight must be between 0 and 1, got %.2f", config.ItemPrepTimeWeight)
+	}
+	if config.PrepTimeOutlierRate < 0 || config.PrepTimeOutlierRate > 0.5 {
+		return nil, fmt.Errorf("prep_time_outlier_rate must be between 0 and 0.5, got %.2f", config.PrepTimeOutlierRate)
+	}
+	if config.PrepTimeOutlierShape <= 0 {
+		return nil, fmt.Errorf("prep_time_outlier_shape must be positive, got %.2f", config.PrepTimeOutlierShape)
+	}
+	if config.PrepTimeOutlierMaxFactor < 1.5 {
+		return nil, fmt.Errorf("prep_time_outlier_max_factor must be at least 1.5, got %.2f", config.PrepTimeOutlierMaxFactor)
+	}
+
+	if config.PartnerDeactivationRating < 0 || config.PartnerDeactivationRating > 5 {
+		return nil, fmt.Errorf("partner_deactivation_rating must be between 0 and 5, got %.2f", config.PartnerDeactivationRating)
+	}
+	if config.PartnerDeactivationMinRatings < 0 {
+		return nil, fmt.Errorf("partner_deactivation_min_ratings must not be negative, got %d", config.PartnerDeactivationMinRatings)
+	}
+	for method, delay := range config.RefundSettlementDelays {
+		if method != PaymentCard && method != PaymentCash && method != PaymentWallet {
+			return nil, fmt.Errorf("unknown payment method %q in refund_settlement_delays", method)
+		}
+		if delay < 0 {
+			return nil, fmt.Errorf("refund settlement delay for %s must not be negative, got %s", method, delay)
+		}
+	}
+	switch config.PartnerPayModel {
+	case PayModelPerDelivery, PayModelHourly, PayModelHybrid:
+	default:
+		return nil, fmt.Errorf("partner_pay_model must be %q, %q or %q, got %q", PayModelPerDelivery, PayModelHourly, PayModelHybrid, config.PartnerPayModel)
+	}
+	for name, rate := range map[string]float64{
+		"partner_per_delivery_pay": config.PartnerPerDeliveryPay,
+		"partner_per_km_pay":       config.PartnerPerKmPay,
+		"partner_hourly_rate":      config.PartnerHourlyRate,
+		"partner_hourly_floor":     config.PartnerHourlyFloor,
+	} {
+		if rate < 0 {
+			return nil, fmt.Errorf("%s must not be negative, got %.2f", name, rate)
+		}
+	}
+	for _, notificationType := range config.NotificationTypes {
+		switch notificationType {
+		case NotificationOrderConfirmed, NotificationPreparing, NotificationOutForDelivery, NotificationDelivered, NotificationReviewReminder:
+		default:
+			return nil, fmt.Errorf("unknown notification type %q", notificationType)
+		}
+	}
+	channelTotal := 0.0
+	for channel, weight := range config.NotificationChannels {
+		if channel != ChannelPush && channel != ChannelSMS && channel != ChannelEmail {
+			return nil, fmt.Errorf("unknown notification channel %q", channel)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("share of notifications sent by %s must not be negative, got %.2f", channel, weight)
+		}
+		channelTotal += weight
+	}
+	if len(config.NotificationChannels) > 0 && channelTotal <= 0 {
+		return nil, fmt.Errorf("notification_channels must give at least one channel a positive share")
+	}
+	if config.MinCapacity < 1 || config.MaxCapacity < config.MinCapacity {
+		return nil, fmt.Errorf("capacity range must satisfy 1 <= min_capacity <= max_capacity, got %d-%d", config.MinCapacity, config.MaxCapacity)
+	}
+	if config.RestaurantOfflineRate < 0 || config.RestaurantOfflineRate > 1 {
+		return nil, fmt.Errorf("restaurant_offline_rate must be between 0 and 1, got %.4f", config.RestaurantOfflineRate)
+	}
+	if config.RestaurantOfflineRate > 0 && config.RestaurantOfflineDuration <= 0 {
+		return nil, fmt.Errorf("restaurant_offline_duration must be positive, got %s", config.RestaurantOfflineDuration)
+	}
+	if config.RestaurantCapacitySpread < 0 {
+		return nil, fmt.Errorf("restaurant_capacity_spread must not be negative, got %.2f", config.RestaurantCapacitySpread)
+	}
+	if config.RatingRecomputeInterval < 0 {
+		return nil, fmt.Errorf("rating_recompute_interval must not be negative, got %s", config.RatingRecomputeInterval)
+	}
+	if config.FragilityComplaintMultiplier < 1 {
+		return nil, fmt.Errorf("fragility_complaint_multiplier must be at least 1, got %.2f", config.FragilityComplaintMultiplier)
+	}
+	if config.FragilityHandlingMinutes < 0 {
+		return nil, fmt.Errorf("fragility_handling_minutes must not be negative, got %.2f", config.FragilityHandlingMinutes)
+	}
+	if config.SpillRate < 0 || config.SpillRate > 1 {
+		return nil, fmt.Errorf("spill_rate must be between 0 and 1, got %.2f", config.SpillRate)
+	}
+	if config.OutageRate < 0 {
+		return nil, fmt.Errorf("outage_rate must not be negative, got %.4f", config.OutageRate)
+	}
+	if config.OutageRate > 0 && config.OutageDuration <= 0 {
+		return nil, fmt.Errorf("outage_duration must be positive, got %s", config.OutageDuration)
+	}
+	if config.OutageCatchUpShare < 0 || config.OutageCatchUpShare > 1 {
+		return nil, fmt.Errorf("outage_catch_up_share must be between 0 and 1, got %.2f", config.OutageCatchUpShare)
+	}
+	if config.OutageCatchUpWindow < 0 {
+		return nil, fmt.Errorf("outage_catch_up_window must not be negative, got %s", config.OutageCatchUpWindow)
+	}
+	if config.ReorderRate < 0 || config.ReorderRate > 1 {
+		return nil, fmt.Errorf("reorder_rate must be between 0 and 1, got %.2f", config.ReorderRate)
+	}
+	for segment, multiplier := range config.ReorderSegmentMultipliers {
+		if segment != UserSegmentOccasional && segment != UserSegmentRegular && segment != UserSegmentFrequent {
+			return nil, fmt.Errorf("unknown reorder_segment_multipliers segment %q, expected %q, %q or %q", segment, UserSegmentOccasional, UserSegmentRegular, UserSegmentFrequent)
+		}
+		if multiplier < 0 {
+			return nil, fmt.Errorf("reorder_segment_multipliers for %s must not be negative, got %.2f", segment, multiplier)
+		}
+	}
+	if config.PartnerAcceptanceRadius < 0 {
+		return nil, fmt.Errorf("partner_acceptance_radius must not be negative, got %.2f", config.PartnerAcceptanceRadius)
+	}
+	for segment, bias := range config.ReviewRatingBias {
+		if segment != UserSegmentOccasional && segment != UserSegmentRegular && segment != UserSegmentFrequent {
+			return nil, fmt.Errorf("unknown review_rating_bias segment %q, expected %q, %q or %q", segment, UserSegmentOccasional, UserSegmentRegular, UserSegmentFrequent)
+		}
+		if bias.FoodBase < -4 || bias.FoodBase > 4 {
+			return nil, fmt.Errorf("review_rating_bias.%s.food_base must be between -4 and 4, got %.2f", segment, bias.FoodBase)
+		}
+		if bias.PriceInfluence < 0 {
+			return nil, fmt.Errorf("review_rating_bias.%s.price_influence must not be negative, got %.2f", segment, bias.PriceInfluence)
+		}
+	}
+	if config.PrepTimeLearningRate < 0 || config.PrepTimeLearningRate > 1 {
+		return nil, fmt.Errorf("prep_time_learning_rate must be between 0 and 1, got %.2f", config.PrepTimeLearningRate)
+	}
+	if config.PrepTimeLearningWindow < 1 {
+		return nil, fmt.Errorf("prep_time_learning_window must be at least 1, got %d", config.PrepTimeLearningWindow)
+	}
+	for tier, multiplier := range config.ETAExperienceBias {
+		if tier != ExperienceTierNew && tier != ExperienceTierDeveloping && tier != ExperienceTierExperienced {
+			return nil, fmt.Errorf("unknown eta_experience_bias tier %q, expected %q, %q or %q", tier, ExperienceTierNew, ExperienceTierDeveloping, ExperienceTierExperienced)
+		}
+		if multiplier < 0.5 || multiplier > 2 {
+			return nil, fmt.Errorf("eta_experience_bias for %s must be between 0.5 and 2, got %.2f", tier, multiplier)
+		}
+	}
+	if config.ItemRatingRate < 0 || config.ItemRatingRate > 1 {
+		return nil, fmt.Errorf("item_rating_rate must be between 0 and 1, got %.2f", config.ItemRatingRate)
+	}
+	if config.ItemRatingCorrelation < 0 || config.ItemRatingCorrelation > 1 {
+		return nil, fmt.Errorf("item_rating_correlation must be between 0 and 1, got %.2f", config.ItemRatingCorrelation)
+	}
+	if config.ReviewFoodOnlyRate < 0 || config.ReviewDeliveryOnlyRate < 0 || config.ReviewFoodOnlyRate+config.ReviewDeliveryOnlyRate > 1 {
+		return nil, fmt.Errorf("review_food_only_rate and review_delivery_only_rate must not be negative or sum to more than 1, got %.2f and %.2f", config.ReviewFoodOnlyRate, config.ReviewDeliveryOnlyRate)
+	}
+	if config.AcceptanceSLA < 0 {
+		return nil, fmt.Errorf("acceptance_sla must not be negative, got %s", config.AcceptanceSLA)
+	}
+	for cuisine, t := range config.AcceptanceTimes {
+		if t <= 0 {
+			return nil, fmt.Errorf("acceptance_times.%s must be positive, got %s", cuisine, t)
+		}
+	}
+	if config.AcceptanceTimeSpread < 0 {
+		return nil, fmt.Errorf("acceptance_time_spread must not be negative, got %.2f", config.AcceptanceTimeSpread)
+	}
+	if config.GPSNoiseMeters < 0 {
+		return nil, fmt.Errorf("gps_noise_meters must not be negative, got %.2f", config.GPSNoiseMeters)
+	}
+	if config.GPSDropoutRate < 0 || config.GPSStaleRate < 0 || config.GPSDropoutRate+config.GPSStaleRate > 1 {
+		return nil, fmt.Errorf("gps_dropout_rate and gps_stale_rate must not be negative or sum to more than 1, got %.2f and %.2f", config.GPSDropoutRate, config.GPSStaleRate)
+	}
+	if config.GPSClockSkew < 0 {
+		return nil, fmt.Errorf("gps_clock_skew must not be negative, got %s", config.GPSClockSkew)
+	}
+	if config.PartnerCashLimit < 0 {
+		return nil, fmt.Errorf("partner_cash_limit must not be negative, got %.2f", config.PartnerCashLimit)
+	}
+	if config.PartnerCashDropShare <= 0 || config.PartnerCashDropShare > 1 {
+		return nil, fmt.Errorf("partner_cash_drop_share must be between 0 and 1, got %.2f", config.PartnerCashDropShare)
+	}
+	if config.PartnerCashDropDuration < 0 {
+		return nil, fmt.Errorf("partner_cash_drop_duration must not be negative, got %s", config.PartnerCashDropDuration)
+	}
+	if config.HomepageFeatureSlots < 0 {
+		return nil, fmt.Errorf("homepage_feature_slots must not be negative, got %d", config.HomepageFeatureSlots)
+	}
+	if config.HomepageFeatureSlots > 0 && (config.HomepageFeatureDailyRate <= 0 || config.HomepageFeatureDuration <= 0) {
+		return nil, fmt.Errorf("homepage_feature_daily_rate and homepage_feature_duration must be positive when homepage_feature_slots is set")
+	}
+	if config.HomepageFeatureBoost < 1 {
+		return nil, fmt.Errorf("homepage_feature_boost must be at least 1, got %.2f", config.HomepageFeatureBoost)
+	}
+	for i, feature := range config.HomepageFeatures {
+		if feature.RestaurantID == "" || feature.Start.IsZero() || feature.Duration <= 0 {
+			return nil, fmt.Errorf("homepage_features %d needs a restaurant_id, start and positive duration", i)
+		}
+	}
+	if config.PartnerGhostRate < 0 || config.PartnerGhostRate > 1 {
+		return nil, fmt.Errorf("partner_ghost_rate must be between 0 and 1, got %.2f", config.PartnerGhostRate)
+	}
+
+	if config.TipPriorityWeight < 0 {
+		return nil, fmt.Errorf("tip_priority_weight must not be negative, got %.2f", config.TipPriorityWeight)
+	}
+
+	if config.PartnerExperienceSpeedImpact < 0 || config.PartnerExperienceSpeedImpact >= 1 {
+		return nil, fmt.Errorf("partner_experience_speed_impact must be at least 0 and below 1, got %.2f", config.PartnerExperienceSpeedImpact)
+	}
+
+	if config.Workers <= 0 {
+		return nil, fmt.Errorf("workers must be positive, got %d", config.Workers)
+	}
+
+	if config.MaxRating <= config.MinRating {
+		return nil, fmt.Errorf("max_rating (%.1f) must be greater than min_rating (%.1f)", config.MaxRating, config.MinRating)
+	}
+
+	switch config.Units {
+	case "", UnitsMetric, UnitsImperial:
+	default:
+		return nil, fmt.Errorf("unsupported units: %s", config.Units)
+	}
+
+	switch config.OutputCompression {
+	case "", "none", "gzip":
+	default:
+		return nil, fmt.Errorf("unsupported output compression: %s", config.OutputCompression)
+	}
+
+	switch config.OutputMode {
+	case "", OutputModeTruncate, OutputModeAppend:
+	default:
+		return nil, fmt.Errorf("unsupported output mode: %s", config.OutputMode)
+	}
+
 	// validate cloud storage configuration
 	if config.OutputDestination != "local" {
 		if err := validateCloudStorageConfig(&config); err != nil {
@@ -159,6 +968,24 @@ func LoadConfig(cfgFile string) (*Config, error) {
 	return &config, nil
 }
 
+// TimeLocation is the city's time zone, falling back to UTC if it can't be loaded
+func (cfg *Config) TimeLocation() *time.Location {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// EntitySeedValue is the seed entity generation is derived from: entity_seed
+// if set, otherwise the master seed
+func (cfg *Config) EntitySeedValue() int64 {
+	if cfg.EntitySeed != 0 {
+		return cfg.EntitySeed
+	}
+	return int64(cfg.Seed)
+}
+
 func (cfg *Config) LoadReviewData(filePath string) error {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -235,6 +1062,9 @@ func validateCloudStorageConfig(config *Config) error {
 		if config.CloudStorage.BucketName == "" {
 			return fmt.Errorf("bucket_name is required for GCS")
 		}
+		if config.CloudStorage.AccessKeyID == "" || config.CloudStorage.SecretAccessKey == "" {
+			return fmt.Errorf("access_key_id and secret_access_key (HMAC keys) are required for GCS")
+		}
 	case "s3":
 		if config.CloudStorage.BucketName == "" {
 			return fmt.Errorf("bucket_name is required for S3")
@@ -252,6 +1082,109 @@ func validateCloudStorageConfig(config *Config) error {
 	return nil
 }
 
+// setDefaults sets defaults for optional simulation features so that enabling
+// a feature without tuning it still gives sensible behaviour
+func setDefaults() {
+	viper.SetDefault("kitchen_degradation_daily_rate", 0.05)
+	viper.SetDefault("kitchen_degradation_min_minutes", 30.0)
+	viper.SetDefault("kitchen_degradation_max_minutes", 180.0)
+	viper.SetDefault("kitchen_degradation_prep_factor", 2.0)
+	viper.SetDefault("kitchen_degradation_capacity_factor", 0.5)
+	viper.SetDefault("reputation_recovery_rate", 0.1)
+	viper.SetDefault("reputation_recent_weight", 0.3)
+	viper.SetDefault("review_response_rate", 0)
+	viper.SetDefault("review_response_damping", 0.5)
+	viper.SetDefault("review_edit_window", "72h")
+	viper.SetDefault("new_restaurant_boost_days", 14)
+	viper.SetDefault("new_restaurant_boost_ratings", 50.0)
+	viper.SetDefault("partner_shift_length", "0s")
+	viper.SetDefault("workers", runtime.NumCPU())
+	viper.SetDefault("max_delivery_radius", 10.0)
+	viper.SetDefault("traffic_speed_impact", 0.5)
+	viper.SetDefault("queue_depth_warning_threshold", 1000000)
+	viper.SetDefault("featured_dish_duration", "48h")
+	viper.SetDefault("featured_dish_boost", 3.0)
+	viper.SetDefault("cashless_restaurant_rate", 0)
+	viper.SetDefault("cash_only_restaurant_rate", 0)
+	viper.SetDefault("tip_probability", 0)
+	viper.SetDefault("complaint_rate", 0)
+	viper.SetDefault("partner_learning_days", 0.0)
+	viper.SetDefault("timezone", "UTC")
+	viper.SetDefault("local_time_demand", false)
+	viper.SetDefault("partner_ghost_rate", 0)
+	viper.SetDefault("acceptance_time_spread", 0.6)
+	viper.SetDefault("outage_duration", "30m")
+	viper.SetDefault("outage_catch_up_share", 0.6)
+	viper.SetDefault("outage_catch_up_window", "1h")
+	viper.SetDefault("item_rating_correlation", 0.7)
+	viper.SetDefault("prep_time_learning_window", 20)
+	viper.SetDefault("partner_dual_app_duration", "30m")
+	viper.SetDefault("restaurant_commission_rate", 0.25)
+	viper.SetDefault("door_dwell_spread", 0.5)
+	viper.SetDefault("door_dwell_urban_multiplier", 1.5)
+	viper.SetDefault("virality_magnitude", 6.0)
+	viper.SetDefault("virality_half_life", "36h")
+	viper.SetDefault("eta_display_width", "10m")
+	viper.SetDefault("menu_size_min", 10)
+	viper.SetDefault("menu_size_max", 30)
+	viper.SetDefault("demand_calibration", DemandCalibrationUsers)
+	viper.SetDefault("pii_scrub_mode", PIIScrubHash)
+	viper.SetDefault("pii_location_precision", 2)
+	viper.SetDefault("homepage_feature_daily_rate", 2.0)
+	viper.SetDefault("homepage_feature_duration", "6h")
+	viper.SetDefault("homepage_feature_boost", 3.0)
+	viper.SetDefault("seasonal_cuisine_strength", 0)
+	viper.SetDefault("partner_cash_limit", 0)
+	viper.SetDefault("partner_cash_drop_share", 0.8)
+	viper.SetDefault("partner_cash_drop_duration", "20m")
+	viper.SetDefault("fragility_complaint_multiplier", 1.0)
+	viper.SetDefault("fragility_handling_minutes", 0)
+	viper.SetDefault("spill_rate", 0)
+	viper.SetDefault("min_capacity", 10)
+	viper.SetDefault("max_capacity", 50)
+	viper.SetDefault("restaurant_capacity_spread", 0)
+	viper.SetDefault("restaurant_offline_rate", 0)
+	viper.SetDefault("restaurant_offline_duration", "45m")
+	viper.SetDefault("notifications_enabled", false)
+	viper.SetDefault("review_reminder_delay", "2h")
+	viper.SetDefault("review_reminder_lift", 0.15)
+	viper.SetDefault("competition_radius", 5.0)
+	viper.SetDefault("competition_cuisine_overlap", CuisineOverlapShared)
+	viper.SetDefault("competition_price_pressure", 0)
+	viper.SetDefault("competition_reference_density", 0.1)
+	viper.SetDefault("partner_pay_model", PayModelPerDelivery)
+	viper.SetDefault("partner_per_delivery_pay", 3.5)
+	viper.SetDefault("partner_per_km_pay", 0.6)
+	viper.SetDefault("partner_hourly_rate", 12.0)
+	viper.SetDefault("partner_hourly_floor", 10.0)
+	viper.SetDefault("min_review_words", 0)
+	viper.SetDefault("rating_only_review_rate", 0)
+	viper.SetDefault("item_prep_time_weight", 0)
+	viper.SetDefault("partner_deactivation_rating", 0)
+	viper.SetDefault("partner_deactivation_min_ratings", 20)
+	viper.SetDefault("enrich_order_events", false)
+	viper.SetDefault("fee_elasticity_reference_fee", 2.99)
+	viper.SetDefault("multi_restaurant_orders_enabled", false)
+	viper.SetDefault("multi_restaurant_order_probability", 0.05)
+	viper.SetDefault("multi_restaurant_max_distance", 1.5)
+	viper.SetDefault("order_max_prep_time", "2h")
+	viper.SetDefault("pickup_timeout", "15m")
+	viper.SetDefault("in_transit_check_interval", "5m")
+	viper.SetDefault("stale_order_timeout", "3h")
+	viper.SetDefault("assignment_retry_interval", "2m")
+	viper.SetDefault("throttle_mode", ThrottleModeBuffer)
+	viper.SetDefault("prep_time_outlier_rate", 0)
+	viper.SetDefault("prep_time_outlier_shape", 1.5)
+	viper.SetDefault("prep_time_outlier_max_factor", 6)
+	viper.SetDefault("restaurant_cancel_rate", 0)
+	viper.SetDefault("bad_actor_restaurant_rate", 0)
+	viper.SetDefault("bad_actor_cancel_rate", 0.25)
+	viper.SetDefault("partner_experience_speed_impact", 0)
+	viper.SetDefault("partner_navigation_error_rate", 0)
+	viper.SetDefault("min_rating", 1.0)
+	viper.SetDefault("max_rating", 5.0)
+}
+
 func bindEnvVariables() {
 	// list all the keys to bind to environment variables
 	keys := []string{
@@ -280,10 +1213,165 @@ func bindEnvVariables() {
 		"output_folder",
 		"continuous",
 		"output_destination",
+		"output_compression",
+		"output_mode",
+		"delivery_behaviour_weight",
+		"review_edit_probability",
+		"review_edit_window",
+		"review_price_sentiment_strength",
+		"min_review_words",
+		"rating_only_review_rate",
+		"max_events",
+		"workers",
+		"min_order_subtotal",
+		"max_order_subtotal",
+		"partner_decline_rate",
+		"partner_shift_length",
+		"partner_shift_summary_fields",
+		"new_restaurant_boost",
+		"new_restaurant_boost_days",
+		"new_restaurant_boost_ratings",
+		"restaurant_metrics_interval",
+		"rating_recompute_interval",
+		"partner_placement",
+		"units",
+		"abandoned_cart_rate",
+		"tip_probability",
+		"prep_progress_interval",
+		"max_delivery_radius",
+		"traffic_speed_impact",
+		"cashless_restaurant_rate",
+		"weather_observation_interval",
+		"weather_regions",
+		"climate",
+		"featured_dish_daily_rate",
+		"featured_dish_duration",
+		"featured_dish_boost",
+		"queue_depth_log_interval",
+		"queue_depth_warning_threshold",
+		"cash_only_restaurant_rate",
+		"distance_report_path",
+		"complaint_rate",
+		"entity_seed",
+		"partner_learning_days",
+		"restaurant_cancel_rate",
+		"tip_priority_weight",
+		"timezone",
+		"local_time_demand",
+		"review_templates_file",
+		"enrich_order_events",
+		"fee_elasticity",
+		"fee_elasticity_reference_fee",
+		"upsell_rate",
+		"upsell_types",
+		"upsell_segment_acceptance",
+		"multi_restaurant_orders_enabled",
+		"multi_restaurant_order_probability",
+		"multi_restaurant_max_distance",
+		"partner_ghost_rate",
+		"homepage_feature_slots",
+		"homepage_feature_daily_rate",
+		"homepage_feature_duration",
+		"homepage_feature_boost",
+		"seasonal_cuisine_strength",
+		"outage_rate",
+		"outage_duration",
+		"outage_catch_up_share",
+		"outage_catch_up_window",
+		"reorder_rate",
+		"review_links",
+		"review_links_unreviewed",
+		"door_dwell_time",
+		"door_dwell_spread",
+		"door_dwell_urban_multiplier",
+		"virality_rate",
+		"virality_magnitude",
+		"virality_half_life",
+		"prep_stations",
+		"partner_status_pings",
+		"order_trace_ids",
+		"settlement_events",
+		"restaurant_commission_rate",
+		"partner_dual_app_rate",
+		"partner_dual_app_duration",
+		"eta_display_bucket",
+		"eta_display_width",
+		"menu_size_min",
+		"menu_size_max",
+		"target_orders",
+		"demand_calibration",
+		"pii_scrub_fields",
+		"pii_scrub_mode",
+		"pii_location_precision",
+		"partner_home_radius",
+		"partner_acceptance_radius",
+		"order_placement_smoothing",
+		"prep_time_learning_rate",
+		"prep_time_learning_window",
+		"eta_experience_calibration",
+		"item_rating_rate",
+		"item_rating_correlation",
+		"review_food_only_rate",
+		"review_delivery_only_rate",
+		"acceptance_sla",
+		"acceptance_time_spread",
+		"gps_noise_meters",
+		"gps_dropout_rate",
+		"gps_stale_rate",
+		"gps_clock_skew",
+		"partner_cash_limit",
+		"partner_cash_drop_share",
+		"partner_cash_drop_duration",
+		"fragility_complaint_multiplier",
+		"fragility_handling_minutes",
+		"spill_rate",
+		"restaurant_capacity_spread",
+		"restaurant_offline_rate",
+		"restaurant_offline_duration",
+		"notifications_enabled",
+		"notification_types",
+		"notification_channels",
+		"review_reminder_delay",
+		"review_reminder_lift",
+		"competition_radius",
+		"competition_cuisine_overlap",
+		"competition_price_pressure",
+		"competition_reference_density",
+		"cross_price_sensitivity",
+		"partner_pay_model",
+		"partner_per_delivery_pay",
+		"partner_per_km_pay",
+		"partner_hourly_rate",
+		"partner_hourly_floor",
+		"refund_settlement_delays",
+		"item_prep_time_weight",
+		"partner_deactivation_rating",
+		"partner_deactivation_min_ratings",
+		"order_max_prep_time",
+		"pickup_timeout",
+		"in_transit_check_interval",
+		"stale_order_timeout",
+		"assignment_retry_interval",
+		"throttle_mode",
+		"prep_time_outlier_rate",
+		"prep_time_outlier_shape",
+		"prep_time_outlier_max_factor",
+		"daily_summary_entities",
+		"bad_actor_restaurant_rate",
+		"bad_actor_cancel_rate",
+		"partner_experience_speed_impact",
+		"partner_navigation_error_rate",
+		"partner_rates_customers",
+		"min_rating",
+		"max_rating",
 		"cloud_storage.provider",
 		"cloud_storage.bucket_name",
 		"cloud_storage.container_name",
 		"cloud_storage.region",
+		"cloud_storage.prefix",
+		"cloud_storage.endpoint",
+		"cloud_storage.access_key_id",
+		"cloud_storage.secret_access_key",
 		// add other keys as needed
 	}
 
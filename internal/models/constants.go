@@ -16,7 +16,66 @@ const (
 	PartnerStatusEnRouteDelivery  = "en_route_to_delivery"
 	PartnerStatusDelivering       = "delivering"
 	PartnerStatusOffline          = "offline"
+	PartnerStatusDeactivated      = "deactivated"
 
 	RestaurantStatusOpen   = "open"
 	RestaurantStatusClosed = "closed"
+
+	// Restaurant.Offline values
+	RestaurantOfflineEnabled  = "ENABLED"
+	RestaurantOfflineDisabled = "DISABLED"
+
+	OfflineReasonOverwhelmed  = "overwhelmed"
+	OfflineReasonWeather      = "weather"
+	OfflineReasonClosingEarly = "closing_early"
+	OfflineReasonUnplanned    = "unplanned"
+
+	KitchenIncidentEquipmentFailure = "equipment_failure"
+	KitchenIncidentStaffShortage    = "staff_shortage"
+	KitchenIncidentSupplyShortage   = "supply_shortage"
+
+	HotspotTypeCityCenter  = "city_center"
+	HotspotTypeBusiness    = "business"
+	HotspotTypeUniversity  = "university"
+	HotspotTypeShopping    = "shopping"
+	HotspotTypeResidential = "residential"
+
+	WeatherClear  = "clear"
+	WeatherCloudy = "cloudy"
+	WeatherRain   = "rain"
+	WeatherSnow   = "snow"
+
+	PartnerPlacementUniform = "uniform"
+	PartnerPlacementDemand  = "demand"
+	PartnerPlacementHome    = "home"
+
+	DemandCalibrationUsers     = "users"
+	DemandCalibrationFrequency = "frequency"
+
+	PaymentCard   = "card"
+	PaymentCash   = "cash"
+	PaymentWallet = "wallet"
+
+	AbandonReasonBasketTotal = "basket_total"
+	AbandonReasonDeliveryFee = "delivery_fee"
+	AbandonReasonChangedMind = "changed_mind"
+
+	CancelledByCustomer   = "customer"
+	CancelledByRestaurant = "restaurant"
+	CancelledBySystem     = "system"
+
+	CancelReasonOverwhelmed     = "overwhelmed"
+	CancelReasonItemUnavailable = "item_unavailable"
+	CancelReasonTimeout         = "timeout"
+
+	CancelReasonAcceptanceTimeout = "acceptance_timeout" // The restaurant didn't accept the order within the SLA
+
+	ThrottleModeBuffer = "buffer"
+	ThrottleModeDrop   = "drop"
+
+	OutputModeTruncate = "truncate"
+	OutputModeAppend   = "append"
+
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
 )
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+const (
+	SummaryEntityRestaurants = "restaurants"
+	SummaryEntityPartners    = "partners"
+)
+
+// DailySummary aggregates one restaurant's or partner's activity over a local calendar day
+type DailySummary struct {
+	EntityType     string // One of the SummaryEntity constants
+	EntityID       string
+	Date           time.Time // Local midnight at the start of the day
+	Orders         int       // Orders placed with the restaurant, or delivered by the partner
+	Completed      int
+	Cancelled      int
+	CompletionRate float64
+	LateDeliveries int
+	Revenue        float64 // Order totals for a restaurant, delivery fees and tips for a partner
+	AvgRating      float64 // Food rating for a restaurant, delivery rating for a partner; 0 without ratings
+	Ratings        int
+	Partial        bool // The simulation ended before the day did
+}
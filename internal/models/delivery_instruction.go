@@ -0,0 +1,17 @@
+package models
+
+const (
+	InstructionNone          = "none"
+	InstructionLeaveAtDoor   = "leave_at_door"
+	InstructionHandToMe      = "hand_to_me"
+	InstructionCallOnArrival = "call_on_arrival"
+	InstructionGateCode      = "gate_code"
+)
+
+// DeliveryInstruction is a kind of drop-off instruction a customer can leave,
+// how often it is chosen and the average extra handling time it causes
+type DeliveryInstruction struct {
+	Type         string  `mapstructure:"type"`
+	Probability  float64 `mapstructure:"probability"`
+	DelayMinutes float64 `mapstructure:"delay_minutes"`
+}
@@ -15,4 +15,71 @@ type DeliveryPartner struct {
 	CurrentLocation Location  `json:"current_location"`
 	Status          string    `json:"status"` // "available", "en_route_to_pickup", "en_route_to_delivery"
 	LastUpdateTime  time.Time
+
+	ShiftStats PartnerShiftStats `json:"shift_stats"` // Activity over the current shift
+
+	AcceptedAssignments int       `json:"accepted_assignments"`
+	Ghostings           int       `json:"ghostings"`     // Accepted orders the partner abandoned before pickup
+	OfflineUntil        time.Time `json:"offline_until"` // When an offline partner comes back
+
+	DeactivatedAt time.Time `json:"deactivated_at"` // When the platform deactivated the partner, zero while active
+
+	CashOnHand float64 `json:"cash_on_hand"` // Cash collected from customers and not yet dropped off
+
+	HomeLocation Location `json:"home_location"` // Where each shift starts with partner_placement "home"
+}
+
+// PartnerCashDrop is a partner handing in the cash they collected
+type PartnerCashDrop struct {
+	PartnerID string
+	Amount    float64
+	Until     time.Time // When the partner is back taking orders
+}
+
+// PartnerDualApp is a partner away on a job from another delivery app
+type PartnerDualApp struct {
+	PartnerID string
+	Start     time.Time
+	Until     time.Time // When the partner is back taking orders
+}
+
+// PartnerDeactivation is the platform removing a chronically low-rated partner
+type PartnerDeactivation struct {
+	PartnerID       string
+	Rating          float64
+	TotalRatings    float64
+	HandedOffOrders []string // Orders released for reassignment as the partner was deactivated
+	DeactivatedAt   time.Time
+}
+
+// PartnerGhosting is a partner going silent on an order they accepted
+type PartnerGhosting struct {
+	PartnerID    string
+	OrderID      string
+	CustomerID   string
+	RestaurantID string
+	AssignedAt   time.Time
+	Ghosted      bool // Set once the no-show takes effect
+}
+
+// PartnerShiftStats accumulates a partner's activity over a shift
+type PartnerShiftStats struct {
+	PartnerID   string
+	ShiftStart  time.Time
+	ShiftEnd    time.Time
+	Offers      int // Orders offered to the partner
+	Declined    int // Offers the partner declined
+	Deliveries  int
+	IdleMinutes float64 // Time spent available without an order
+	DistanceKm  float64
+	Experience  float64 // Partner experience at the end of the shift
+
+	HomeToFirstPickupKm float64 // Distance from home to the shift's first pickup
+	FirstPickupAssigned bool    // Whether HomeToFirstPickupKm has been recorded
+
+	// Earnings over the shift
+	OnlineMinutes float64 // Time on shift while not offline, which hourly pay accrues over
+	DeliveryPay   float64
+	HourlyPay     float64
+	Tips          float64
 }
@@ -0,0 +1,43 @@
+package models
+
+import "strings"
+
+// DefaultCuisineDemandProfiles holds hourly demand multipliers (index 0 is
+// midnight) for cuisines whose busy hours differ from the norm, keyed by
+// lower-case cuisine name. Cuisines without a profile have flat demand.
+var DefaultCuisineDemandProfiles = map[string][]float64{
+	"breakfast": {
+		0.1, 0.1, 0.1, 0.1, 0.2, 0.5, 1.2, 1.8, 2.0, 1.8, 1.5, 1.1,
+		0.8, 0.6, 0.5, 0.4, 0.3, 0.3, 0.2, 0.2, 0.1, 0.1, 0.1, 0.1,
+	},
+	"cafe": {
+		0.2, 0.1, 0.1, 0.1, 0.2, 0.4, 0.9, 1.5, 1.8, 1.6, 1.4, 1.3,
+		1.3, 1.2, 1.1, 1.0, 0.9, 0.7, 0.5, 0.4, 0.3, 0.3, 0.2, 0.2,
+	},
+	"bar": {
+		1.4, 1.2, 0.8, 0.4, 0.2, 0.1, 0.1, 0.1, 0.1, 0.1, 0.2, 0.3,
+		0.5, 0.5, 0.5, 0.6, 0.8, 1.1, 1.4, 1.6, 1.8, 1.9, 1.9, 1.7,
+	},
+	"fast food": {
+		1.1, 0.9, 0.7, 0.4, 0.2, 0.2, 0.3, 0.5, 0.6, 0.6, 0.8, 1.3,
+		1.5, 1.3, 0.9, 0.8, 0.9, 1.2, 1.4, 1.4, 1.3, 1.3, 1.3, 1.2,
+	},
+	"street food": {
+		0.9, 0.7, 0.5, 0.3, 0.2, 0.2, 0.2, 0.3, 0.4, 0.5, 0.8, 1.4,
+		1.6, 1.4, 0.9, 0.8, 0.9, 1.2, 1.4, 1.3, 1.2, 1.1, 1.0, 1.0,
+	},
+}
+
+// CuisineDemandMultiplier returns how busy a cuisine is at the given hour,
+// preferring the configured profile over the built-in default
+func (cfg *Config) CuisineDemandMultiplier(cuisine string, hour int) float64 {
+	key := strings.ToLower(cuisine)
+	profile, ok := cfg.CuisineDemandProfiles[key]
+	if !ok {
+		profile, ok = DefaultCuisineDemandProfiles[key]
+	}
+	if !ok || len(profile) != 24 || hour < 0 || hour >= 24 {
+		return 1
+	}
+	return profile[hour]
+}
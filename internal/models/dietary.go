@@ -0,0 +1,71 @@
+package models
+
+import "strings"
+
+const (
+	DietVegetarian = "vegetarian"
+	DietVegan      = "vegan"
+	DietGlutenFree = "gluten-free"
+	DietDairyFree  = "dairy-free"
+	DietNutFree    = "nut-free"
+	DietHalal      = "halal"
+	DietKosher     = "kosher"
+)
+
+// DefaultDietaryTagProbabilities is the chance a menu item carries each
+// dietary tag for cuisines without a configured or default profile
+var DefaultDietaryTagProbabilities = map[string]float64{
+	DietVegetarian: 0.3,
+	DietVegan:      0.1,
+	DietGlutenFree: 0.2,
+	DietDairyFree:  0.25,
+	DietNutFree:    0.7,
+	DietHalal:      0.2,
+	DietKosher:     0.05,
+}
+
+// DefaultCuisineDietaryTags holds dietary tag probabilities for cuisines
+// that differ noticeably from the default, keyed by lower-case cuisine name
+var DefaultCuisineDietaryTags = map[string]map[string]float64{
+	"indian": {
+		DietVegetarian: 0.5, DietVegan: 0.2, DietGlutenFree: 0.4,
+		DietDairyFree: 0.2, DietNutFree: 0.5, DietHalal: 0.6, DietKosher: 0.02,
+	},
+	"italian": {
+		DietVegetarian: 0.4, DietVegan: 0.1, DietGlutenFree: 0.1,
+		DietDairyFree: 0.15, DietNutFree: 0.8, DietHalal: 0.1, DietKosher: 0.05,
+	},
+	"japanese": {
+		DietVegetarian: 0.2, DietVegan: 0.1, DietGlutenFree: 0.15,
+		DietDairyFree: 0.85, DietNutFree: 0.8, DietHalal: 0.1, DietKosher: 0.02,
+	},
+	"mediterranean": {
+		DietVegetarian: 0.5, DietVegan: 0.3, DietGlutenFree: 0.35,
+		DietDairyFree: 0.4, DietNutFree: 0.6, DietHalal: 0.4, DietKosher: 0.1,
+	},
+	"moroccan": {
+		DietVegetarian: 0.3, DietVegan: 0.15, DietGlutenFree: 0.3,
+		DietDairyFree: 0.5, DietNutFree: 0.5, DietHalal: 0.9, DietKosher: 0.05,
+	},
+	"thai": {
+		DietVegetarian: 0.3, DietVegan: 0.2, DietGlutenFree: 0.5,
+		DietDairyFree: 0.8, DietNutFree: 0.4, DietHalal: 0.2, DietKosher: 0.02,
+	},
+	"fast food": {
+		DietVegetarian: 0.15, DietVegan: 0.05, DietGlutenFree: 0.1,
+		DietDairyFree: 0.2, DietNutFree: 0.8, DietHalal: 0.1, DietKosher: 0.02,
+	},
+}
+
+// DietaryTagsFor returns the dietary tag probabilities for a cuisine,
+// preferring the configured profile over the built-in default
+func (cfg *Config) DietaryTagsFor(cuisine string) map[string]float64 {
+	key := strings.ToLower(cuisine)
+	if tags, ok := cfg.CuisineDietaryTags[key]; ok {
+		return tags
+	}
+	if tags, ok := DefaultCuisineDietaryTags[key]; ok {
+		return tags
+	}
+	return DefaultDietaryTagProbabilities
+}
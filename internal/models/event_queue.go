@@ -30,6 +30,35 @@ const (
 	EventAddNewRestaurant         = "AddNewRestaurant"
 	EventAddNewDeliveryPartner    = "AddNewDeliveryPartner"
 	EventGenerateReview           = "GenerateReview"
+	EventEditReview               = "EditReview"
+	EventRestaurantMetrics        = "RestaurantMetrics"
+	EventPartnerShiftSummary      = "PartnerShiftSummary"
+	EventAbandonCart              = "AbandonCart"
+	EventPrepProgress             = "PrepProgress"
+	EventWeatherObservation       = "WeatherObservation"
+	EventFeaturedDishStarted      = "FeaturedDishStarted"
+	EventFeaturedDishEnded        = "FeaturedDishEnded"
+	EventComplaintCheck           = "ComplaintCheck"
+	EventSupportTicket            = "SupportTicket"
+	EventDailySummary             = "DailySummary"
+	EventPartnerGhosted           = "PartnerGhosted"
+	EventPartnerDeactivated       = "PartnerDeactivated"
+	EventRefundInitiated          = "RefundInitiated"
+	EventRefundCompleted          = "RefundCompleted"
+	EventPartnerPayout            = "PartnerPayout"
+	EventNotification             = "Notification"
+	EventRatingRecomputed         = "RatingRecomputed"
+	EventRestaurantHours          = "RestaurantHours"
+	EventPartnerCashDrop          = "PartnerCashDrop"
+	EventHomepageFeatureStarted   = "HomepageFeatureStarted"
+	EventHomepageFeatureEnded     = "HomepageFeatureEnded"
+	EventOrderAcceptance          = "OrderAcceptance"
+	EventPlatformOutageStarted    = "PlatformOutageStarted"
+	EventPlatformOutageEnded      = "PlatformOutageEnded"
+	EventPartnerDualApp           = "PartnerDualApp"
+	EventOrderSettlement          = "OrderSettlement"
+	EventRestaurantViral          = "RestaurantViral"
+	EventOrderReviewLink          = "OrderReviewLink"
 )
 
 // Event represents a simulation event
@@ -37,20 +66,30 @@ type Event struct {
 	Time time.Time
 	Type string
 	Data interface{}
+
+	seq uint64 // insertion order, used to break ties between events with the same Time
 }
 
-// EventQueue is a priority queue of events
+// EventQueue is a priority queue of events. Events with the same Time are
+// dequeued in the order they were enqueued.
 type EventQueue struct {
-	events []*Event
-	mutex  sync.Mutex
+	events  []*Event
+	nextSeq uint64
+	mutex   sync.Mutex
 }
 
 // eventHeap implements heap.Interface and holds Events
 type eventHeap []*Event
 
-func (h eventHeap) Len() int           { return len(h) }
-func (h eventHeap) Less(i, j int) bool { return h[i].Time.Before(h[j].Time) }
-func (h eventHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h eventHeap) Len() int      { return len(h) }
+func (h eventHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h eventHeap) Less(i, j int) bool {
+	if h[i].Time.Equal(h[j].Time) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].Time.Before(h[j].Time)
+}
 
 func (h *eventHeap) Push(x interface{}) {
 	*h = append(*h, x.(*Event))
@@ -73,6 +112,8 @@ func NewEventQueue() *EventQueue {
 func (eq *EventQueue) Enqueue(event *Event) {
 	eq.mutex.Lock()
 	defer eq.mutex.Unlock()
+	event.seq = eq.nextSeq
+	eq.nextSeq++
 	heap.Push((*eventHeap)(&eq.events), event)
 }
 
@@ -0,0 +1,37 @@
+package models
+
+const (
+	ExperienceTierNew         = "new"
+	ExperienceTierDeveloping  = "developing"
+	ExperienceTierExperienced = "experienced"
+)
+
+// DefaultETAExperienceBias is how much longer than the route alone the
+// platform expects partners in each experience tier to take, when no mapping
+// is configured
+var DefaultETAExperienceBias = map[string]float64{
+	ExperienceTierNew:         1.2,
+	ExperienceTierDeveloping:  1.08,
+	ExperienceTierExperienced: 1,
+}
+
+// ExperienceTier buckets a partner's experience, from 0 to 1, into thirds
+func ExperienceTier(experience float64) string {
+	switch {
+	case experience < 1.0/3:
+		return ExperienceTierNew
+	case experience < 2.0/3:
+		return ExperienceTierDeveloping
+	default:
+		return ExperienceTierExperienced
+	}
+}
+
+// ETAExperienceMultiplier returns the configured ETA bias for an experience
+// tier, or the default if the tier isn't configured
+func (cfg *Config) ETAExperienceMultiplier(tier string) float64 {
+	if multiplier, ok := cfg.ETAExperienceBias[tier]; ok {
+		return multiplier
+	}
+	return DefaultETAExperienceBias[tier]
+}
@@ -0,0 +1,39 @@
+package models
+
+// FeeOverride replaces the global tax_rate and service_fee_percentage for
+// some restaurants. A rate left unset keeps the rate it overrides.
+type FeeOverride struct {
+	TaxRate              *float64 `mapstructure:"tax_rate"`
+	ServiceFeePercentage *float64 `mapstructure:"service_fee_percentage"`
+}
+
+// Apply returns the tax rate and service fee percentage with the override's
+// rates in place of those it sets
+func (o FeeOverride) Apply(taxRate, serviceFee float64) (float64, float64) {
+	if o.TaxRate != nil {
+		taxRate = *o.TaxRate
+	}
+	if o.ServiceFeePercentage != nil {
+		serviceFee = *o.ServiceFeePercentage
+	}
+	return taxRate, serviceFee
+}
+
+// validRates reports whether the rates the override sets are between 0 and 1
+func (o FeeOverride) validRates() bool {
+	for _, rate := range []*float64{o.TaxRate, o.ServiceFeePercentage} {
+		if rate != nil && (*rate < 0 || *rate > 1) {
+			return false
+		}
+	}
+	return true
+}
+
+// FeeRegion is a jurisdiction with its own tax and service fee rates, covering
+// restaurants within RadiusKm of Location
+type FeeRegion struct {
+	Name        string   `mapstructure:"name"`
+	Location    Location `mapstructure:"location"`
+	RadiusKm    float64  `mapstructure:"radius_km"`
+	FeeOverride `mapstructure:",squash"`
+}
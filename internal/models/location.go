@@ -7,8 +7,10 @@ type Location struct {
 
 // Hotspot represents a location with high demand for food delivery
 type Hotspot struct {
-	Location Location
-	Weight   float64 // Represents the importance or activity level of the hotspot
+	Name     string   `mapstructure:"name"`
+	Type     string   `mapstructure:"type"` // One of the HotspotType constants
+	Location Location `mapstructure:"location"`
+	Weight   float64  `mapstructure:"weight"` // Represents the importance or activity level of the hotspot
 }
 
 type PartnerLocationUpdate struct {
@@ -17,3 +19,19 @@ type PartnerLocationUpdate struct {
 	NewLocation Location
 	Speed       float64
 }
+
+// DemandHotspots returns the configured demand hotspots, or five default ones
+// laid out around the city centre
+func (cfg *Config) DemandHotspots() []Hotspot {
+	if len(cfg.Hotspots) > 0 {
+		return cfg.Hotspots
+	}
+	lat, lon := cfg.CityLat, cfg.CityLon
+	return []Hotspot{
+		{Name: "City center", Type: HotspotTypeCityCenter, Location: Location{Lat: lat, Lon: lon}, Weight: 1.0},
+		{Name: "Business district", Type: HotspotTypeBusiness, Location: Location{Lat: lat + 0.01, Lon: lon + 0.01}, Weight: 0.8},
+		{Name: "University area", Type: HotspotTypeUniversity, Location: Location{Lat: lat - 0.015, Lon: lon - 0.005}, Weight: 0.7},
+		{Name: "Shopping mall", Type: HotspotTypeShopping, Location: Location{Lat: lat + 0.008, Lon: lon - 0.012}, Weight: 0.6},
+		{Name: "Residential area", Type: HotspotTypeResidential, Location: Location{Lat: lat - 0.02, Lon: lon + 0.018}, Weight: 0.5},
+	}
+}
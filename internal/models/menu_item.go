@@ -11,6 +11,7 @@ type MenuItem struct {
 	Type               string   `json:"type"`       // e.g., "appetizer", "main course", "side dish", "dessert", "drink"
 	Popularity         float64  `json:"popularity"` // A score representing item popularity (e.g., 0.0 to 1.0)
 	PrepComplexity     float64  `json:"prep_complexity"`
-	Ingredients        []string `json:"ingredients"` // List of ingredients
+	Ingredients        []string `json:"ingredients"`  // List of ingredients
+	DietaryTags        []string `json:"dietary_tags"` // Diets the item is suitable for, e.g. "vegan", "halal"
 	IsDiscountEligible bool     `json:"is_discount_eligible"`
 }
@@ -0,0 +1,21 @@
+package models
+
+import "strings"
+
+// MenuSizeRange is the fewest and most items a restaurant's menu can have
+type MenuSizeRange struct {
+	Min int `mapstructure:"min"`
+	Max int `mapstructure:"max"`
+}
+
+// MenuSizeFor returns the menu size range for a restaurant serving cuisines:
+// the configured range of the first of its cuisines that has one, otherwise
+// menu_size_min to menu_size_max
+func (cfg *Config) MenuSizeFor(cuisines []string) MenuSizeRange {
+	for _, cuisine := range cuisines {
+		if r, ok := cfg.CuisineMenuSizes[strings.ToLower(cuisine)]; ok {
+			return r
+		}
+	}
+	return MenuSizeRange{Min: cfg.MenuSizeMin, Max: cfg.MenuSizeMax}
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+const (
+	NotificationOrderConfirmed = "order_confirmed"
+	NotificationPreparing      = "preparing"
+	NotificationOutForDelivery = "out_for_delivery"
+	NotificationDelivered      = "delivered"
+	NotificationReviewReminder = "review_reminder"
+
+	ChannelPush  = "push"
+	ChannelSMS   = "sms"
+	ChannelEmail = "email"
+)
+
+// DefaultNotificationTypes are the order updates users are notified of when
+// none are configured
+var DefaultNotificationTypes = []string{
+	NotificationOrderConfirmed,
+	NotificationPreparing,
+	NotificationOutForDelivery,
+	NotificationDelivered,
+	NotificationReviewReminder,
+}
+
+// DefaultNotificationChannels is the share of notifications sent on each
+// channel when none are configured
+var DefaultNotificationChannels = map[string]float64{
+	ChannelPush:  0.8,
+	ChannelSMS:   0.15,
+	ChannelEmail: 0.05,
+}
+
+// Notification is a message sent to a user about one of their orders
+type Notification struct {
+	ID      string
+	UserID  string
+	Order   *Order
+	Type    string // One of the Notification constants
+	Channel string // One of the Channel constants
+	SentAt  time.Time
+}
+
+// NotificationTypesEnabled returns the configured notification types, or the
+// defaults if none are configured
+func (cfg *Config) NotificationTypesEnabled() []string {
+	if cfg.NotificationTypes != nil {
+		return cfg.NotificationTypes
+	}
+	return DefaultNotificationTypes
+}
+
+// NotificationChannelWeights returns the configured channel shares, or the
+// defaults if none are configured
+func (cfg *Config) NotificationChannelWeights() map[string]float64 {
+	if len(cfg.NotificationChannels) > 0 {
+		return cfg.NotificationChannels
+	}
+	return DefaultNotificationChannels
+}
@@ -10,6 +10,9 @@ type Order struct {
 	Items                 []string  `json:"item_ids"` // List of MenuItem IDs
 	TotalAmount           float64   `json:"total_amount"`
 	DeliveryCost          float64   `json:"delivery_cost"`
+	DistanceFee           float64   `json:"distance_fee"` // Distance surcharge included in DeliveryCost
+	ToppedUp              bool      `json:"topped_up"`    // Items were added to reach the minimum order amount
+	Tip                   float64   `json:"tip"`
 	OrderPlacedAt         time.Time `json:"order_placed_at"`
 	PrepStartTime         time.Time `json:"prep_start_time"`
 	EstimatedPickupTime   time.Time `json:"estimated_pickup_time"`
@@ -20,5 +23,37 @@ type Order struct {
 	Status                string    `json:"status"`         // e.g., "placed", "preparing", "in_transit", "delivered", "cancelled"
 	PaymentMethod         string    `json:"payment_method"` // e.g., "card", "cash", "wallet"
 	Address               Address   `json:"delivery_address"`
+	DeliveryInstruction   string    `json:"delivery_instruction"` // One of the Instruction constants
+	DeliveryNote          string    `json:"delivery_note"`        // Free-text instruction, e.g. "Gate code 1234"
 	ReviewGenerated       bool      `json:"review_generated"`
+	ReviewReminderID      string    `json:"review_reminder_id"`
+	CustomerRating        float64   `json:"customer_rating"` // Partner's rating of the customer, 0 if not rated
+	CancelledBy           string    `json:"cancelled_by"`    // One of the CancelledBy constants, empty unless cancelled
+	CancellationReason    string    `json:"cancellation_reason"`
+	RestaurantIDs         []string  `json:"restaurant_ids"`    // Every restaurant in a multi-restaurant order, in pickup order; empty for single-restaurant orders
+	PickupsCompleted      int       `json:"pickups_completed"` // Restaurants of a multi-restaurant order already collected from
+
+	Upsell *Upsell `json:"upsell,omitempty"` // Add-on suggested at checkout, nil if none was shown
+
+	HomepageFeatured bool `json:"homepage_featured"` // Ground truth: placed while the restaurant was featured on the homepage
+	Viral            bool `json:"viral"`             // Ground truth: placed while the restaurant was viral
+
+	DoorDwell time.Duration `json:"door_dwell"` // Time the partner spent at the door finding it and handing over, part of ActualDeliveryTime
+
+	ReorderOf string `json:"reorder_of"` // Order this repeats exactly, empty unless the customer reordered
+
+	AcceptedAt time.Time `json:"accepted_at"` // When the restaurant accepted the order; zero when acceptance isn't modelled or it was auto-rejected
+
+	// Tax and service fee included in TotalAmount, at the rates that applied to the restaurant, and the discount taken off it
+	TaxRate        float64 `json:"tax_rate"`
+	TaxAmount      float64 `json:"tax_amount"`
+	ServiceFeeRate float64 `json:"service_fee_rate"`
+	ServiceFee     float64 `json:"service_fee"`
+	DiscountAmount float64 `json:"discount_amount"`
+}
+
+// PrepProgress is a point-in-time preparation update for an order
+type PrepProgress struct {
+	Order            *Order
+	EstimatedReadyAt time.Time
 }
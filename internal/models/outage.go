@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+const (
+	OutageReasonPayments = "payments" // Checkout fails; the app still works
+	OutageReasonAppCrash = "app_crash"
+)
+
+// PlatformOutage is a window in which nobody can order on the platform
+type PlatformOutage struct {
+	ID           string
+	Reason       string // One of the OutageReason constants
+	Start        time.Time
+	Until        time.Time
+	MissedOrders int // Order attempts that failed during the outage
+	Retried      int // Missed orders the customer placed again once it ended
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	PayModelPerDelivery = "per_delivery"
+	PayModelHourly      = "hourly"
+	PayModelHybrid      = "hybrid"
+)
+
+// PartnerPayout is what a partner is paid for a shift
+type PartnerPayout struct {
+	PartnerID      string
+	PayModel       string // One of the PayModel constants
+	ShiftStart     time.Time
+	ShiftEnd       time.Time
+	OnlineMinutes  float64
+	Deliveries     int
+	DeliveryPay    float64 // Per-delivery and per-km pay
+	HourlyPay      float64
+	GuaranteeTopUp float64 // Paid to bring hybrid earnings up to the hourly floor
+	Tips           float64
+}
+
+// Total is everything paid out for the shift
+func (p *PartnerPayout) Total() float64 {
+	return p.DeliveryPay + p.HourlyPay + p.GuaranteeTopUp + p.Tips
+}
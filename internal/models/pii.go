@@ -0,0 +1,12 @@
+package models
+
+const (
+	PIIFieldAddress        = "address"
+	PIIFieldDeliveryNote   = "delivery_note"
+	PIIFieldComment        = "comment"
+	PIIFieldRestaurantName = "restaurant_name"
+	PIIFieldLocation       = "location"
+
+	PIIScrubHash      = "hash"
+	PIIScrubRandomize = "randomize"
+)
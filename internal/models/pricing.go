@@ -0,0 +1,92 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// What makes two restaurants competitors
+const (
+	CuisineOverlapShared  = "shared"  // They share at least one cuisine
+	CuisineOverlapPrimary = "primary" // Their main cuisine is the same
+	CuisineOverlapAny     = "any"     // Every nearby restaurant competes
+)
+
+// PriceRange is the range menu item prices are drawn from
+type PriceRange struct {
+	Min float64 `mapstructure:"min"`
+	Max float64 `mapstructure:"max"`
+}
+
+// DefaultPriceRange is used for cuisines without a configured or default range
+var DefaultPriceRange = PriceRange{Min: 5, Max: 50}
+
+// DefaultCuisinePriceRanges holds typical menu item price ranges per cuisine,
+// keyed by lower-case cuisine name
+var DefaultCuisinePriceRanges = map[string]PriceRange{
+	"fast food":       {Min: 2, Max: 12},
+	"street food":     {Min: 3, Max: 12},
+	"cafe":            {Min: 3, Max: 15},
+	"homemade":        {Min: 5, Max: 18},
+	"american":        {Min: 6, Max: 25},
+	"mexican":         {Min: 5, Max: 20},
+	"chinese":         {Min: 5, Max: 22},
+	"vietnamese":      {Min: 5, Max: 20},
+	"thai":            {Min: 6, Max: 24},
+	"indian":          {Min: 6, Max: 25},
+	"carribean":       {Min: 6, Max: 22},
+	"moroccan":        {Min: 7, Max: 26},
+	"greek":           {Min: 6, Max: 26},
+	"mediterranean":   {Min: 7, Max: 28},
+	"italian":         {Min: 8, Max: 30},
+	"native american": {Min: 8, Max: 30},
+	"european":        {Min: 10, Max: 35},
+	"continental":     {Min: 12, Max: 40},
+	"japanese":        {Min: 8, Max: 45},
+	"contemporary":    {Min: 15, Max: 55},
+	"french":          {Min: 15, Max: 60},
+}
+
+// CuisinePriceRange returns the menu item price range for a cuisine, preferring
+// the configured range over the built-in default
+func (cfg *Config) CuisinePriceRange(cuisine string) PriceRange {
+	key := strings.ToLower(cuisine)
+	if r, ok := cfg.CuisinePriceRanges[key]; ok && r.Max >= r.Min && r.Max > 0 {
+		return r
+	}
+	if r, ok := DefaultCuisinePriceRanges[key]; ok {
+		return r
+	}
+	return DefaultPriceRange
+}
+
+// DeliveryFeeTier charges FeePerKm for each km of delivery distance beyond
+// FromKm, up to the start of the next tier
+type DeliveryFeeTier struct {
+	FromKm   float64 `mapstructure:"from_km"`
+	FeePerKm float64 `mapstructure:"fee_per_km"`
+}
+
+// sortDeliveryFeeTiers orders the fee schedule by distance
+func (cfg *Config) sortDeliveryFeeTiers() {
+	sort.Slice(cfg.DeliveryFeeTiers, func(i, j int) bool {
+		return cfg.DeliveryFeeTiers[i].FromKm < cfg.DeliveryFeeTiers[j].FromKm
+	})
+}
+
+// DistanceFee returns the distance component of the delivery fee for a
+// restaurant-to-customer distance in km
+func (cfg *Config) DistanceFee(distance float64) float64 {
+	fee := 0.0
+	for i, tier := range cfg.DeliveryFeeTiers {
+		if distance <= tier.FromKm {
+			break
+		}
+		upTo := distance
+		if i+1 < len(cfg.DeliveryFeeTiers) && cfg.DeliveryFeeTiers[i+1].FromKm < distance {
+			upTo = cfg.DeliveryFeeTiers[i+1].FromKm
+		}
+		fee += (upTo - tier.FromKm) * tier.FeePerKm
+	}
+	return fee
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// HomepageFeatureSchedule features a restaurant on the platform homepage for
+// a set window
+type HomepageFeatureSchedule struct {
+	RestaurantID string        `mapstructure:"restaurant_id"`
+	Start        time.Time     `mapstructure:"start"`
+	Duration     time.Duration `mapstructure:"duration"`
+}
+
+// HomepageFeature is the platform promoting a restaurant on its homepage and
+// in search, the start or end of which is emitted as ground truth
+type HomepageFeature struct {
+	RestaurantID string
+	Start        time.Time
+	Until        time.Time
+	Scheduled    bool // Set from homepage_features rather than picked at random
+}
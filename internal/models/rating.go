@@ -0,0 +1,59 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Ratings are simulated on a 1–5 scale and only rescaled on output
+const (
+	internalMinRating = 1.0
+	internalMaxRating = 5.0
+)
+
+// OutputRating maps an internal 1–5 rating onto the configured
+// min_rating–max_rating scale, keeping its relative position
+func (cfg *Config) OutputRating(rating float64) float64 {
+	if cfg.MaxRating <= cfg.MinRating {
+		return rating
+	}
+	fraction := (rating - internalMinRating) / (internalMaxRating - internalMinRating)
+	scaled := cfg.MinRating + fraction*(cfg.MaxRating-cfg.MinRating)
+	return math.Round(scaled*10) / 10
+}
+
+// RatingDistribution describes a truncated normal distribution of ratings
+type RatingDistribution struct {
+	Mean   float64 `mapstructure:"mean"`
+	StdDev float64 `mapstructure:"stddev"`
+	Min    float64 `mapstructure:"min"`
+	Max    float64 `mapstructure:"max"`
+}
+
+// Enabled reports whether the distribution has been configured
+func (d RatingDistribution) Enabled() bool {
+	return d.Mean > 0
+}
+
+// Sample draws a rating from the distribution, redrawing values outside
+// min–max so the shape inside the bounds is preserved
+func (d RatingDistribution) Sample(normFloat64 func() float64) float64 {
+	for attempt := 0; attempt < 100; attempt++ {
+		rating := d.Mean + normFloat64()*d.StdDev
+		if rating >= d.Min && rating <= d.Max {
+			return math.Round(rating*10) / 10
+		}
+	}
+	return math.Max(d.Min, math.Min(d.Max, d.Mean))
+}
+
+// RatingRecomputation is a restaurant's or partner's rating rebuilt from its
+// full set of reviews, correcting drift in the incrementally updated rating
+type RatingRecomputation struct {
+	EntityType     string // One of the SummaryEntity constants
+	EntityID       string
+	PreviousRating float64
+	Rating         float64
+	Reviews        int // Simulated reviews the rating was rebuilt from
+	RecomputedAt   time.Time
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestOutputRatingKeepsScaleShape(t *testing.T) {
+	cfg := &Config{MinRating: 0, MaxRating: 10}
+	for _, tc := range []struct{ internal, want float64 }{
+		{1, 0},
+		{2, 2.5},
+		{3, 5},
+		{4.2, 8},
+		{5, 10},
+	} {
+		if got := cfg.OutputRating(tc.internal); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("OutputRating(%v) on 0–10 = %v, want %v", tc.internal, got, tc.want)
+		}
+	}
+
+	// evenly spaced internal ratings stay evenly spaced on the output scale
+	step := cfg.OutputRating(2) - cfg.OutputRating(1)
+	for r := 2.0; r < 5; r++ {
+		if got := cfg.OutputRating(r+1) - cfg.OutputRating(r); math.Abs(got-step) > 1e-9 {
+			t.Errorf("step from %v to %v is %v, want %v", r, r+1, got, step)
+		}
+	}
+}
+
+func TestOutputRatingUnchangedWithoutScale(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.OutputRating(3.7); got != 3.7 {
+		t.Errorf("OutputRating(3.7) with no scale = %v, want it unchanged", got)
+	}
+}
+
+func TestRatingDistributionSampleMatchesMeanAndStdDev(t *testing.T) {
+	// bounds far enough out that truncation barely changes the moments
+	d := RatingDistribution{Mean: 4.1, StdDev: 0.3, Min: 1, Max: 5}
+	rng := rand.New(rand.NewSource(7))
+
+	const n = 20000
+	var sum, sumSq float64
+	for i := 0; i < n; i++ {
+		r := d.Sample(rng.NormFloat64)
+		if r < d.Min || r > d.Max {
+			t.Fatalf("sample %v outside %v–%v", r, d.Min, d.Max)
+		}
+		sum += r
+		sumSq += r * r
+	}
+	mean := sum / n
+	stddev := math.Sqrt(sumSq/n - mean*mean)
+	if math.Abs(mean-d.Mean) > 0.02 {
+		t.Errorf("sample mean %.3f, want %.1f", mean, d.Mean)
+	}
+	if math.Abs(stddev-d.StdDev) > 0.02 {
+		t.Errorf("sample stddev %.3f, want %.1f", stddev, d.StdDev)
+	}
+}
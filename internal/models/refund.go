@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+const (
+	RefundReasonCancellation = "cancellation"
+	RefundReasonComplaint    = "complaint"
+
+	RefundStatusInitiated = "initiated"
+	RefundStatusCompleted = "completed"
+)
+
+// DefaultRefundSettlementDelays is how long refunds take to reach the customer
+// for each payment method when no delays are configured
+var DefaultRefundSettlementDelays = map[string]time.Duration{
+	PaymentWallet: 0,
+	PaymentCard:   72 * time.Hour,
+	PaymentCash:   0, // cash orders are refunded as wallet credit
+}
+
+// Refund is money returned to a customer, from when it is initiated until it
+// settles with their payment provider
+type Refund struct {
+	ID            string
+	OrderID       string
+	CustomerID    string
+	RestaurantID  string
+	PaymentMethod string // Method the refund is paid back to
+	Amount        float64
+	Reason        string // One of the RefundReason constants
+	InitiatedAt   time.Time
+	SettlesAt     time.Time
+}
+
+// RefundSettlementDelay is how long a refund to the given payment method takes
+// to settle: the configured delay if there is one, otherwise the default
+func (cfg *Config) RefundSettlementDelay(paymentMethod string) time.Duration {
+	if delay, ok := cfg.RefundSettlementDelays[paymentMethod]; ok {
+		return delay
+	}
+	return DefaultRefundSettlementDelays[paymentMethod]
+}
@@ -0,0 +1,23 @@
+package models
+
+// DefaultReorderSegmentMultipliers scale the reorder rate per user segment
+// when none are configured: frequent customers repeat favourites most
+var DefaultReorderSegmentMultipliers = map[string]float64{
+	UserSegmentOccasional: 0.5,
+	UserSegmentRegular:    1,
+	UserSegmentFrequent:   2,
+}
+
+// ReorderProbability is the chance a user in segment repeats a recent order
+// instead of browsing, capped at 1
+func (cfg *Config) ReorderProbability(segment string) float64 {
+	multiplier, ok := cfg.ReorderSegmentMultipliers[segment]
+	if !ok {
+		multiplier = DefaultReorderSegmentMultipliers[segment]
+	}
+	probability := cfg.ReorderRate * multiplier
+	if probability > 1 {
+		return 1
+	}
+	return probability
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type Restaurant struct {
 	ID               string   `json:"id"`
 	Host             string   `json:"host"`
@@ -21,4 +23,78 @@ type Restaurant struct {
 	MenuItems        []string `json:"menu_item_ids"`
 	CurrentOrders    []Order  `json:"current_orders"`
 	Capacity         int      `json:"capacity"`
+
+	BaseCapacity int `json:"base_capacity"` // Orders the venue handles at once on an ordinary day; capacity varies around it
+
+	OfflineUntil time.Time `json:"offline_until"` // When a restaurant that went offline mid-day starts taking orders again
+
+	HomepageFeaturedFrom  time.Time `json:"homepage_featured_from"`
+	HomepageFeaturedUntil time.Time `json:"homepage_featured_until"` // End of the restaurant's current homepage feature, zero if not featured
+
+	ViralFrom time.Time `json:"viral_from"` // Start of the restaurant's current viral spike, zero if not viral
+
+	KitchenIncident *KitchenIncident `json:"kitchen_incident,omitempty"` // Active kitchen degradation, if any
+	RatingWindows   RatingWindows    `json:"rating_windows"`
+	OpenedAt        time.Time        `json:"opened_at"`
+
+	AcceptedPaymentMethods []string `json:"accepted_payment_methods"` // Payment constants the restaurant takes
+
+	FeaturedItemID string    `json:"featured_item_id,omitempty"` // Menu item currently being promoted, if any
+	FeaturedUntil  time.Time `json:"featured_until"`
+
+	BadActor        bool    `json:"bad_actor"`   // Ground truth: the restaurant habitually cancels accepted orders
+	CancelRate      float64 `json:"cancel_rate"` // Chance the restaurant cancels an order it has accepted
+	AcceptedOrders  int     `json:"accepted_orders"`
+	CancelledOrders int     `json:"cancelled_orders"` // Accepted orders the restaurant went on to cancel
+}
+
+// FeaturedDish is the start or end of a restaurant promoting one of its dishes
+type FeaturedDish struct {
+	RestaurantID string
+	MenuItemID   string
+	Until        time.Time // When the dish stops being featured; zero on the end event
+}
+
+// RatingWindows splits a restaurant's reputation into a fast-moving recent
+// rating and a slow-moving historical one
+type RatingWindows struct {
+	Recent     float64 `json:"recent"`
+	Historical float64 `json:"historical"`
+}
+
+// KitchenIncident is a temporary kitchen problem (equipment failure, staff
+// shortage) during which prep times spike and capacity drops
+type KitchenIncident struct {
+	Type            string    `json:"type"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	PrepTimeFactor  float64   `json:"prep_time_factor"`
+	CapacityFactor  float64   `json:"capacity_factor"`
+	CapacityAtStart int       `json:"capacity_at_start"` // Restored when the incident ends
+}
+
+// RestaurantMetrics is a snapshot of a restaurant's order performance over a
+// reporting window
+type RestaurantMetrics struct {
+	RestaurantID      string
+	WindowStart       time.Time
+	WindowEnd         time.Time
+	OrdersPlaced      int
+	OrdersCompleted   int
+	OrdersCancelled   int
+	CompletionRate    float64
+	EstimatedPrepTime float64 // minutes
+	ActualPrepTime    float64 // minutes, averaged over orders picked up in the window
+	LateOrders        int
+	EarlyOrders       int
+	PickupEfficiency  float64
+}
+
+// RestaurantHoursChange is a restaurant going offline mid-day or coming back
+type RestaurantHoursChange struct {
+	RestaurantID string
+	Status       string    // RestaurantStatusOpen or RestaurantStatusClosed
+	Reason       string    // One of the OfflineReason constants; empty when reopening
+	Until        time.Time // When the restaurant takes orders again
+	OpenOrders   int       // Orders the restaurant is still completing
 }
@@ -8,11 +8,20 @@ type Review struct {
 	CustomerID        string    `json:"customer_id"`
 	RestaurantID      string    `json:"restaurant_id"`
 	DeliveryPartnerID string    `json:"delivery_partner_id"`
-	FoodRating        float64   `json:"food_rating"`
-	DeliveryRating    float64   `json:"delivery_rating"`
+	FoodRating        float64   `json:"food_rating"`     // 0 if the customer only rated the delivery
+	DeliveryRating    float64   `json:"delivery_rating"` // 0 if the customer only rated the food
 	OverallRating     float64   `json:"overall_rating"`
 	Comment           string    `json:"comment"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 	IsIgnored         bool      `json:"is_ignored"`
+	RestaurantReplied bool      `json:"restaurant_replied"`
+
+	ItemRatings []ItemRating `json:"item_ratings,omitempty"` // Ratings of individual dishes, empty unless the customer rated them
+}
+
+// ItemRating is a customer's rating of one dish in their order
+type ItemRating struct {
+	ItemID string  `json:"item_id"`
+	Rating float64 `json:"rating"`
 }
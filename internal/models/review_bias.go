@@ -0,0 +1,33 @@
+package models
+
+import "strings"
+
+// ReviewRatingBias is how a user segment's food ratings lean
+type ReviewRatingBias struct {
+	FoodBase       float64 `mapstructure:"food_base"`       // Stars added to every food rating the segment gives
+	PriceInfluence float64 `mapstructure:"price_influence"` // Scales review_price_sentiment_strength for the segment
+}
+
+// DefaultReviewRatingBias leaves a segment's ratings as sampled
+var DefaultReviewRatingBias = ReviewRatingBias{PriceInfluence: 1}
+
+// ReviewRatingBiasFor returns the configured rating bias for users in
+// segment, or the neutral default
+func (cfg *Config) ReviewRatingBiasFor(segment string) ReviewRatingBias {
+	if bias, ok := cfg.ReviewRatingBias[segment]; ok {
+		return bias
+	}
+	return DefaultReviewRatingBias
+}
+
+// CuisineRatingOffset returns the stars added to food ratings of a restaurant
+// serving cuisines: the configured offset of the first of its cuisines that
+// has one, otherwise 0
+func (cfg *Config) CuisineRatingOffset(cuisines []string) float64 {
+	for _, cuisine := range cuisines {
+		if offset, ok := cfg.CuisineRatingOffsets[strings.ToLower(cuisine)]; ok {
+			return offset
+		}
+	}
+	return 0
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// OrderReviewLink ties a delivered order to its review, or records that it
+// never got one
+type OrderReviewLink struct {
+	OrderID      string
+	CustomerID   string
+	RestaurantID string
+	ReviewID     string // Empty for an order that was never reviewed
+	DeliveredAt  time.Time
+	ReviewedAt   time.Time // Zero for an order that was never reviewed
+}
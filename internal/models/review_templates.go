@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	ReviewTriggerLate      = "late"
+	ReviewTriggerEarly     = "early"
+	ReviewTriggerExpensive = "expensive"
+	ReviewTriggerGoodValue = "good_value"
+)
+
+// ReviewTemplates is the text reviews are built from: base comments, a
+// delivery remark chosen by delivery rating, and extra remarks triggered by
+// what happened to the order
+type ReviewTemplates struct {
+	Comments         []ReviewData             `mapstructure:"comments"`          // Base comments; empty uses the loaded review data
+	DeliveryFeedback []DeliveryFeedbackBand   `mapstructure:"delivery_feedback"` // Delivery remarks by rating band
+	Triggers         []ReviewTriggerTemplates `mapstructure:"triggers"`
+}
+
+// DeliveryFeedbackBand holds the delivery remarks used for delivery ratings of
+// at least MinRating
+type DeliveryFeedbackBand struct {
+	MinRating float64  `mapstructure:"min_rating"`
+	Phrases   []string `mapstructure:"phrases"`
+}
+
+// ReviewTriggerTemplates holds remarks added, with the given probability, to
+// reviews of orders matching Condition
+type ReviewTriggerTemplates struct {
+	Condition   string   `mapstructure:"condition"` // One of the ReviewTrigger constants
+	Probability float64  `mapstructure:"probability"`
+	Phrases     []string `mapstructure:"phrases"`
+}
+
+// DefaultReviewTemplates are the built-in review templates
+func DefaultReviewTemplates() *ReviewTemplates {
+	return &ReviewTemplates{
+		DeliveryFeedback: []DeliveryFeedbackBand{
+			{MinRating: 4.5, Phrases: []string{"Delivery was lightning fast!"}},
+			{MinRating: 4.0, Phrases: []string{"Arrived earlier than expected."}},
+			{MinRating: 3.5, Phrases: []string{"Delivery was on time."}},
+			{MinRating: 2.5, Phrases: []string{"Delivery was a bit slow."}},
+			{MinRating: 1.5, Phrases: []string{"The wait for delivery was too long."}},
+			{MinRating: 0, Phrases: []string{"Extremely slow delivery."}},
+		},
+	}
+}
+
+// LoadReviewTemplates reads review templates from a JSON, YAML or TOML file
+// and checks they are usable
+func LoadReviewTemplates(filePath string) (*ReviewTemplates, error) {
+	v := viper.New()
+	v.SetConfigFile(filePath)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading review templates: %w", err)
+	}
+
+	templates := &ReviewTemplates{}
+	if err := v.UnmarshalExact(templates); err != nil {
+		return nil, fmt.Errorf("error decoding review templates: %w", err)
+	}
+	if len(templates.DeliveryFeedback) == 0 {
+		templates.DeliveryFeedback = DefaultReviewTemplates().DeliveryFeedback
+	}
+	if err := templates.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid review templates in %s: %w", filePath, err)
+	}
+
+	// bands are matched highest first
+	sort.SliceStable(templates.DeliveryFeedback, func(i, j int) bool {
+		return templates.DeliveryFeedback[i].MinRating > templates.DeliveryFeedback[j].MinRating
+	})
+	return templates, nil
+}
+
+// Validate checks every template has text and every band and trigger is well formed
+func (t *ReviewTemplates) Validate() error {
+	for i, comment := range t.Comments {
+		if comment.Comment == "" {
+			return fmt.Errorf("comment %d is empty", i)
+		}
+	}
+
+	lowest := 5.0
+	for i, band := range t.DeliveryFeedback {
+		if band.MinRating < 0 || band.MinRating > 5 {
+			return fmt.Errorf("delivery_feedback %d: min_rating must be between 0 and 5, got %.2f", i, band.MinRating)
+		}
+		if err := validatePhrases(band.Phrases); err != nil {
+			return fmt.Errorf("delivery_feedback %d: %w", i, err)
+		}
+		lowest = math.Min(lowest, band.MinRating)
+	}
+	if lowest > 1 {
+		return fmt.Errorf("delivery_feedback must cover the lowest ratings, lowest min_rating is %.2f", lowest)
+	}
+
+	for i, trigger := range t.Triggers {
+		switch trigger.Condition {
+		case ReviewTriggerLate, ReviewTriggerEarly, ReviewTriggerExpensive, ReviewTriggerGoodValue:
+		default:
+			return fmt.Errorf("trigger %d: unknown condition %q", i, trigger.Condition)
+		}
+		if trigger.Probability < 0 || trigger.Probability > 1 {
+			return fmt.Errorf("trigger %d: probability must be between 0 and 1, got %.2f", i, trigger.Probability)
+		}
+		if err := validatePhrases(trigger.Phrases); err != nil {
+			return fmt.Errorf("trigger %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validatePhrases(phrases []string) error {
+	if len(phrases) == 0 {
+		return fmt.Errorf("at least one phrase is required")
+	}
+	for i, phrase := range phrases {
+		if phrase == "" {
+			return fmt.Errorf("phrase %d is empty", i)
+		}
+	}
+	return nil
+}
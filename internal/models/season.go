@@ -0,0 +1,69 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	SeasonWinter = "winter"
+	SeasonSpring = "spring"
+	SeasonSummer = "summer"
+	SeasonAutumn = "autumn"
+)
+
+// DefaultSeasonalCuisineModifiers scale how appealing a cuisine is in each
+// season, keyed by season then lower-case cuisine name: warming curries,
+// noodle soups and home cooking in winter, lighter food in summer. Cuisines
+// not listed are unaffected.
+var DefaultSeasonalCuisineModifiers = map[string]map[string]float64{
+	SeasonWinter: {
+		"indian":        1.25,
+		"vietnamese":    1.2,
+		"homemade":      1.2,
+		"chinese":       1.1,
+		"moroccan":      1.1,
+		"mediterranean": 0.85,
+		"greek":         0.9,
+	},
+	SeasonAutumn: {
+		"homemade": 1.1,
+		"indian":   1.1,
+	},
+	SeasonSpring: {
+		"mediterranean": 1.1,
+		"japanese":      1.05,
+	},
+	SeasonSummer: {
+		"mediterranean": 1.25,
+		"greek":         1.2,
+		"japanese":      1.15,
+		"street food":   1.1,
+		"mexican":       1.1,
+		"indian":        0.85,
+		"homemade":      0.9,
+	},
+}
+
+// SeasonAt is the meteorological season at t, flipped for the southern hemisphere
+func SeasonAt(t time.Time, southern bool) string {
+	seasons := [4]string{SeasonWinter, SeasonSpring, SeasonSummer, SeasonAutumn}
+	index := int(t.Month()) % 12 / 3 // December-February is 0
+	if southern {
+		index = (index + 2) % 4
+	}
+	return seasons[index]
+}
+
+// SeasonalCuisineModifier returns how appealing a cuisine is in season,
+// preferring the configured modifiers over the built-in defaults
+func (cfg *Config) SeasonalCuisineModifier(season, cuisine string) float64 {
+	modifiers, ok := cfg.SeasonalCuisineModifiers[season]
+	if !ok {
+		modifiers = DefaultSeasonalCuisineModifiers[season]
+	}
+	if modifier, ok := modifiers[strings.ToLower(cuisine)]; ok {
+		return modifier
+	}
+	return 1
+}
@@ -0,0 +1,24 @@
+package models
+
+// OrderSettlement is how a delivered order's total and tip split between the
+// restaurant, the partner, the tax authority and the platform. The restaurant
+// payout, partner pay, tip, tax and platform net always add up to the order
+// total plus tip.
+type OrderSettlement struct {
+	OrderID          string
+	CustomerID       string
+	RestaurantID     string
+	PartnerID        string
+	Subtotal         float64 // Menu price of the items
+	Discount         float64 // Taken off the total, funded by the platform
+	Tax              float64
+	ServiceFee       float64
+	DeliveryFee      float64
+	Tip              float64 // Passed on to the partner in full
+	TotalAmount      float64 // What the customer paid, excluding the tip
+	CommissionRate   float64
+	Commission       float64 // Platform commission taken from the subtotal
+	RestaurantPayout float64 // Subtotal less commission
+	PartnerPay       float64 // Per-delivery and per-km pay for the order, 0 under hourly pay
+	PlatformNet      float64 // Commission, service and delivery fees less partner pay and the discount
+}
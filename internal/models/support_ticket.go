@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+const (
+	ComplaintWrongItem    = "wrong_item"
+	ComplaintMissingItem  = "missing_item"
+	ComplaintColdFood     = "cold_food"
+	ComplaintLateDelivery = "late_delivery"
+	ComplaintDamagedItem  = "damaged_item" // Spilled or crushed in transit
+
+	ResolutionRefund = "refund"
+	ResolutionCredit = "credit"
+	ResolutionNone   = "none"
+)
+
+// DefaultComplaintCategories is the share of support tickets in each complaint
+// category when none are configured
+var DefaultComplaintCategories = map[string]float64{
+	ComplaintWrongItem:    0.3,
+	ComplaintMissingItem:  0.25,
+	ComplaintColdFood:     0.3,
+	ComplaintLateDelivery: 0.15,
+	ComplaintDamagedItem:  0.05,
+}
+
+// SupportTicket is a customer complaint about a delivered order
+type SupportTicket struct {
+	ID           string
+	OrderID      string
+	CustomerID   string
+	RestaurantID string
+	Category     string  // One of the Complaint constants
+	Resolution   string  // One of the Resolution constants
+	Amount       float64 // Refunded or credited amount, 0 when unresolved
+	MinutesLate  float64
+	CreatedAt    time.Time
+}
+
+// ComplaintCategoryWeights returns the configured complaint category shares,
+// or the defaults if none are configured
+func (cfg *Config) ComplaintCategoryWeights() map[string]float64 {
+	if len(cfg.ComplaintCategories) > 0 {
+		return cfg.ComplaintCategories
+	}
+	return DefaultComplaintCategories
+}
@@ -0,0 +1,36 @@
+package models
+
+const kmPerMile = 1.609344
+
+// OutputDistance converts an internal distance in kilometres to the configured
+// output unit (miles when imperial)
+func (cfg *Config) OutputDistance(km float64) float64 {
+	if cfg.Units == UnitsImperial {
+		return km / kmPerMile
+	}
+	return km
+}
+
+// OutputSpeed converts an internal speed in km/h to the configured output unit
+// (mph when imperial)
+func (cfg *Config) OutputSpeed(kmh float64) float64 {
+	return cfg.OutputDistance(kmh)
+}
+
+// OutputPrecipitation converts an internal precipitation rate in mm/h to the
+// configured output unit (inches/h when imperial)
+func (cfg *Config) OutputPrecipitation(mm float64) float64 {
+	if cfg.Units == UnitsImperial {
+		return mm / 25.4
+	}
+	return mm
+}
+
+// OutputTemperature converts an internal temperature in Celsius to the
+// configured output unit (Fahrenheit when imperial)
+func (cfg *Config) OutputTemperature(celsius float64) float64 {
+	if cfg.Units == UnitsImperial {
+		return celsius*9/5 + 32
+	}
+	return celsius
+}
@@ -0,0 +1,41 @@
+package models
+
+// DefaultUpsellTypes is the chance a customer takes up each kind of add-on
+// suggested at checkout, keyed by menu item type, when none are configured
+var DefaultUpsellTypes = map[string]float64{
+	"dessert": 0.15,
+	"drink":   0.2,
+}
+
+// DefaultUpsellSegmentAcceptance scales upsell acceptance per user segment
+// when none is configured: regular customers are the baseline
+var DefaultUpsellSegmentAcceptance = map[string]float64{
+	UserSegmentOccasional: 0.6,
+	UserSegmentRegular:    1,
+	UserSegmentFrequent:   1.4,
+}
+
+// Upsell is an add-on suggested to a customer at checkout
+type Upsell struct {
+	Type     string `json:"type"` // Menu item type of the suggestion, e.g. "dessert"
+	ItemID   string `json:"item_id"`
+	Accepted bool   `json:"accepted"`
+}
+
+// UpsellAcceptanceRates returns the configured acceptance rate of each upsell
+// type, or the defaults if none are configured
+func (cfg *Config) UpsellAcceptanceRates() map[string]float64 {
+	if len(cfg.UpsellTypes) > 0 {
+		return cfg.UpsellTypes
+	}
+	return DefaultUpsellTypes
+}
+
+// UpsellSegmentMultiplier is how much more or less likely users in segment
+// are to accept an upsell than regular users
+func (cfg *Config) UpsellSegmentMultiplier(segment string) float64 {
+	if multiplier, ok := cfg.UpsellSegmentAcceptance[segment]; ok {
+		return multiplier
+	}
+	return DefaultUpsellSegmentAcceptance[segment]
+}
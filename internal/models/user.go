@@ -13,9 +13,19 @@ type User struct {
 	DietaryRestrictions []string  `json:"diet_restrictions"`
 	OrderFrequency      float64   `json:"order_frequency"`
 	LastOrderTime       time.Time `json:"last_order_time"`
+	HardToFindAddress   bool      `json:"hard_to_find_address"` // Partners struggle to find the drop-off
+	Rating              float64   `json:"rating"`               // Average rating given by delivery partners
+	TotalRatings        float64   `json:"total_ratings"`
 }
 
 type UserBehaviourUpdate struct {
 	UserID         string
 	OrderFrequency float64
 }
+
+// User segments by how often they order relative to the configured order frequency
+const (
+	UserSegmentOccasional = "occasional"
+	UserSegmentRegular    = "regular"
+	UserSegmentFrequent   = "frequent"
+)
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ViralSpike is a restaurant suddenly going viral on social media, the start
+// of which is emitted as ground truth. The demand boost starts at Magnitude
+// and halves every HalfLife.
+type ViralSpike struct {
+	RestaurantID string
+	Start        time.Time
+	Magnitude    float64
+	HalfLife     time.Duration
+}
@@ -0,0 +1,72 @@
+package models
+
+// WeatherCondition is the weather across the city, or one region of it, at a
+// point in the simulation
+type WeatherCondition struct {
+	Region        string  `json:"region"`        // Weather region name, empty for city-wide weather
+	Condition     string  `json:"condition"`     // One of the Weather constants
+	Temperature   float64 `json:"temperature"`   // Degrees Celsius
+	WindSpeed     float64 `json:"wind_speed"`    // km/h
+	Humidity      float64 `json:"humidity"`      // Relative humidity, percent
+	Precipitation float64 `json:"precipitation"` // mm/h
+}
+
+// WeatherConditions are every condition the simulation generates and keys its
+// weather effects on
+var WeatherConditions = []string{WeatherClear, WeatherCloudy, WeatherRain, WeatherSnow}
+
+// WeatherLabel is the name a weather condition is emitted under, its entry in
+// weather_condition_labels or else the condition itself
+func (cfg *Config) WeatherLabel(condition string) string {
+	if label, ok := cfg.WeatherConditionLabels[condition]; ok {
+		return label
+	}
+	return condition
+}
+
+// WeatherRegion is a part of the city with its own weather. Each location
+// belongs to the region whose centre is nearest.
+type WeatherRegion struct {
+	Name              string   `mapstructure:"name"`
+	Location          Location `mapstructure:"location"`           // Centre of the region
+	TemperatureOffset float64  `mapstructure:"temperature_offset"` // Degrees Celsius warmer than the city average, negative for cooler
+	RainChanceOffset  float64  `mapstructure:"rain_chance_offset"` // Added to the chance of rain, e.g. 0.1 for a wetter coast
+}
+
+const (
+	ClimateOceanic          = "oceanic"
+	ClimateHumidContinental = "humid_continental"
+	ClimateMediterranean    = "mediterranean"
+	ClimateDesert           = "desert"
+	ClimateTropical         = "tropical"
+	ClimateSubarctic        = "subarctic"
+)
+
+// ClimateProfile shapes the temperature curve over the year and the day
+type ClimateProfile struct {
+	MeanTemperature   float64 // Yearly mean, degrees Celsius
+	SeasonalAmplitude float64 // Difference between the yearly mean and the warmest month's mean
+	DiurnalRange      float64 // Difference between the warmest and coolest hour on a clear day
+}
+
+// ClimateProfiles are the built-in climates selectable with the climate setting
+var ClimateProfiles = map[string]ClimateProfile{
+	ClimateOceanic:          {MeanTemperature: 11, SeasonalAmplitude: 7, DiurnalRange: 7},
+	ClimateHumidContinental: {MeanTemperature: 9, SeasonalAmplitude: 14, DiurnalRange: 10},
+	ClimateMediterranean:    {MeanTemperature: 17, SeasonalAmplitude: 8, DiurnalRange: 10},
+	ClimateDesert:           {MeanTemperature: 24, SeasonalAmplitude: 11, DiurnalRange: 16},
+	ClimateTropical:         {MeanTemperature: 27, SeasonalAmplitude: 1.5, DiurnalRange: 8},
+	ClimateSubarctic:        {MeanTemperature: -2, SeasonalAmplitude: 18, DiurnalRange: 9},
+}
+
+// defaultClimate is the temperature curve used when no climate is configured
+var defaultClimate = ClimateProfile{MeanTemperature: 11, SeasonalAmplitude: 8, DiurnalRange: 8}
+
+// ClimateProfile is the configured climate, or the default temperate curve if
+// none is set
+func (cfg *Config) ClimateProfile() ClimateProfile {
+	if profile, ok := ClimateProfiles[cfg.Climate]; ok {
+		return profile
+	}
+	return defaultClimate
+}
@@ -200,7 +200,7 @@ func (p *PostgresOutput) BatchInsertRestaurants(restaurants []*models.Restaurant
 
 	for i, restaurant := range restaurants {
 		offlineStatus := "DISABLED"
-		if restaurant.Offline == "true" {
+		if restaurant.Offline == "true" || restaurant.Offline == models.RestaurantOfflineEnabled {
 			offlineStatus = "ENABLED"
 		}
 
@@ -866,13 +866,14 @@ func isRetryableError(err error) bool {
 func topicToTable(topic string) string {
 	tableMap := map[string]string{
 		// order related events
-		"order_placed_events":       "orders",
-		"order_preparation_events":  "order_event",
-		"order_ready_events":        "order_event",
-		"order_pickup_events":       "order_event",
-		"order_delivery_events":     "order_event",
-		"order_cancellation_events": "order_event",
-		"order_in_transit_events":   "order_event",
+		"order_placed_events":        "orders",
+		"order_preparation_events":   "order_event",
+		"order_ready_events":         "order_event",
+		"order_pickup_events":        "order_event",
+		"order_delivery_events":      "order_event",
+		"order_cancellation_events":  "order_event",
+		"order_in_transit_events":    "order_event",
+		"order_prep_progress_events": "order_event",
 
 		// delivery performance events
 		"delivery_status_check_events":       "delivery_partner_event",
@@ -892,6 +893,7 @@ func topicToTable(topic string) string {
 		// user/customer events
 		"user_behaviour_events":  "customer_event",
 		"user_preference_events": "customer_event",
+		"abandoned_cart_events":  "customer_event",
 
 		// review events
 		"review_events": "review_event",
@@ -929,8 +931,8 @@ func topicToTable(topic string) string {
 		//"efficiency_metrics_events":   "fact_efficiency_metrics",
 		//"performance_metrics_events":  "fact_performance_metrics",
 		//
-		//// system facts
-		//"notification_events":  "fact_notification",
+		// system facts
+		"notification_events": "fact_notification",
 		//"communication_events": "fact_communication",
 		//"system_events":        "fact_system_log",
 	}
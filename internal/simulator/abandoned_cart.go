@@ -0,0 +1,74 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"time"
+)
+
+// occasionalUserFactor marks users ordering less often than this fraction of
+// the configured order frequency as occasional, who abandon carts more often
+const occasionalUserFactor = 0.65
+
+// abandonCart decides whether a user who is about to check out basket b at
+// drops out instead. An abandoned cart is queued as its own event and never
+// becomes an order, so it does not touch restaurant metrics. Baskets well
+// above what the user normally spends, high delivery fees and occasional
+// users all make abandonment more likely.
+func (s *Simulator) abandonCart(user *models.User, b *basket, at time.Time) bool {
+	if s.Config.AbandonedCartRate <= 0 || len(b.items) == 0 {
+		return false
+	}
+	subtotal := s.calculateSubtotal(b.items)
+	fee := s.calculateDeliveryFee(subtotal, s.calculateDistance(b.restaurant.Location, user.Location)).Total()
+
+	probability := s.Config.AbandonedCartRate
+	reason := models.AbandonReasonChangedMind
+	if usual := s.usualOrderTotal(user); usual > 0 && subtotal > usual {
+		probability *= math.Min(subtotal/usual, 3)
+		reason = models.AbandonReasonBasketTotal
+	}
+	if subtotal > 0 && fee/subtotal > 0.25 {
+		probability *= 1.5
+		reason = models.AbandonReasonDeliveryFee
+	}
+	if user.OrderFrequency < occasionalUserFactor*s.Config.OrderFrequency {
+		probability *= 1.5
+	}
+	if s.Rng.Float64() >= math.Min(probability, 0.9) {
+		return false
+	}
+
+	s.EventQueue.Enqueue(&models.Event{
+		Time: at,
+		Type: models.EventAbandonCart,
+		Data: &models.AbandonedCart{
+			ID:           generateID(),
+			UserID:       user.ID,
+			RestaurantID: b.restaurant.ID,
+			Items:        b.items,
+			Subtotal:     math.Round(subtotal*100) / 100,
+			DeliveryFee:  math.Round(fee*100) / 100,
+			Reason:       reason,
+			AbandonedAt:  at,
+		},
+	})
+	return true
+}
+
+// usualOrderTotal is the average total of the user's recent orders, or 0 if
+// they have not ordered yet
+func (s *Simulator) usualOrderTotal(user *models.User) float64 {
+	orders := s.OrdersByUser[user.ID]
+	if window := s.Config.UserBehaviourWindow; window > 0 && len(orders) > window {
+		orders = orders[len(orders)-window:]
+	}
+	if len(orders) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, order := range orders {
+		total += order.TotalAmount
+	}
+	return total / float64(len(orders))
+}
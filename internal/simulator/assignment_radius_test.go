@@ -0,0 +1,45 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestAcceptanceRadiusIndependentOfNearThreshold(t *testing.T) {
+	s := NewSimulator(&models.Config{PartnerAcceptanceRadius: 2})
+	s.CurrentTime = time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	restaurant := models.Location{Lat: 53.00, Lon: -2.2}
+	// roughly 1.1 km and 4.4 km north of the restaurant
+	s.DeliveryPartners = []*models.DeliveryPartner{
+		{ID: "close", Status: models.PartnerStatusAvailable, CurrentLocation: models.Location{Lat: 53.01, Lon: -2.2}},
+		{ID: "far", Status: models.PartnerStatusAvailable, CurrentLocation: models.Location{Lat: 53.04, Lon: -2.2}},
+	}
+	ids := func() []string {
+		var got []string
+		for _, p := range s.getAvailablePartnersNear(restaurant) {
+			got = append(got, p.ID)
+		}
+		return got
+	}
+
+	for _, threshold := range []float64{5, 50} {
+		s.Config.NearLocationThreshold = threshold
+		if got := ids(); len(got) != 1 || got[0] != "close" {
+			t.Errorf("near_location_threshold %.0f: available partners = %v, want only the one inside the 2 km acceptance radius", threshold, got)
+		}
+	}
+
+	s.Config.PartnerAcceptanceRadius = 0
+	if got := ids(); len(got) != 2 {
+		t.Errorf("with the acceptance radius disabled, available partners = %v, want both", got)
+	}
+
+	// the acceptance radius does not widen a narrower availability check
+	s.Config.PartnerAcceptanceRadius = 10
+	s.Config.NearLocationThreshold = 1
+	if got := ids(); len(got) != 1 || got[0] != "close" {
+		t.Errorf("near_location_threshold 1: available partners = %v, want only the close partner", got)
+	}
+}
@@ -0,0 +1,104 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// competitorDensity is the number of competing restaurants per square km
+// within competition_radius of a restaurant. What counts as a competitor
+// depends on competition_cuisine_overlap.
+func (s *Simulator) competitorDensity(restaurant *models.Restaurant) float64 {
+	radius := s.Config.CompetitionRadius
+	if radius <= 0 {
+		return 0
+	}
+	competitors := 0
+	for _, other := range s.Restaurants {
+		if other.ID == restaurant.ID || !s.cuisinesCompete(restaurant, other) {
+			continue
+		}
+		if s.calculateDistance(restaurant.Location, other.Location) <= radius {
+			competitors++
+		}
+	}
+	return float64(competitors) / (math.Pi * radius * radius)
+}
+
+// cuisinesCompete reports whether two restaurants compete for the same customers
+func (s *Simulator) cuisinesCompete(a, b *models.Restaurant) bool {
+	switch s.Config.CompetitionCuisineOverlap {
+	case models.CuisineOverlapAny:
+		return true
+	case models.CuisineOverlapPrimary:
+		return len(a.Cuisines) > 0 && len(b.Cuisines) > 0 && a.Cuisines[0] == b.Cuisines[0]
+	default:
+		for _, cuisine := range a.Cuisines {
+			if contains(b.Cuisines, cuisine) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// competitionPriceFactor scales a restaurant's menu prices for local
+// competition: denser than competition_reference_density trends cheaper,
+// sparser trends dearer, by competition_price_pressure per competitor per
+// square km. Prices move by at most 30% either way.
+func (s *Simulator) competitionPriceFactor(restaurant *models.Restaurant) float64 {
+	if s.Config.CompetitionPricePressure <= 0 {
+		return 1
+	}
+	density := s.competitorDensity(restaurant)
+	factor := 1 - s.Config.CompetitionPricePressure*(density-s.Config.CompetitionReferenceDensity)
+	return math.Max(0.7, math.Min(1.3, factor))
+}
+
+// competitorPriceDemandFactor scales a restaurant's selection score by how
+// its menu prices compare with its competitors': the competitors' average
+// menu price over its own, raised to cross_price_sensitivity. A restaurant
+// undercutting its neighbours wins orders from them, one pricier than them
+// loses orders to them. The factor stays between 1/3 and 3.
+func (s *Simulator) competitorPriceDemandFactor(restaurant *models.Restaurant) float64 {
+	sensitivity := s.Config.CrossPriceSensitivity
+	if sensitivity <= 0 {
+		return 1
+	}
+	own := s.averageMenuPrice(restaurant)
+	if own <= 0 {
+		return 1
+	}
+
+	var total float64
+	competitors := 0
+	for _, other := range s.Restaurants {
+		if other.ID == restaurant.ID || restaurantOffline(other) || !s.cuisinesCompete(restaurant, other) {
+			continue
+		}
+		if s.calculateDistance(restaurant.Location, other.Location) > s.Config.CompetitionRadius {
+			continue
+		}
+		if price := s.averageMenuPrice(other); price > 0 {
+			total += price
+			competitors++
+		}
+	}
+	if competitors == 0 {
+		return 1
+	}
+	factor := math.Pow(total/float64(competitors)/own, sensitivity)
+	return math.Max(1.0/3, math.Min(3, factor))
+}
+
+// averageMenuPrice is the mean current price of a restaurant's menu items
+func (s *Simulator) averageMenuPrice(restaurant *models.Restaurant) float64 {
+	if len(restaurant.MenuItems) == 0 {
+		return 0
+	}
+	var total float64
+	for _, itemID := range restaurant.MenuItems {
+		total += s.itemPrice(itemID)
+	}
+	return total / float64(len(restaurant.MenuItems))
+}
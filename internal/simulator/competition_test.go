@@ -0,0 +1,88 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestCrowdedRestaurantsPriceBelowIsolatedOnes(t *testing.T) {
+	s := NewSimulator(&models.Config{
+		CompetitionRadius:           1,
+		CompetitionCuisineOverlap:   models.CuisineOverlapShared,
+		CompetitionPricePressure:    0.5,
+		CompetitionReferenceDensity: 0.5,
+	})
+	centre := models.Location{Lat: 53.0, Lon: -2.18}
+	add := func(id string, loc models.Location, cuisine string) *models.Restaurant {
+		r := &models.Restaurant{ID: id, Location: loc, Cuisines: []string{cuisine}}
+		s.Restaurants[id] = r
+		return r
+	}
+
+	// a pizzeria on a street of nine others, all within a few hundred metres
+	crowded := add("crowded", centre, "pizza")
+	for i := 0; i < 9; i++ {
+		add(fmt.Sprintf("rival-%d", i), models.Location{Lat: centre.Lat + float64(i)*0.0005, Lon: centre.Lon + 0.0005}, "pizza")
+	}
+	// a burger bar nearby doesn't compete with it
+	add("burgers", models.Location{Lat: centre.Lat, Lon: centre.Lon + 0.001}, "burgers")
+	// and a pizzeria out of town has no competitors at all
+	isolated := add("isolated", models.Location{Lat: centre.Lat + 0.2, Lon: centre.Lon}, "pizza")
+
+	wantDensity := 9 / math.Pi
+	if got := s.competitorDensity(crowded); math.Abs(got-wantDensity) > 1e-9 {
+		t.Errorf("crowded density = %.3f competitors/km², want %.3f", got, wantDensity)
+	}
+	if got := s.competitorDensity(isolated); got != 0 {
+		t.Errorf("isolated density = %.3f, want 0", got)
+	}
+
+	crowdedFactor := s.competitionPriceFactor(crowded)
+	isolatedFactor := s.competitionPriceFactor(isolated)
+	if crowdedFactor >= 1 || isolatedFactor <= 1 {
+		t.Errorf("price factors crowded %.3f, isolated %.3f; want crowded below 1 and isolated above", crowdedFactor, isolatedFactor)
+	}
+	if want := 1 - 0.5*(wantDensity-0.5); math.Abs(crowdedFactor-math.Max(0.7, want)) > 1e-9 {
+		t.Errorf("crowded price factor = %.3f, want %.3f", crowdedFactor, math.Max(0.7, want))
+	}
+	if want := 1.25; math.Abs(isolatedFactor-want) > 1e-9 {
+		t.Errorf("isolated price factor = %.3f, want %.3f", isolatedFactor, want)
+	}
+}
+
+func TestPriceCutShiftsDemandFromCompetitor(t *testing.T) {
+	s := NewSimulator(&models.Config{
+		CompetitionRadius:         2,
+		CompetitionCuisineOverlap: models.CuisineOverlapShared,
+		CrossPriceSensitivity:     1,
+	})
+	addPizzeria := func(id string, lon, price float64) *models.Restaurant {
+		itemID := id + "-margherita"
+		s.MenuItems[itemID] = &models.MenuItem{ID: itemID, RestaurantID: id, Price: price}
+		r := &models.Restaurant{ID: id, Location: models.Location{Lat: 53.0, Lon: lon}, Cuisines: []string{"pizza"}, MenuItems: []string{itemID}}
+		s.Restaurants[id] = r
+		return r
+	}
+	a := addPizzeria("a", -2.180, 10)
+	b := addPizzeria("b", -2.185, 10)
+
+	if fa, fb := s.competitorPriceDemandFactor(a), s.competitorPriceDemandFactor(b); fa != 1 || fb != 1 {
+		t.Fatalf("equal prices give factors %.3f and %.3f, want 1", fa, fb)
+	}
+
+	// a cuts its prices by 20%
+	s.MenuItems["a-margherita"].Price = 8
+	fa, fb := s.competitorPriceDemandFactor(a), s.competitorPriceDemandFactor(b)
+	if math.Abs(fa-1.25) > 1e-9 || math.Abs(fb-0.8) > 1e-9 {
+		t.Errorf("after a 20%% cut, factors are %.3f and %.3f; want 1.25 for the cheaper and 0.8 for the dearer", fa, fb)
+	}
+
+	// a much deeper cut is capped
+	s.MenuItems["a-margherita"].Price = 1
+	if fa := s.competitorPriceDemandFactor(a); fa != 3 {
+		t.Errorf("factor after a 90%% cut = %.3f, want it capped at 3", fa)
+	}
+}
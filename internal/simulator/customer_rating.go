@@ -0,0 +1,42 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// rateCustomer generates the rating a delivery partner gives the customer on
+// delivery. Partners are generous by default, reward good tips, and mark
+// customers down for long trips and addresses that are hard to find.
+func (s *Simulator) rateCustomer(order *models.Order, user *models.User) float64 {
+	rating := 4.6
+
+	subtotal := s.calculateSubtotal(order.Items)
+	switch {
+	case order.Tip == 0:
+		rating -= 0.3
+	case subtotal > 0 && order.Tip/subtotal >= 0.1:
+		rating += 0.4
+	}
+
+	if restaurant := s.getRestaurant(order.RestaurantID); restaurant != nil {
+		distance := s.calculateDistance(restaurant.Location, user.Location)
+		rating -= math.Min(math.Max(distance-5, 0)*0.1, 0.8)
+	}
+
+	if user.HardToFindAddress {
+		rating -= 1.0
+	}
+
+	rating += s.Rng.NormFloat64() * 0.3
+	return math.Round(math.Max(1, math.Min(5, rating))*2) / 2
+}
+
+// recordCustomerRating stores a partner's rating on the order and folds it
+// into the customer's average rating
+func (s *Simulator) recordCustomerRating(order *models.Order, user *models.User) {
+	rating := s.rateCustomer(order, user)
+	order.CustomerRating = rating
+	user.Rating = (user.Rating*user.TotalRatings + rating) / (user.TotalRatings + 1)
+	user.TotalRatings++
+}
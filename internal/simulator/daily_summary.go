@@ -0,0 +1,191 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+// dailyTotals accumulates an entity's activity since local midnight. For a
+// partner, placed counts the orders they finished, delivered or cancelled.
+type dailyTotals struct {
+	orderCounts
+	late               int
+	revenue, ratingSum float64
+	ratings            int
+}
+
+// dailySummaries holds the running totals for the current local day
+type dailySummaries struct {
+	day         time.Time
+	restaurants map[string]*dailyTotals
+	partners    map[string]*dailyTotals
+}
+
+func (s *Simulator) summarises(entityType string) bool {
+	return contains(s.Config.DailySummaryEntities, entityType)
+}
+
+// dailyTotalsFor returns the running totals for an entity, or nil if that
+// entity type isn't summarised
+func (s *Simulator) dailyTotalsFor(entityType, id string) *dailyTotals {
+	if id == "" || !s.summarises(entityType) {
+		return nil
+	}
+	if s.daily.restaurants == nil {
+		s.daily.restaurants = make(map[string]*dailyTotals)
+		s.daily.partners = make(map[string]*dailyTotals)
+	}
+	totals := s.daily.restaurants
+	if entityType == models.SummaryEntityPartners {
+		totals = s.daily.partners
+	}
+	t, ok := totals[id]
+	if !ok {
+		t = &dailyTotals{}
+		totals[id] = t
+	}
+	return t
+}
+
+func (s *Simulator) recordDailyOrderPlaced(order *models.Order) {
+	if t := s.dailyTotalsFor(models.SummaryEntityRestaurants, order.RestaurantID); t != nil {
+		t.placed++
+		t.revenue += order.TotalAmount
+	}
+}
+
+func (s *Simulator) recordDailyDelivery(order *models.Order) {
+	late := order.ActualDeliveryTime.After(order.EstimatedDeliveryTime)
+	if t := s.dailyTotalsFor(models.SummaryEntityRestaurants, order.RestaurantID); t != nil {
+		t.completed++
+		if late {
+			t.late++
+		}
+	}
+	if t := s.dailyTotalsFor(models.SummaryEntityPartners, order.DeliveryPartnerID); t != nil {
+		t.placed++
+		t.completed++
+		t.revenue += order.DeliveryCost + order.Tip
+		if late {
+			t.late++
+		}
+	}
+}
+
+func (s *Simulator) recordDailyCancellation(order *models.Order) {
+	if t := s.dailyTotalsFor(models.SummaryEntityRestaurants, order.RestaurantID); t != nil {
+		t.cancelled++
+	}
+	if t := s.dailyTotalsFor(models.SummaryEntityPartners, order.DeliveryPartnerID); t != nil {
+		t.placed++
+		t.cancelled++
+	}
+}
+
+func (s *Simulator) recordDailyRating(review *models.Review) {
+	if t := s.dailyTotalsFor(models.SummaryEntityRestaurants, review.RestaurantID); t != nil && review.FoodRating > 0 {
+		t.ratingSum += review.FoodRating
+		t.ratings++
+	}
+	if t := s.dailyTotalsFor(models.SummaryEntityPartners, review.DeliveryPartnerID); t != nil && review.DeliveryRating > 0 {
+		t.ratingSum += review.DeliveryRating
+		t.ratings++
+	}
+}
+
+// scheduleDailySummaries emits the previous day's summaries once simulated
+// time passes local midnight, then starts a new day
+func (s *Simulator) scheduleDailySummaries() {
+	if len(s.Config.DailySummaryEntities) == 0 {
+		return
+	}
+	local := s.CurrentTime.In(s.timeLocation())
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	if s.daily.day.IsZero() {
+		s.daily.day = today
+		return
+	}
+	if !today.After(s.daily.day) {
+		return
+	}
+
+	for _, summary := range s.summariseDay() {
+		s.EventQueue.Enqueue(&models.Event{
+			Time: today,
+			Type: models.EventDailySummary,
+			Data: summary,
+		})
+	}
+	s.daily = dailySummaries{day: today}
+}
+
+// summariseDay builds a summary for each restaurant and partner with activity
+// on the current day, restaurants first
+func (s *Simulator) summariseDay() []*models.DailySummary {
+	var summaries []*models.DailySummary
+	for _, group := range []struct {
+		entityType string
+		totals     map[string]*dailyTotals
+	}{
+		{models.SummaryEntityRestaurants, s.daily.restaurants},
+		{models.SummaryEntityPartners, s.daily.partners},
+	} {
+		ids := make([]string, 0, len(group.totals))
+		for id := range group.totals {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			t := group.totals[id]
+			summary := &models.DailySummary{
+				EntityType:     group.entityType,
+				EntityID:       id,
+				Date:           s.daily.day,
+				Orders:         t.placed,
+				Completed:      t.completed,
+				Cancelled:      t.cancelled,
+				CompletionRate: t.completionRate(),
+				LateDeliveries: t.late,
+				Revenue:        math.Round(t.revenue*100) / 100,
+				Ratings:        t.ratings,
+			}
+			if t.ratings > 0 {
+				summary.AvgRating = t.ratingSum / float64(t.ratings)
+			}
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
+
+// writeOpenDailySummaries writes summaries for the day still in progress when
+// the simulation ends, marked partial, so the last day's activity isn't lost.
+// It returns how many were written.
+func (s *Simulator) writeOpenDailySummaries(output OutputDestination) int {
+	written := 0
+	for _, summary := range s.summariseDay() {
+		summary.Partial = true
+		msg, err := s.serializeEvent(models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventDailySummary,
+			Data: summary,
+		})
+		if err != nil {
+			log.Printf("Error serializing daily summary: %v", err)
+			continue
+		}
+		if err := output.WriteMessage(msg.Topic, msg.Message); err != nil {
+			log.Printf("Failed to write message: %v", err)
+			continue
+		}
+		written++
+	}
+	if written > 0 {
+		log.Printf("Wrote %d partial daily summaries for %s", written, s.daily.day.Format("2006-01-02"))
+	}
+	return written
+}
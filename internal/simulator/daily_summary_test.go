@@ -0,0 +1,43 @@
+package simulator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRunFlushesPartialDailySummary(t *testing.T) {
+	config := testConfig(t, map[string]interface{}{
+		"start_date":             "2024-03-01T18:00:00Z",
+		"end_date":               "2024-03-02T13:00:00Z",
+		"max_events":             0,
+		"daily_summary_entities": []string{"restaurants"},
+	})
+
+	summaries := make(map[string]int)
+	for _, m := range runRecorded(t, config) {
+		if m.topic != "restaurant_daily_summary_events" {
+			continue
+		}
+		var summary struct {
+			Date    string `json:"date"`
+			Partial bool   `json:"partial"`
+		}
+		if err := json.Unmarshal(m.msg, &summary); err != nil {
+			t.Fatal(err)
+		}
+		// the day that ended at midnight is complete, the one the run ended in is not
+		if wantPartial := summary.Date == "2024-03-02"; summary.Partial != wantPartial {
+			t.Errorf("summary for %s has partial %v, want %v", summary.Date, summary.Partial, wantPartial)
+		}
+		summaries[summary.Date]++
+	}
+
+	for _, date := range []string{"2024-03-01", "2024-03-02"} {
+		if summaries[date] == 0 {
+			t.Errorf("no restaurant summaries for %s", date)
+		}
+	}
+	if len(summaries) != 2 {
+		t.Errorf("summaries for %d days, want 2: %v", len(summaries), summaries)
+	}
+}
@@ -0,0 +1,50 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// fragilityScale is the order complexity at which fragility reaches about 0.63
+const fragilityScale = 4.0
+
+// calculateOrderComplexity adds up how awkward an order's items are to carry:
+// each item's prep complexity, with extra for drinks and desserts, which spill
+// and crush
+func (s *Simulator) calculateOrderComplexity(order *models.Order) float64 {
+	complexity := 0.0
+	for _, id := range order.Items {
+		item, ok := s.MenuItems[id]
+		if !ok {
+			complexity += 0.5
+			continue
+		}
+		complexity += item.PrepComplexity
+		switch item.Type {
+		case "drink":
+			complexity += 1
+		case "dessert":
+			complexity += 0.5
+		}
+	}
+	return complexity
+}
+
+// orderFragility is how hard the order is to deliver intact, from 0 to 1
+func (s *Simulator) orderFragility(order *models.Order) float64 {
+	return 1 - math.Exp(-s.calculateOrderComplexity(order)/fragilityScale)
+}
+
+// arrivedSpilled decides whether an order with a drink arrived spilled. It is
+// rare, and only likely at all for the most fragile orders.
+func (s *Simulator) arrivedSpilled(order *models.Order, fragility float64) bool {
+	if s.Config.SpillRate <= 0 {
+		return false
+	}
+	for _, id := range order.Items {
+		if item, ok := s.MenuItems[id]; ok && item.Type == "drink" {
+			return s.Rng.Float64() < s.Config.SpillRate*fragility
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package simulator
+
+import (
+	"fmt"
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"time"
+)
+
+// selectDeliveryInstruction picks the drop-off instruction for a new order and
+// the note the customer would type for it
+func (s *Simulator) selectDeliveryInstruction() (string, string) {
+	if len(s.Config.DeliveryInstructions) == 0 {
+		return models.InstructionNone, ""
+	}
+	roll := s.Rng.Float64()
+	for _, instruction := range s.Config.DeliveryInstructions {
+		if roll < instruction.Probability {
+			return instruction.Type, s.deliveryInstructionNote(instruction.Type)
+		}
+		roll -= instruction.Probability
+	}
+	return models.InstructionNone, ""
+}
+
+func (s *Simulator) deliveryInstructionNote(instructionType string) string {
+	switch instructionType {
+	case models.InstructionLeaveAtDoor:
+		return "Leave at door"
+	case models.InstructionHandToMe:
+		return "Hand it to me"
+	case models.InstructionCallOnArrival:
+		return "Call on arrival"
+	case models.InstructionGateCode:
+		return fmt.Sprintf("Gate code %04d", s.Rng.Intn(10000))
+	}
+	return ""
+}
+
+// deliveryHandlingTime is the extra time spent at the door following the
+// order's delivery instruction, varying between half and one and a half
+// times the instruction's average delay, plus time taken handing over a
+// fragile order carefully
+func (s *Simulator) deliveryHandlingTime(order *models.Order) time.Duration {
+	minutes := s.orderFragility(order) * s.Config.FragilityHandlingMinutes
+	for _, instruction := range s.Config.DeliveryInstructions {
+		if instruction.Type == order.DeliveryInstruction && instruction.DelayMinutes > 0 {
+			minutes += instruction.DelayMinutes * (0.5 + s.Rng.Float64())
+			break
+		}
+	}
+	return time.Duration(minutes * float64(time.Minute))
+}
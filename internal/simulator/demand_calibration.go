@@ -0,0 +1,93 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"time"
+)
+
+// meanUserFrequencyShare is the average share of order_frequency a user
+// orders at, as each user draws between 50% and 100% of it
+const meanUserFrequencyShare = 0.75
+
+// calibrateDemand scales initial_users, or order_frequency with
+// demand_calibration "frequency", so the run is expected to place about
+// target_orders orders between start_date and end_date, and logs the inputs
+// it settled on. It has to run before users are generated.
+func (s *Simulator) calibrateDemand() {
+	target := float64(s.Config.TargetOrders)
+	if target <= 0 {
+		return
+	}
+	users := float64(s.Config.InitialUsers)
+	before := s.estimateOrders(users, s.Config.OrderFrequency)
+
+	switch s.Config.DemandCalibration {
+	case models.DemandCalibrationFrequency:
+		// orders grow faster than linearly with frequency, see estimateOrders
+		low, high := 0.0, math.Max(s.Config.OrderFrequency, 0.01)
+		for s.estimateOrders(users, high) < target && high < 1e6 {
+			high *= 2
+		}
+		for i := 0; i < 60; i++ {
+			mid := (low + high) / 2
+			if s.estimateOrders(users, mid) < target {
+				low = mid
+			} else {
+				high = mid
+			}
+		}
+		s.Config.OrderFrequency = high
+	default:
+		perUser := s.estimateOrders(1, s.Config.OrderFrequency)
+		if perUser <= 0 {
+			log.Printf("Demand calibration skipped: order_frequency %.2f places no orders", s.Config.OrderFrequency)
+			return
+		}
+		s.Config.InitialUsers = int(math.Max(1, math.Round(target/perUser)))
+	}
+
+	log.Printf("Demand calibration: %d initial users ordering %.3f times a day were estimated to place %.0f orders between %s and %s; using %d initial users ordering %.3f times a day for an estimated %.0f orders against a target of %d",
+		int(users), s.Config.OrderFrequency, before, s.Config.StartDate.Format(time.RFC3339), s.Config.EndDate.Format(time.RFC3339),
+		s.Config.InitialUsers, s.Config.OrderFrequency, s.estimateOrders(float64(s.Config.InitialUsers), s.Config.OrderFrequency), s.Config.TargetOrders)
+}
+
+// estimateOrders is the expected number of orders over the run for the given
+// initial users and order frequency, stepping through it as the simulation
+// does. Each step users place orders with the peak-hour and weekend demand
+// multipliers, and each of those is placed twice: once when it is generated
+// and again when its PlaceOrder event is handled. That event schedules the
+// user's next order one order interval later, for that time of day and week,
+// and so does every follow-up after it and every user joining through
+// user_growth_rate. Local events, fees and outages are left out.
+func (s *Simulator) estimateOrders(initialUsers, frequency float64) float64 {
+	userFrequency := frequency * meanUserFrequencyShare
+	if initialUsers <= 0 || userFrequency <= 0 {
+		return 0
+	}
+	dailyGrowthRate := math.Pow(1+s.Config.UserGrowthRate, 1.0/365.0) - 1
+	stepHours := simulationTimeStep.Hours()
+
+	// due holds the follow-up orders expected in each step
+	due := make(map[int]float64)
+	users, orders := initialUsers, 0.0
+	step := 0
+	for t := s.Config.StartDate; t.Before(s.Config.EndDate); t = t.Add(simulationTimeStep) {
+		next := step + int(math.Max(1, math.Round(24/userFrequency*s.orderIntervalFactor(t)/stepHours)))
+		if s.Config.UserGrowthRate != 0 {
+			days := t.Sub(s.Config.StartDate).Hours() / 24
+			if grown := initialUsers * math.Pow(1+dailyGrowthRate, days); grown > users {
+				due[next] += grown - users
+				users = grown
+			}
+		}
+		generated := users * userFrequency * s.timeDemandFactor(t) / (24 * 60)
+		followUps := due[step]
+		delete(due, step)
+		orders += 2*generated + followUps
+		due[next] += generated + followUps
+		step++
+	}
+	return orders
+}
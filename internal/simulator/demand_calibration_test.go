@@ -0,0 +1,27 @@
+package simulator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalibratedRunPlacesTargetOrders(t *testing.T) {
+	const target = 1000
+	config := testConfig(t, map[string]interface{}{
+		"end_date":        "2024-03-01T12:00:00Z",
+		"order_frequency": 5,
+		"target_orders":   target,
+		"max_events":      0,
+	})
+	out := &recordingOutput{}
+	sim := NewSimulator(config)
+	sim.output = out
+	if err := sim.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	placed := sim.distances.Orders
+	if math.Abs(float64(placed-target)) > 0.15*target {
+		t.Errorf("calibrated run placed %d orders, want %d within 15%%", placed, target)
+	}
+}
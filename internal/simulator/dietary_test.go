@@ -0,0 +1,89 @@
+package simulator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestFillBasketFindsTheRestaurantARestrictedUserCanEatAt(t *testing.T) {
+	s := NewSimulator(&models.Config{Seed: 7, MaxDeliveryRadius: 10})
+	here := models.Location{Lat: 53.0, Lon: -2.18}
+	steakhouse := &models.Restaurant{ID: "steakhouse", Location: here, Rating: 5}
+	for _, course := range []string{"main course", "side dish", "drink", "appetizer", "dessert"} {
+		id := "steak-" + course
+		s.MenuItems[id] = &models.MenuItem{ID: id, RestaurantID: steakhouse.ID, Type: course, Price: 10, Popularity: 1}
+		steakhouse.MenuItems = append(steakhouse.MenuItems, id)
+	}
+	// the only vegan dish around is a dessert, outside the usual meal
+	bakery := &models.Restaurant{ID: "bakery", Location: here, Rating: 1, MenuItems: []string{"sorbet", "croissant"}}
+	s.MenuItems["sorbet"] = &models.MenuItem{ID: "sorbet", RestaurantID: bakery.ID, Type: "dessert", Price: 4, Popularity: 1, DietaryTags: []string{models.DietVegan}}
+	s.MenuItems["croissant"] = &models.MenuItem{ID: "croissant", RestaurantID: bakery.ID, Type: "main course", Price: 3, Popularity: 1}
+	s.Restaurants = map[string]*models.Restaurant{steakhouse.ID: steakhouse, bakery.ID: bakery}
+
+	user := &models.User{ID: "u1", Location: here, DietaryRestrictions: []string{"Vegan"}}
+	for i := 0; i < 200; i++ {
+		b := s.fillBasket(user)
+		if b == nil {
+			t.Fatal("no basket, want the bakery's vegan dessert")
+		}
+		if b.restaurant.ID != bakery.ID {
+			t.Fatalf("basket from %s, which serves nothing vegan", b.restaurant.ID)
+		}
+		if len(b.items) != 1 || b.items[0] != "sorbet" {
+			t.Fatalf("basket holds %v, want just the sorbet", b.items)
+		}
+	}
+
+	// with nothing vegan anywhere there is no basket rather than an empty one
+	delete(s.Restaurants, bakery.ID)
+	if b := s.fillBasket(user); b != nil {
+		t.Errorf("got a basket from %s holding %v, want none", b.restaurant.ID, b.items)
+	}
+}
+
+func TestRestrictedUsersNeverPlaceEmptyOrders(t *testing.T) {
+	out := &recordingOutput{}
+	sim := NewSimulator(testConfig(t, map[string]interface{}{
+		"end_date":        "2024-03-01T06:00:00Z",
+		"order_frequency": 2,
+		"max_events":      0,
+	}))
+	sim.output = out
+	if err := sim.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	restricted := make(map[string]bool)
+	for _, user := range sim.Users {
+		restricted[user.ID] = len(user.DietaryRestrictions) > 0
+	}
+
+	checked := 0
+	check := func(userID string, items []string) {
+		if !restricted[userID] {
+			return
+		}
+		checked++
+		if len(items) == 0 {
+			t.Errorf("user %s with dietary restrictions placed an order with no items", userID)
+		}
+	}
+	for _, order := range sim.Orders {
+		check(order.CustomerID, order.Items)
+	}
+	for _, m := range out.messages {
+		if m.topic != "order_placed_events" {
+			continue
+		}
+		var event OrderPlacedEvent
+		if err := json.Unmarshal(m.msg, &event); err != nil {
+			t.Fatal(err)
+		}
+		check(event.CustomerID, event.ItemIDs)
+	}
+	if checked == 0 {
+		t.Fatal("no orders from users with dietary restrictions; the run is too short to check")
+	}
+}
@@ -0,0 +1,153 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"hash/fnv"
+	"time"
+)
+
+// eventPartitionKey returns the key used to pin an event to a worker. Every
+// event in an order's lifecycle is keyed by the customer ID, so the PlaceOrder
+// event (which only carries the user) and the later events for the order it
+// creates always land on the same worker and are written in causal order.
+func eventPartitionKey(event *models.Event) string {
+	switch data := event.Data.(type) {
+	case *models.Order:
+		return data.CustomerID
+	case *models.User:
+		return data.ID
+	case *models.UserBehaviourUpdate:
+		return data.UserID
+	case *models.PartnerLocationUpdate:
+		return data.PartnerID
+	case *models.Restaurant:
+		return data.ID
+	case *models.Review:
+		return data.CustomerID
+	case *models.RestaurantMetrics:
+		return data.RestaurantID
+	case *models.PartnerShiftStats:
+		return data.PartnerID
+	case *models.AbandonedCart:
+		return data.UserID
+	case *models.PrepProgress:
+		return data.Order.CustomerID
+	case *models.FeaturedDish:
+		return data.RestaurantID
+	case *models.SupportTicket:
+		return data.CustomerID
+	case *models.DailySummary:
+		return data.EntityID
+	case *models.PartnerGhosting:
+		return data.CustomerID
+	case *models.PartnerDeactivation:
+		return data.PartnerID
+	case *models.Refund:
+		return data.CustomerID
+	case *models.PartnerPayout:
+		return data.PartnerID
+	case *models.Notification:
+		return data.UserID
+	case *models.RatingRecomputation:
+		return data.EntityID
+	case *models.RestaurantHoursChange:
+		return data.RestaurantID
+	case *models.PartnerCashDrop:
+		return data.PartnerID
+	case *models.HomepageFeature:
+		return data.RestaurantID
+	case *models.OrderAcceptance:
+		return data.CustomerID
+	case *models.PlatformOutage:
+		return data.ID
+	case *models.PartnerDualApp:
+		return data.PartnerID
+	case *models.OrderSettlement:
+		return data.CustomerID
+	case *models.ViralSpike:
+		return data.RestaurantID
+	case *models.OrderReviewLink:
+		return data.CustomerID
+	}
+	return event.Type
+}
+
+// workerIndex maps an event to one of numWorkers workers by hashing its partition key
+func workerIndex(event *models.Event, numWorkers int) int {
+	h := fnv.New32a()
+	h.Write([]byte(eventPartitionKey(event)))
+	return int(h.Sum32() % uint32(numWorkers))
+}
+
+// orderStatusRank orders statuses by how far through its lifecycle an order is.
+// Delivered and cancelled are both final.
+var orderStatusRank = map[string]int{
+	models.OrderStatusPlaced:    0,
+	models.OrderStatusPreparing: 1,
+	models.OrderStatusReady:     2,
+	models.OrderStatusPickedUp:  3,
+	models.OrderStatusInTransit: 4,
+	models.OrderStatusDelivered: 5,
+	models.OrderStatusCancelled: 5,
+}
+
+// lifecycleOrder is the order whose status a lifecycle event reports, or nil
+// for other events
+func lifecycleOrder(event models.Event) *models.Order {
+	switch event.Type {
+	case models.EventPrepareOrder, models.EventOrderReady, models.EventAssignDeliveryPartner,
+		models.EventPickUpOrder, models.EventOrderInTransit, models.EventCheckDeliveryStatus,
+		models.EventDeliverOrder, models.EventCancelOrder:
+		order, _ := event.Data.(*models.Order)
+		return order
+	case models.EventPrepProgress:
+		if progress, ok := event.Data.(*models.PrepProgress); ok {
+			return progress.Order
+		}
+	}
+	return nil
+}
+
+// staleOrderStatus reports whether order is behind a status already written
+// for it, which happens when an event was scheduled against a copy of the
+// order that has since moved on. Otherwise it records the status as written,
+// so an order's lifecycle events never go backwards in the output.
+func (s *Simulator) staleOrderStatus(order *models.Order) bool {
+	rank, ok := orderStatusRank[order.Status]
+	if !ok {
+		return false
+	}
+	if s.writtenStatus == nil {
+		s.writtenStatus = make(map[string]int)
+	}
+	if written, ok := s.writtenStatus[order.ID]; ok && rank < written {
+		return true
+	}
+	s.writtenStatus[order.ID] = rank
+	return false
+}
+
+// writtenStatusRetention is how long the status written for a finished order
+// is kept after it leaves Orders, long enough for events still queued against
+// copies of it to drain
+const writtenStatusRetention = 24 * time.Hour
+
+// orderDropped notes that a finished order has left Orders
+func (s *Simulator) orderDropped(orderID string) {
+	if s.droppedOrders == nil {
+		s.droppedOrders = make(map[string]time.Time)
+	}
+	s.droppedOrders[orderID] = s.CurrentTime
+}
+
+// forgetDroppedOrders stops tracking the written status of orders that left
+// Orders more than writtenStatusRetention ago, so it does not grow for the
+// whole run
+func (s *Simulator) forgetDroppedOrders() {
+	for id, droppedAt := range s.droppedOrders {
+		if s.CurrentTime.Sub(droppedAt) > writtenStatusRetention {
+			delete(s.writtenStatus, id)
+			delete(s.droppedOrders, id)
+		}
+	}
+}
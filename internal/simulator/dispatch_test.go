@@ -0,0 +1,102 @@
+package simulator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// lifecycleTopics are the topics whose events report an order's status
+var lifecycleTopics = map[string]bool{
+	"order_placed_events":                true,
+	"order_preparation_events":           true,
+	"order_prep_progress_events":         true,
+	"order_ready_events":                 true,
+	"delivery_partner_assignment_events": true,
+	"order_pickup_events":                true,
+	"order_in_transit_events":            true,
+	"delivery_status_check_events":       true,
+	"order_delivery_events":              true,
+	"order_cancellation_events":          true,
+}
+
+func TestRunWritesOrderStatusesInOrder(t *testing.T) {
+	config := testConfig(t, map[string]interface{}{
+		"start_date": "2024-03-01T10:00:00Z",
+		"end_date":   "2024-03-01T16:00:00Z",
+		"max_events": 0,
+		"workers":    8,
+	})
+
+	furthest := make(map[string]string)
+	delivered := 0
+	for _, m := range runRecorded(t, config) {
+		if !lifecycleTopics[m.topic] {
+			continue
+		}
+		var event struct {
+			ID         string `json:"id"`
+			OrderID    string `json:"orderId"`
+			OrderIDAlt string `json:"order_id"`
+			Status     string `json:"status"`
+		}
+		if err := json.Unmarshal(m.msg, &event); err != nil {
+			t.Fatalf("%s: %v", m.topic, err)
+		}
+		id, status := event.OrderID, event.Status
+		switch m.topic {
+		case "order_placed_events":
+			id, status = event.ID, models.OrderStatusPlaced
+		case "order_preparation_events":
+			id = event.OrderIDAlt
+		}
+		if id == "" {
+			t.Fatalf("%s event without an order ID: %s", m.topic, m.msg)
+		}
+
+		if previous, ok := furthest[id]; ok && orderStatusRank[status] < orderStatusRank[previous] {
+			t.Errorf("order %s went from %s back to %s on %s", id, previous, status, m.topic)
+		}
+		if previous, ok := furthest[id]; !ok || orderStatusRank[status] >= orderStatusRank[previous] {
+			furthest[id] = status
+		}
+		if m.topic == "order_delivery_events" {
+			delivered++
+		}
+	}
+	if delivered == 0 {
+		t.Fatal("no orders were delivered; the run is too short to check lifecycles")
+	}
+}
+
+func TestWrittenStatusIsForgottenADayAfterAnOrderFinishes(t *testing.T) {
+	s := NewSimulator(&models.Config{})
+	s.CurrentTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.Orders = []models.Order{
+		{ID: "done", Status: models.OrderStatusDelivered},
+		{ID: "active", Status: models.OrderStatusPreparing},
+	}
+	for i := range s.Orders {
+		s.staleOrderStatus(&s.Orders[i])
+	}
+
+	s.removeCompletedOrders()
+	s.CurrentTime = s.CurrentTime.Add(writtenStatusRetention)
+	if !s.staleOrderStatus(&models.Order{ID: "done", Status: models.OrderStatusReady}) {
+		t.Error("a stale copy of a finished order was written within the retention")
+	}
+
+	s.CurrentTime = s.CurrentTime.Add(simulationTimeStep)
+	s.removeCompletedOrders()
+	if _, ok := s.writtenStatus["done"]; ok {
+		t.Error("kept the written status of an order finished over a day ago")
+	}
+	if len(s.droppedOrders) != 0 {
+		t.Errorf("still tracking dropped orders %v", s.droppedOrders)
+	}
+	if _, ok := s.writtenStatus["active"]; !ok {
+		t.Error("forgot the written status of an active order")
+	}
+}
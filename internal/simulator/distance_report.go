@@ -0,0 +1,109 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"os"
+)
+
+// distanceBucketBounds are the upper bounds, in km, of the delivery distance
+// histogram; a final bucket catches everything beyond the last bound
+var distanceBucketBounds = []float64{1, 2, 3, 5, 10, 15, 20}
+
+// maxReportedOutOfRadius caps how many offending order IDs the report lists
+const maxReportedOutOfRadius = 100
+
+type distanceBucket struct {
+	Range  string `json:"range"`
+	Orders int    `json:"orders"`
+}
+
+// distanceReport collects restaurant-to-customer distances of every order
+// placed, to check restaurant selection stays within the delivery radius
+type distanceReport struct {
+	Orders            int              `json:"orders"`
+	MeanKm            float64          `json:"meanKm"`
+	MaxKm             float64          `json:"maxKm"`
+	MaxRadiusKm       float64          `json:"maxRadiusKm"`
+	OutOfRadius       int              `json:"outOfRadius"`
+	OutOfRadiusOrders []string         `json:"outOfRadiusOrders"`
+	Histogram         []distanceBucket `json:"histogram"`
+
+	totalKm float64
+}
+
+func newDistanceReport(maxRadius float64) *distanceReport {
+	report := &distanceReport{MaxRadiusKm: maxRadius}
+	lower := 0.0
+	for _, upper := range distanceBucketBounds {
+		report.Histogram = append(report.Histogram, distanceBucket{Range: fmt.Sprintf("%g-%g km", lower, upper)})
+		lower = upper
+	}
+	report.Histogram = append(report.Histogram, distanceBucket{Range: fmt.Sprintf(">%g km", lower)})
+	return report
+}
+
+func (r *distanceReport) add(orderID string, distance float64) {
+	r.Orders++
+	r.totalKm += distance
+	r.MaxKm = math.Max(r.MaxKm, distance)
+
+	bucket := len(distanceBucketBounds)
+	for i, upper := range distanceBucketBounds {
+		if distance <= upper {
+			bucket = i
+			break
+		}
+	}
+	r.Histogram[bucket].Orders++
+
+	if distance > r.MaxRadiusKm {
+		r.OutOfRadius++
+		if len(r.OutOfRadiusOrders) < maxReportedOutOfRadius {
+			r.OutOfRadiusOrders = append(r.OutOfRadiusOrders, orderID)
+		}
+	}
+}
+
+// recordDeliveryDistance adds a newly placed order to the distance report
+func (s *Simulator) recordDeliveryDistance(order *models.Order) {
+	restaurant := s.getRestaurant(order.RestaurantID)
+	if restaurant == nil {
+		return
+	}
+	if s.distances == nil {
+		s.distances = newDistanceReport(s.Config.MaxDeliveryRadius)
+	}
+	s.distances.add(order.ID, s.calculateDistance(restaurant.Location, orderLocation(order)))
+}
+
+// writeDistanceReport logs a summary of delivery distances at the end of a
+// run and, if distance_report_path is set, writes the full report as JSON
+func (s *Simulator) writeDistanceReport() error {
+	report := s.distances
+	if report == nil || report.Orders == 0 {
+		return nil
+	}
+	report.MeanKm = math.Round(report.totalKm/float64(report.Orders)*100) / 100
+	report.MaxKm = math.Round(report.MaxKm*100) / 100
+
+	log.Printf("Delivery distances: %d orders, mean %.2f km, max %.2f km", report.Orders, report.MeanKm, report.MaxKm)
+	if report.OutOfRadius > 0 {
+		log.Printf("Warning: %d orders exceed the %.1f km delivery radius", report.OutOfRadius, report.MaxRadiusKm)
+	}
+
+	if s.Config.DistanceReportPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode distance report: %w", err)
+	}
+	if err := os.WriteFile(s.Config.DistanceReportPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write distance report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"time"
+)
+
+// sampleDoorDwell draws how long the partner spends at the customer's
+// address finding the door and handing the order over, and records it on the
+// order. Dwell times are log-normal around door_dwell_time, longer for flats
+// and addresses in the urban core; 0 when door_dwell_time is unset.
+func (s *Simulator) sampleDoorDwell(order *models.Order) time.Duration {
+	if s.Config.DoorDwellTime <= 0 {
+		return 0
+	}
+	spread := s.Config.DoorDwellSpread
+	// centre the distribution so its mean is door_dwell_time
+	factor := math.Exp(s.Rng.NormFloat64()*spread - spread*spread/2)
+	if order.Address.Flat != "" || s.isUrbanArea(orderLocation(order)) {
+		factor *= s.Config.DoorDwellUrbanMultiplier
+	}
+	order.DoorDwell = time.Duration(float64(s.Config.DoorDwellTime) * factor).Round(time.Second)
+	return order.DoorDwell
+}
+
+// startHandover keeps a partner who has reached the customer busy at the door
+// until the order's actual delivery time, when its delivery event is emitted
+// and finishHandover frees them
+func (s *Simulator) startHandover(partner *models.DeliveryPartner, order *models.Order) {
+	partner.Status = models.PartnerStatusDelivering
+	partner.CurrentOrderID = order.ID
+	s.pingOnStatusChange(partner, order.ID)
+	s.EventQueue.Enqueue(&models.Event{
+		Time: order.ActualDeliveryTime,
+		Type: models.EventDeliverOrder,
+		Data: order,
+	})
+}
+
+// finishHandover makes the partner available again once the order is handed over
+func (s *Simulator) finishHandover(order *models.Order) {
+	partner := s.getDeliveryPartner(order.DeliveryPartnerID)
+	if partner == nil || partner.Status != models.PartnerStatusDelivering || partner.CurrentOrderID != order.ID {
+		return
+	}
+	partner.Status = models.PartnerStatusAvailable
+	partner.CurrentOrderID = ""
+	partner.LastUpdateTime = s.CurrentTime
+}
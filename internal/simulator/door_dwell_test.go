@@ -0,0 +1,111 @@
+package simulator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestDeliveryTimeIncludesDoorDwell(t *testing.T) {
+	s := NewSimulator(&models.Config{
+		Seed:                     42,
+		DoorDwellTime:            2 * time.Minute,
+		DoorDwellSpread:          0.3,
+		DoorDwellUrbanMultiplier: 1.5,
+	})
+	s.CurrentTime = time.Date(2024, 6, 3, 19, 0, 0, 0, time.UTC)
+	s.Users = []*models.User{{ID: "u"}}
+	s.DeliveryPartners = []*models.DeliveryPartner{{ID: "p", Status: models.PartnerStatusEnRouteDelivery, CurrentOrderID: "o"}}
+	order := &models.Order{ID: "o", CustomerID: "u", DeliveryPartnerID: "p", Status: models.OrderStatusInTransit}
+
+	s.handleDeliverOrder(order)
+	if order.Status != models.OrderStatusDelivered {
+		t.Fatalf("order status %q, want delivered", order.Status)
+	}
+	if order.DoorDwell <= 0 {
+		t.Fatalf("door dwell %s, want a positive dwell", order.DoorDwell)
+	}
+	if got := order.ActualDeliveryTime.Sub(s.CurrentTime); got != order.DoorDwell {
+		t.Errorf("delivery completed %s after arrival, want the %s door dwell", got, order.DoorDwell)
+	}
+}
+
+func TestPartnerStaysBusyUntilHandoverEnds(t *testing.T) {
+	s := NewSimulator(&models.Config{
+		Seed:                     42,
+		DoorDwellTime:            2 * time.Minute,
+		DoorDwellSpread:          0.3,
+		DoorDwellUrbanMultiplier: 1.5,
+	})
+	s.CurrentTime = time.Date(2024, 6, 3, 19, 0, 0, 0, time.UTC)
+	s.Users = []*models.User{{ID: "u"}}
+	partner := &models.DeliveryPartner{ID: "p", Status: models.PartnerStatusEnRouteDelivery, CurrentOrderID: "o"}
+	s.DeliveryPartners = []*models.DeliveryPartner{partner}
+	order := &models.Order{ID: "o", CustomerID: "u", DeliveryPartnerID: "p", Status: models.OrderStatusInTransit}
+
+	s.handleDeliverOrder(order)
+	if partner.Status == models.PartnerStatusAvailable {
+		t.Fatal("partner available on reaching the door, want them busy until the handover ends")
+	}
+
+	var delivery *models.Event
+	for !s.EventQueue.IsEmpty() {
+		if event := s.EventQueue.Dequeue(); event.Type == models.EventDeliverOrder {
+			delivery = event
+		}
+	}
+	if delivery == nil {
+		t.Fatal("no delivery event queued")
+	}
+	if !delivery.Time.Equal(order.ActualDeliveryTime) {
+		t.Errorf("delivery event at %s, want the actual delivery time %s", delivery.Time, order.ActualDeliveryTime)
+	}
+
+	s.CurrentTime = delivery.Time
+	s.handleDeliverOrder(delivery.Data.(*models.Order))
+	if partner.Status != models.PartnerStatusAvailable || partner.CurrentOrderID != "" {
+		t.Errorf("partner %s with order %q after the handover, want available", partner.Status, partner.CurrentOrderID)
+	}
+}
+
+func TestDoorDwellDistribution(t *testing.T) {
+	s := NewSimulator(&models.Config{
+		Seed:                     42,
+		CityLat:                  53.48,
+		CityLon:                  -2.24,
+		UrbanRadius:              3,
+		DoorDwellTime:            2 * time.Minute,
+		DoorDwellSpread:          0.3,
+		DoorDwellUrbanMultiplier: 1.5,
+	})
+	meanDwell := func(address models.Address) time.Duration {
+		const samples = 5000
+		var total time.Duration
+		for i := 0; i < samples; i++ {
+			total += s.sampleDoorDwell(&models.Order{Address: address})
+		}
+		return total / samples
+	}
+
+	tests := []struct {
+		name    string
+		address models.Address
+		want    time.Duration
+	}{
+		{"suburban house", models.Address{Latitude: 53.6, Longitude: -2.24}, 2 * time.Minute},
+		{"suburban flat", models.Address{Flat: "4B", Latitude: 53.6, Longitude: -2.24}, 3 * time.Minute},
+		{"urban house", models.Address{Latitude: 53.48, Longitude: -2.24}, 3 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := meanDwell(tt.address); math.Abs(got.Seconds()-tt.want.Seconds()) > 5 {
+			t.Errorf("%s: mean door dwell %s, want about %s", tt.name, got, tt.want)
+		}
+	}
+
+	s.Config.DoorDwellTime = 0
+	if got := s.sampleDoorDwell(&models.Order{}); got != 0 {
+		t.Errorf("door dwell with door_dwell_time unset = %s, want 0", got)
+	}
+}
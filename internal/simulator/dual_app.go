@@ -0,0 +1,40 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"time"
+)
+
+// partnerAbsenceOtherApp tags a partner absence spent on another app's job
+const partnerAbsenceOtherApp = "other_app"
+
+// scheduleDualApping takes idle partners offline while they work a job from
+// another delivery app: each is called away at partner_dual_app_rate per hour
+// and is gone for between half and one and a half times
+// partner_dual_app_duration. Partners busy with one of our orders stay.
+func (s *Simulator) scheduleDualApping() {
+	rate := s.Config.PartnerDualAppRate
+	if rate <= 0 {
+		return
+	}
+	chance := rate * simulationTimeStep.Hours()
+	for _, partner := range s.DeliveryPartners {
+		if partner == nil || partner.Status != models.PartnerStatusAvailable || s.Rng.Float64() >= chance {
+			continue
+		}
+		away := &models.PartnerDualApp{
+			PartnerID: partner.ID,
+			Start:     s.CurrentTime,
+			Until:     s.CurrentTime.Add(time.Duration((0.5 + s.Rng.Float64()) * float64(s.Config.PartnerDualAppDuration))),
+		}
+		partner.Status = models.PartnerStatusOffline
+		partner.OfflineUntil = away.Until
+		log.Printf("Partner %s busy on another app until %s", partner.ID, away.Until.Format(time.RFC3339))
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventPartnerDualApp,
+			Data: away,
+		})
+	}
+}
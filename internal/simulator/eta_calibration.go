@@ -0,0 +1,56 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+)
+
+// etaAccuracy accumulates how far delivery estimates were off, in minutes,
+// for one partner experience tier
+type etaAccuracy struct {
+	n, sumError, sumAbsError float64
+}
+
+// etaExperienceBias stretches a delivery estimate for the assigned partner's
+// experience when eta_experience_calibration is on, so the platform promises
+// new partners' customers a later arrival rather than having them overrun
+func (s *Simulator) etaExperienceBias(partner *models.DeliveryPartner) float64 {
+	if !s.Config.ETAExperienceCalibration || partner == nil {
+		return 1
+	}
+	return s.Config.ETAExperienceMultiplier(models.ExperienceTier(partner.Experience))
+}
+
+// recordETAAccuracy notes how far a delivered order's estimate was off, by
+// the experience tier of the partner who delivered it
+func (s *Simulator) recordETAAccuracy(order *models.Order) {
+	partner := s.getDeliveryPartner(order.DeliveryPartnerID)
+	if partner == nil || order.EstimatedDeliveryTime.IsZero() {
+		return
+	}
+	tier := models.ExperienceTier(partner.Experience)
+	if s.etaAccuracy == nil {
+		s.etaAccuracy = make(map[string]*etaAccuracy)
+	}
+	accuracy, ok := s.etaAccuracy[tier]
+	if !ok {
+		accuracy = &etaAccuracy{}
+		s.etaAccuracy[tier] = accuracy
+	}
+	minutesLate := order.ActualDeliveryTime.Sub(order.EstimatedDeliveryTime).Minutes()
+	accuracy.n++
+	accuracy.sumError += minutesLate
+	accuracy.sumAbsError += math.Abs(minutesLate)
+}
+
+func (s *Simulator) logETAAccuracy() {
+	for _, tier := range []string{models.ExperienceTierNew, models.ExperienceTierDeveloping, models.ExperienceTierExperienced} {
+		accuracy, ok := s.etaAccuracy[tier]
+		if !ok || accuracy.n == 0 {
+			continue
+		}
+		log.Printf("Delivery estimates for %s partners: %d orders, mean error %+.1f min, mean absolute error %.1f min",
+			tier, int(accuracy.n), accuracy.sumError/accuracy.n, accuracy.sumAbsError/accuracy.n)
+	}
+}
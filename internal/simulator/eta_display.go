@@ -0,0 +1,31 @@
+package simulator
+
+import (
+	"time"
+)
+
+// displayedETARange is a delivery estimate as the customer sees it at now: a
+// range eta_display_width wide around the time left, in minutes, with both
+// ends rounded to eta_display_bucket. Both are nil without eta_display_bucket.
+func (s *Simulator) displayedETARange(estimated, now time.Time) (*int32, *int32) {
+	bucket := s.Config.ETADisplayBucket
+	if bucket <= 0 || estimated.IsZero() {
+		return nil, nil
+	}
+	remaining := estimated.Sub(now)
+	if remaining < 0 {
+		remaining = 0
+	}
+	width := s.Config.ETADisplayWidth
+	low := (remaining - width/2).Round(bucket)
+	if low < 0 {
+		low = 0
+	}
+	high := (low + width).Round(bucket)
+	if high < low+bucket {
+		high = low + bucket
+	}
+
+	lowMinutes, highMinutes := int32(low.Minutes()), int32(high.Minutes())
+	return &lowMinutes, &highMinutes
+}
@@ -0,0 +1,18 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// enrichOrderPlacedEvent copies the restaurant's name and cuisines and the
+// user's segment onto an order placed event, as they stand when the order is
+// placed, so consumers don't need to join against entity data
+func (s *Simulator) enrichOrderPlacedEvent(placed *OrderPlacedEvent, order *models.Order, user *models.User) {
+	if restaurant := s.getRestaurant(order.RestaurantID); restaurant != nil {
+		name := restaurant.Name
+		placed.RestaurantName = &name
+		placed.RestaurantCuisines = append([]string(nil), restaurant.Cuisines...)
+	}
+	segment := s.userSegment(user)
+	placed.UserSegment = &segment
+}
@@ -0,0 +1,61 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"time"
+)
+
+// updateFeaturedDish ends an expired dish promotion or randomly starts
+// featuring one of the restaurant's menu items
+func (s *Simulator) updateFeaturedDish(restaurant *models.Restaurant) {
+	if restaurant.FeaturedItemID != "" {
+		if s.CurrentTime.Before(restaurant.FeaturedUntil) {
+			return
+		}
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventFeaturedDishEnded,
+			Data: &models.FeaturedDish{
+				RestaurantID: restaurant.ID,
+				MenuItemID:   restaurant.FeaturedItemID,
+			},
+		})
+		restaurant.FeaturedItemID = ""
+		restaurant.FeaturedUntil = time.Time{}
+		return
+	}
+
+	if s.Config.FeaturedDishDailyRate <= 0 || len(restaurant.MenuItems) == 0 {
+		return
+	}
+
+	// convert the daily rate into a per-time-step probability
+	stepProbability := s.Config.FeaturedDishDailyRate * simulationTimeStep.Hours() / 24
+	if s.Rng.Float64() >= stepProbability {
+		return
+	}
+
+	restaurant.FeaturedItemID = restaurant.MenuItems[s.Rng.Intn(len(restaurant.MenuItems))]
+	restaurant.FeaturedUntil = s.CurrentTime.Add(s.Config.FeaturedDishDuration)
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventFeaturedDishStarted,
+		Data: &models.FeaturedDish{
+			RestaurantID: restaurant.ID,
+			MenuItemID:   restaurant.FeaturedItemID,
+			Until:        restaurant.FeaturedUntil,
+		},
+	})
+	log.Printf("Restaurant %s featuring item %s until %s",
+		restaurant.ID, restaurant.FeaturedItemID, restaurant.FeaturedUntil.Format(time.RFC3339))
+}
+
+// featuredDishBoost returns the popularity multiplier for an item, boosted
+// while the restaurant is featuring it
+func (s *Simulator) featuredDishBoost(restaurant *models.Restaurant, item *models.MenuItem) float64 {
+	if restaurant.FeaturedItemID == "" || restaurant.FeaturedItemID != item.ID {
+		return 1.0
+	}
+	return s.Config.FeaturedDishBoost
+}
@@ -0,0 +1,54 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// frequentUserFactor marks users ordering more than this multiple of the
+// configured order frequency as frequent
+const frequentUserFactor = 1.5
+
+// userSegment buckets a user by how often they order
+func (s *Simulator) userSegment(user *models.User) string {
+	switch {
+	case user.OrderFrequency < occasionalUserFactor*s.Config.OrderFrequency:
+		return models.UserSegmentOccasional
+	case user.OrderFrequency > frequentUserFactor*s.Config.OrderFrequency:
+		return models.UserSegmentFrequent
+	default:
+		return models.UserSegmentRegular
+	}
+}
+
+// expectedDeliveryFee is the delivery cost a user expects to pay: what their
+// recent orders cost to deliver, or the fee on a typical basket if they have
+// not ordered yet
+func (s *Simulator) expectedDeliveryFee(user *models.User) float64 {
+	orders := s.OrdersByUser[user.ID]
+	if window := s.Config.UserBehaviourWindow; window > 0 && len(orders) > window {
+		orders = orders[len(orders)-window:]
+	}
+	if len(orders) == 0 {
+		subtotal := (s.Config.SmallOrderThreshold + s.Config.FreeDeliveryThreshold) / 2
+		return s.calculateDeliveryFee(subtotal, 0).Total()
+	}
+	total := 0.0
+	for _, order := range orders {
+		total += order.DeliveryCost
+	}
+	return total / float64(len(orders))
+}
+
+// feeDemandFactor scales a user's order probability for what delivery costs
+// them. Fees above fee_elasticity_reference_fee suppress demand and cheaper
+// delivery lifts it, more so for segments with a higher elasticity.
+func (s *Simulator) feeDemandFactor(user *models.User) float64 {
+	elasticity := s.Config.FeeElasticity[s.userSegment(user)]
+	reference := s.Config.FeeElasticityReferenceFee
+	if elasticity <= 0 || reference <= 0 {
+		return 1
+	}
+	change := (s.expectedDeliveryFee(user) - reference) / reference
+	return math.Min(math.Exp(-elasticity*change), 2)
+}
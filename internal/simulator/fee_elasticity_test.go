@@ -0,0 +1,93 @@
+package simulator
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// feeElasticitySimulator has one user of each segment whose past orders all
+// cost fee to deliver
+func feeElasticitySimulator(fee float64) (*Simulator, map[string]*models.User) {
+	s := NewSimulator(&models.Config{
+		OrderFrequency:            1,
+		FeeElasticityReferenceFee: 3,
+		FeeElasticity: map[string]float64{
+			models.UserSegmentOccasional: 1.5,
+			models.UserSegmentRegular:    1,
+			models.UserSegmentFrequent:   0.5,
+		},
+	})
+	s.OrdersByUser = make(map[string][]models.Order)
+	users := map[string]*models.User{
+		models.UserSegmentOccasional: {ID: "occasional", OrderFrequency: 0.3},
+		models.UserSegmentRegular:    {ID: "regular", OrderFrequency: 1},
+		models.UserSegmentFrequent:   {ID: "frequent", OrderFrequency: 3},
+	}
+	for _, user := range users {
+		s.OrdersByUser[user.ID] = []models.Order{{DeliveryCost: fee}}
+	}
+	return s, users
+}
+
+func TestFeeDemandFactorFollowsElasticity(t *testing.T) {
+	s, users := feeElasticitySimulator(4.5)
+	for segment, user := range users {
+		if got := s.userSegment(user); got != segment {
+			t.Fatalf("user %s is in segment %s, want %s", user.ID, got, segment)
+		}
+		// a fee 50% above the reference suppresses demand by exp(-elasticity*0.5)
+		want := math.Exp(-s.Config.FeeElasticity[segment] * 0.5)
+		if got := s.feeDemandFactor(user); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s demand factor at a 50%% higher fee = %.4f, want %.4f", segment, got, want)
+		}
+	}
+
+	occasional := s.feeDemandFactor(users[models.UserSegmentOccasional])
+	frequent := s.feeDemandFactor(users[models.UserSegmentFrequent])
+	if occasional >= frequent {
+		t.Errorf("occasional users (%.3f) should be put off more than frequent ones (%.3f)", occasional, frequent)
+	}
+}
+
+func TestFeeDemandFactorLiftIsCapped(t *testing.T) {
+	s, users := feeElasticitySimulator(0)
+	s.Config.FeeElasticity[models.UserSegmentOccasional] = 10
+	if got := s.feeDemandFactor(users[models.UserSegmentOccasional]); got != 2 {
+		t.Errorf("demand factor with free delivery = %v, want it capped at 2", got)
+	}
+	if got := s.feeDemandFactor(users[models.UserSegmentRegular]); got <= 1 {
+		t.Errorf("free delivery should lift demand, got factor %v", got)
+	}
+}
+
+func TestHigherFeesPlaceFewerOrders(t *testing.T) {
+	placed := func(fee float64) map[string]int {
+		s, users := feeElasticitySimulator(fee)
+		s.Rng = rand.New(rand.NewSource(42))
+		s.CurrentTime = time.Date(2024, 3, 5, 15, 0, 0, 0, time.UTC) // an off-peak weekday
+		counts := make(map[string]int)
+		for i := 0; i < 200000; i++ {
+			for _, segment := range []string{models.UserSegmentOccasional, models.UserSegmentRegular, models.UserSegmentFrequent} {
+				if s.shouldPlaceOrder(users[segment]) {
+					counts[segment]++
+				}
+			}
+		}
+		return counts
+	}
+
+	cheap, dear := placed(3), placed(6)
+	for segment := range cheap {
+		if dear[segment] >= cheap[segment] {
+			t.Errorf("%s users placed %d orders at a 6.00 fee and %d at 3.00, want fewer at the higher fee",
+				segment, dear[segment], cheap[segment])
+		}
+	}
+	if len(cheap) != 3 {
+		t.Fatalf("orders placed by %d segments at the reference fee, want all 3", len(cheap))
+	}
+}
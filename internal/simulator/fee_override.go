@@ -0,0 +1,37 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"strings"
+)
+
+// orderCharges are the tax and service fee included in an order's total and
+// the discount taken off it
+type orderCharges struct {
+	TaxRate        float64
+	Tax            float64
+	ServiceFeeRate float64
+	ServiceFee     float64
+	Discount       float64
+}
+
+// restaurantRates is the tax rate and service fee percentage charged on
+// orders from restaurant: the global rates, replaced by those of the first
+// fee_regions area it lies in, replaced in turn by its own entry in
+// restaurant_fee_overrides
+func (s *Simulator) restaurantRates(restaurant *models.Restaurant) (float64, float64) {
+	taxRate, serviceFee := s.Config.TaxRate, s.Config.ServiceFeePercentage
+	if restaurant == nil {
+		return taxRate, serviceFee
+	}
+	for _, region := range s.Config.FeeRegions {
+		if s.calculateDistance(restaurant.Location, region.Location) <= region.RadiusKm {
+			taxRate, serviceFee = region.Apply(taxRate, serviceFee)
+			break
+		}
+	}
+	if override, ok := s.Config.RestaurantFeeOverrides[strings.ToLower(restaurant.ID)]; ok {
+		taxRate, serviceFee = override.Apply(taxRate, serviceFee)
+	}
+	return taxRate, serviceFee
+}
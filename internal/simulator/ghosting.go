@@ -0,0 +1,74 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"time"
+)
+
+// maybeScheduleGhosting decides, as a partner accepts an order, whether they
+// will go silent before picking it up. If so the no-show is queued for
+// 5–20 minutes later.
+func (s *Simulator) maybeScheduleGhosting(partner *models.DeliveryPartner, order *models.Order) {
+	partner.AcceptedAssignments++
+	if s.Rng.Float64() >= s.Config.PartnerGhostRate {
+		return
+	}
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime.Add(time.Duration(5+s.Rng.Intn(16)) * time.Minute),
+		Type: models.EventPartnerGhosted,
+		Data: &models.PartnerGhosting{
+			PartnerID:    partner.ID,
+			OrderID:      order.ID,
+			CustomerID:   order.CustomerID,
+			RestaurantID: order.RestaurantID,
+			AssignedAt:   s.CurrentTime,
+		},
+	})
+}
+
+// handlePartnerGhosted takes a partner who stopped responding offline and
+// frees their order for reassignment. Nothing happens if the partner already
+// collected the order or it has moved on to someone else.
+func (s *Simulator) handlePartnerGhosted(ghosting *models.PartnerGhosting) {
+	// the order as it stands now, not as it was when the partner accepted it
+	order := s.getOrderByID(ghosting.OrderID)
+	if order == nil || order.DeliveryPartnerID != ghosting.PartnerID {
+		return
+	}
+	switch order.Status {
+	case models.OrderStatusPickedUp, models.OrderStatusInTransit, models.OrderStatusDelivered, models.OrderStatusCancelled:
+		return
+	}
+	partner := s.getDeliveryPartner(ghosting.PartnerID)
+	if partner == nil {
+		return
+	}
+
+	partner.Status = models.PartnerStatusOffline
+	partner.CurrentOrderID = ""
+	partner.OfflineUntil = s.CurrentTime.Add(time.Duration(2+s.Rng.Intn(5)) * time.Hour)
+	partner.Ghostings++
+	partner.Rating = updateRating(partner.Rating, 1, s.Config.PartnerRatingAlpha)
+
+	order.DeliveryPartnerID = ""
+	ghosting.Ghosted = true
+	log.Printf("Partner %s stopped responding to order %s, releasing it for reassignment", partner.ID, order.ID)
+}
+
+// returnOfflinePartners brings partners who went offline back once their time away is up
+func (s *Simulator) returnOfflinePartners() {
+	for _, partner := range s.DeliveryPartners {
+		if partner != nil && partner.Status == models.PartnerStatusOffline && !s.CurrentTime.Before(partner.OfflineUntil) {
+			partner.Status = models.PartnerStatusAvailable
+			partner.LastUpdateTime = s.CurrentTime
+		}
+	}
+}
+
+// partnerReliability is the share of accepted orders a partner saw through,
+// smoothed so one no-show doesn't dominate a new partner's record
+func partnerReliability(partner *models.DeliveryPartner) float64 {
+	const priorAssignments = 20.0
+	return 1 - float64(partner.Ghostings)/(float64(partner.AcceptedAssignments)+priorAssignments)
+}
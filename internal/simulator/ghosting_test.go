@@ -0,0 +1,45 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestGhostingReleasesTheCurrentOrder(t *testing.T) {
+	s := NewSimulator(&models.Config{Seed: 1})
+	s.CurrentTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	partner := &models.DeliveryPartner{ID: "p1", Status: models.PartnerStatusEnRoutePickup, CurrentOrderID: "o1"}
+	s.DeliveryPartners = []*models.DeliveryPartner{partner}
+	s.Orders = []models.Order{{ID: "o1", CustomerID: "u1", Status: models.OrderStatusPlaced}}
+
+	// the partner accepts a copy of the order, as assignment events carry
+	accepted := s.Orders[0]
+	accepted.DeliveryPartnerID = partner.ID
+	s.Orders[0].DeliveryPartnerID = partner.ID
+	ghosting := &models.PartnerGhosting{PartnerID: partner.ID, OrderID: accepted.ID, CustomerID: accepted.CustomerID, AssignedAt: s.CurrentTime}
+
+	// the order moves on in s.Orders after the copy was taken
+	s.Orders[0].Status = models.OrderStatusPreparing
+	s.handlePartnerGhosted(ghosting)
+	if !ghosting.Ghosted {
+		t.Fatal("partner did not ghost a preparing order")
+	}
+	if got := s.Orders[0].DeliveryPartnerID; got != "" {
+		t.Errorf("order still assigned to %s, want it released for reassignment", got)
+	}
+	if partner.Status != models.PartnerStatusOffline {
+		t.Errorf("partner status %s, want offline", partner.Status)
+	}
+
+	// a partner who has since collected the order doesn't ghost it
+	s.Orders[0].DeliveryPartnerID = "p2"
+	s.DeliveryPartners = append(s.DeliveryPartners, &models.DeliveryPartner{ID: "p2", Status: models.PartnerStatusEnRoutePickup, CurrentOrderID: "o1"})
+	s.Orders[0].Status = models.OrderStatusPickedUp
+	late := &models.PartnerGhosting{PartnerID: "p2", OrderID: "o1", CustomerID: "u1", AssignedAt: s.CurrentTime}
+	s.handlePartnerGhosted(late)
+	if late.Ghosted {
+		t.Error("partner ghosted an order they had already picked up")
+	}
+}
@@ -0,0 +1,45 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"time"
+)
+
+// metersPerDegreeLat is the length of one degree of latitude
+const metersPerDegreeLat = 111320.0
+
+// reportedPartnerPing is the location and update time the platform receives
+// for a partner ping, as a phone GPS would report it: offset by
+// gps_noise_meters of position error, stamped by a device clock off by up to
+// gps_clock_skew, and now and then a repeat of the previous ping. ok is false
+// for pings that drop out. The partner's true position is left untouched, and
+// with the defaults the ping is reported exactly.
+func (s *Simulator) reportedPartnerPing(update *models.PartnerLocationUpdate) (models.Location, time.Time, bool) {
+	reported := update.NewLocation
+	reportedAt := s.CurrentTime
+	cfg := s.Config
+	if cfg.GPSNoiseMeters <= 0 && cfg.GPSDropoutRate <= 0 && cfg.GPSStaleRate <= 0 && cfg.GPSClockSkew <= 0 {
+		return reported, reportedAt, true
+	}
+
+	roll := s.Rng.Float64()
+	if roll < cfg.GPSDropoutRate {
+		return models.Location{}, time.Time{}, false
+	}
+	if last, ok := s.reportedLocations[update.PartnerID]; ok && roll < cfg.GPSDropoutRate+cfg.GPSStaleRate {
+		reported = last
+	} else if cfg.GPSNoiseMeters > 0 {
+		reported.Lat += s.Rng.NormFloat64() * cfg.GPSNoiseMeters / metersPerDegreeLat
+		reported.Lon += s.Rng.NormFloat64() * cfg.GPSNoiseMeters / (metersPerDegreeLat * math.Max(0.01, math.Cos(update.NewLocation.Lat*math.Pi/180)))
+	}
+	if cfg.GPSClockSkew > 0 {
+		reportedAt = reportedAt.Add(time.Duration(s.Rng.NormFloat64() * float64(cfg.GPSClockSkew)))
+	}
+
+	if s.reportedLocations == nil {
+		s.reportedLocations = make(map[string]models.Location)
+	}
+	s.reportedLocations[update.PartnerID] = reported
+	return reported, reportedAt, true
+}
@@ -48,8 +48,8 @@ func (s *Simulator) generateReviews() {
 	for _, order := range s.Orders {
 		if order.Status == "delivered" && s.shouldGenerateReview(&order) {
 			review := s.createReview(&order)
+			s.updateRatings(&review)
 			s.Reviews = append(s.Reviews, review)
-			s.updateRatings(review)
 		}
 	}
 }
@@ -60,6 +60,12 @@ func (s *Simulator) shouldGenerateReview(order *models.Order) bool {
 		return false
 	}
 
+	// a reminded order the user still hasn't reviewed converts at the
+	// reminder's rate
+	if order.ReviewReminderID != "" {
+		return s.Rng.Float64() < s.Config.ReviewReminderLift
+	}
+
 	// base probability of generating a review
 	baseProbability := 0.3
 
@@ -101,7 +107,8 @@ func (s *Simulator) shouldGenerateReview(order *models.Order) bool {
 
 func (s *Simulator) createReview(order *models.Order) models.Review {
 	// select a random review from our data for the food rating and comment
-	reviewData := s.Config.ReviewData[s.Rng.Intn(len(s.Config.ReviewData))]
+	comments := s.reviewComments()
+	reviewData := comments[s.Rng.Intn(len(comments))]
 
 	// generate food rating based on whether the review was liked or not
 	var foodRating float64
@@ -110,17 +117,31 @@ func (s *Simulator) createReview(order *models.Order) models.Review {
 	} else {
 		foodRating = 1 + s.Rng.Float64()*2 // Random rating between 1 and 3
 	}
+	foodRating = s.applyFoodRatingBias(order, foodRating)
 
 	// calculate delivery rating based on delivery performance
 	deliveryRating := s.calculateDeliveryRating(order)
 
-	// calculate overall rating
-	overallRating := (foodRating + deliveryRating) / 2
+	// weigh the experience against what the order cost
+	if s.Config.ReviewPriceSentimentStrength > 0 {
+		foodRating = s.adjustRatingForPrice(order, foodRating, deliveryRating)
+	}
+	// keep the comment's sentiment in line with the adjusted rating
+	if liked := foodRating >= 3; liked != reviewData.Liked {
+		if matching, ok := s.pickReviewData(liked); ok {
+			reviewData = matching
+		}
+	}
 
-	// adjust the comment to include delivery feedback
-	comment := s.adjustCommentWithDeliveryFeedback(reviewData.Comment, deliveryRating)
+	comment := s.composeReviewComment(order, reviewData, deliveryRating)
+	if s.Config.MinReviewWords > 0 {
+		comment = s.ensureReviewLength(order, comment, reviewData.Liked, deliveryRating)
+	}
+	if s.Rng.Float64() < s.Config.RatingOnlyReviewRate {
+		comment = ""
+	}
 
-	return models.Review{
+	review := models.Review{
 		ID:                generateID(),
 		OrderID:           order.ID,
 		CustomerID:        order.CustomerID,
@@ -128,24 +149,41 @@ func (s *Simulator) createReview(order *models.Order) models.Review {
 		DeliveryPartnerID: order.DeliveryPartnerID,
 		FoodRating:        foodRating,
 		DeliveryRating:    deliveryRating,
-		OverallRating:     overallRating,
 		Comment:           comment,
 		CreatedAt:         s.CurrentTime,
 		UpdatedAt:         s.CurrentTime,
 		IsIgnored:         false,
 	}
+	s.omitReviewRatings(&review)
+	s.rateItems(order, &review)
+	s.recordSegmentRatings(&review)
+	s.recordCuisineRatings(&review)
+	return review
 }
 
-func (s *Simulator) updateRatings(review models.Review) {
+func (s *Simulator) updateRatings(review *models.Review) {
+	s.recordDailyRating(review)
+	s.updateItemPopularity(review)
+
 	// update restaurant rating
-	restaurant := s.getRestaurant(review.RestaurantID)
-	restaurant.Rating = updateRating(restaurant.Rating, review.FoodRating, s.Config.RestaurantRatingAlpha)
-	restaurant.TotalRatings++
+	if review.FoodRating > 0 {
+		restaurant := s.getRestaurant(review.RestaurantID)
+		s.recordRestaurantRatingBaseline(restaurant)
+		if s.Config.ReputationRecoveryEnabled {
+			s.updateRestaurantReputation(restaurant, review)
+		} else {
+			restaurant.Rating = updateRating(restaurant.Rating, review.FoodRating, s.Config.RestaurantRatingAlpha)
+		}
+		restaurant.TotalRatings++
+	}
 
 	// update delivery partner rating
-	partner := s.getDeliveryPartner(review.DeliveryPartnerID)
-	partner.Rating = updateRating(partner.Rating, review.DeliveryRating, s.Config.PartnerRatingAlpha)
-	partner.TotalRatings++
+	if review.DeliveryRating > 0 {
+		partner := s.getDeliveryPartner(review.DeliveryPartnerID)
+		s.recordRatingBaseline(partner.ID, partner.Rating, partner.TotalRatings)
+		partner.Rating = updateRating(partner.Rating, review.DeliveryRating, s.Config.PartnerRatingAlpha)
+		partner.TotalRatings++
+	}
 }
 
 func (s *Simulator) calculateDeliveryRating(order *models.Order) float64 {
@@ -178,46 +216,55 @@ func (s *Simulator) calculateDeliveryRating(order *models.Order) float64 {
 	// add some randomness (±0.5 stars)
 	rating := baseRating + (s.Rng.Float64() - 0.5)
 
+	// blend in how the partner behaved, so on-time deliveries can still rate
+	// poorly and late ones well
+	if weight := math.Min(s.Config.DeliveryBehaviourWeight, 1); weight > 0 {
+		rating = (1-weight)*rating + weight*s.sampleDeliveryBehaviourRating(order)
+	}
+
 	// ensure rating is between 1 and 5
 	return math.Max(1, math.Min(5, rating))
 }
 
-func (s *Simulator) adjustCommentWithDeliveryFeedback(originalComment string, deliveryRating float64) string {
-	deliveryComments := []string{
-		"Delivery was lightning fast! ",
-		"Arrived earlier than expected. ",
-		"Delivery was on time. ",
-		"Delivery was a bit slow. ",
-		"The wait for delivery was too long. ",
-		"Extremely slow delivery. ",
+// sampleDeliveryBehaviourRating rates a partner's conduct on a delivery,
+// regardless of timing. Experienced partners are more likely to handle the
+// order carefully, follow the delivery instructions and be polite.
+func (s *Simulator) sampleDeliveryBehaviourRating(order *models.Order) float64 {
+	experience := 0.5
+	if partner := s.getDeliveryPartner(order.DeliveryPartnerID); partner != nil {
+		experience = partner.Experience
 	}
 
-	var deliveryComment string
-	switch {
-	case deliveryRating >= 4.5:
-		deliveryComment = deliveryComments[0]
-	case deliveryRating >= 4.0:
-		deliveryComment = deliveryComments[1]
-	case deliveryRating >= 3.5:
-		deliveryComment = deliveryComments[2]
-	case deliveryRating >= 2.5:
-		deliveryComment = deliveryComments[3]
-	case deliveryRating >= 1.5:
-		deliveryComment = deliveryComments[4]
-	default:
-		deliveryComment = deliveryComments[5]
+	// careful handling, followed instructions, politeness
+	factorWeights := []float64{0.4, 0.3, 0.3}
+	rating := 1.0
+	for _, weight := range factorWeights {
+		if s.Rng.Float64() < 0.7+0.25*experience {
+			rating += 4 * weight
+		}
 	}
+	return rating
+}
+
+func (s *Simulator) adjustCommentWithDeliveryFeedback(originalComment string, deliveryRating float64) string {
+	deliveryComment := s.deliveryFeedbackPhrase(deliveryRating)
 
 	// randomly decide whether to prepend or append the delivery comment
 	if s.Rng.Float64() < 0.5 {
-		return deliveryComment + originalComment
+		return joinReviewText(deliveryComment, originalComment)
 	} else {
-		return originalComment + " " + deliveryComment
+		return joinReviewText(originalComment, deliveryComment)
 	}
 }
 
 func (s *Simulator) updateRestaurantStatus() {
 	for i, restaurant := range s.Restaurants {
+		s.updateKitchenIncident(restaurant)
+		s.updateFeaturedDish(restaurant)
+		if s.Config.ReputationRecoveryEnabled {
+			s.recoverRestaurantReputation(restaurant)
+		}
+		s.learnPrepTime(restaurant)
 		s.Restaurants[i].PrepTime = s.adjustPrepTime(restaurant)
 		s.Restaurants[i].PickupEfficiency = s.adjustPickupEfficiency(restaurant)
 		s.EventQueue.Enqueue(&models.Event{
@@ -239,6 +286,9 @@ func (s *Simulator) getRestaurant(restaurantID string) *models.Restaurant {
 func (s *Simulator) getNearbyRestaurants(userLocation models.Location, radius float64) []*models.Restaurant {
 	var nearbyRestaurants []*models.Restaurant
 	for _, restaurant := range s.Restaurants {
+		if restaurantOffline(restaurant) {
+			continue
+		}
 		if distance := s.calculateDistance(userLocation, restaurant.Location); distance <= radius {
 			nearbyRestaurants = append(nearbyRestaurants, restaurant)
 		}
@@ -260,14 +310,25 @@ func (s *Simulator) selectRestaurant(user *models.User) *models.Restaurant {
 
 	if len(nearbyRestaurants) == 0 {
 		// If no restaurants nearby, expand the search radius
-		nearbyRestaurants = s.getNearbyRestaurants(user.Location, 10.0)
+		nearbyRestaurants = s.getNearbyRestaurants(user.Location, s.Config.MaxDeliveryRadius)
 	}
 
+	// Restaurants featured on the homepage are shown to everyone they can deliver to
+	nearbyRestaurants = s.addHomepageFeatured(nearbyRestaurants, user.Location)
+	nearbyRestaurants = s.withoutRejectedRestaurant(nearbyRestaurants, user)
+	// and users only consider restaurants with something they can eat
+	nearbyRestaurants = s.servingUser(nearbyRestaurants, user)
+
 	// If still no restaurants, return a random restaurant (fallback)
 	if len(nearbyRestaurants) == 0 {
 		keys := make([]string, 0, len(s.Restaurants))
-		for k := range s.Restaurants {
-			keys = append(keys, k)
+		for k, restaurant := range s.Restaurants {
+			if !restaurantOffline(restaurant) && s.servesUser(restaurant, user) {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			return nil
 		}
 		return s.Restaurants[keys[s.Rng.Intn(len(keys))]]
 	}
@@ -314,22 +375,46 @@ func (s *Simulator) calculateRestaurantScore(restaurant *models.Restaurant, user
 	// Base score is the restaurant's rating
 	score := restaurant.Rating
 
-	// Adjust score based on user preferences
+	// Adjust score based on user preferences, which weigh more for cuisines in season
 	for _, cuisine := range restaurant.Cuisines {
 		if contains(user.Preferences, cuisine) {
-			score += 1.0
+			score += s.seasonalCuisineModifier(cuisine)
 		}
 	}
 
 	// Adjust score based on distance (closer is better)
+	// Distance surcharges put users off further restaurants: each unit of surcharge counts as an extra km
 	distance := s.calculateDistance(user.Location, restaurant.Location)
-	score += 5.0 / (1.0 + distance) // This will add between 0 and 5 to the score, with closer restaurants getting a higher boost
+	score += 5.0 / (1.0 + distance + s.Config.DistanceFee(distance)) // This will add between 0 and 5 to the score, with closer restaurants getting a higher boost
+
+	// Give newly opened restaurants a chance to build up ratings
+	score += s.newRestaurantBoost(restaurant)
 
 	// Adjust score based on time of day (e.g., breakfast places in the morning)
-	if isBreakfastTime(s.CurrentTime) && contains(restaurant.Cuisines, "Breakfast") {
+	if isBreakfastTime(s.localTime(s.CurrentTime)) && contains(restaurant.Cuisines, "Breakfast") {
 		score += 2.0
 	}
 
+	// Scale by how busy the restaurant's cuisines usually are at this hour (a bar at 9am scores low)
+	score *= s.cuisineDemandMultiplier(restaurant)
+
+	// and by how much its cuisines appeal at this time of year
+	score *= s.seasonalCuisineMultiplier(restaurant)
+
+	// Restaurants that cancel accepted orders lose business
+	score *= calculateReliabilityScore(restaurant)
+
+	// Customers drift toward restaurants cheaper than their competitors
+	score *= s.competitorPriceDemandFactor(restaurant)
+
+	// Promoted restaurants draw far more attention than their organic standing
+	if s.homepageFeatured(restaurant) {
+		score *= s.Config.HomepageFeatureBoost
+	}
+
+	// and a restaurant gone viral draws a rush that fades over a few days
+	score *= s.viralityBoost(restaurant)
+
 	// Adjust score based on restaurant's recent order volume (popularity boost)
 	recentOrderCount := s.getRecentOrderCount(restaurant.ID)
 	score += float64(recentOrderCount) * 0.1 // Small boost for each recent order
@@ -337,6 +422,36 @@ func (s *Simulator) calculateRestaurantScore(restaurant *models.Restaurant, user
 	return score
 }
 
+// newRestaurantBoost is the extra selection score a restaurant gets during its
+// first days on the platform, fading out as it collects ratings
+func (s *Simulator) newRestaurantBoost(restaurant *models.Restaurant) float64 {
+	if s.Config.NewRestaurantBoost <= 0 || restaurant.OpenedAt.IsZero() {
+		return 0
+	}
+	if s.CurrentTime.Sub(restaurant.OpenedAt) > time.Duration(s.Config.NewRestaurantBoostDays)*24*time.Hour {
+		return 0
+	}
+	remaining := 1.0
+	if s.Config.NewRestaurantBoostRatings > 0 {
+		remaining = math.Max(0, 1-restaurant.TotalRatings/s.Config.NewRestaurantBoostRatings)
+	}
+	return s.Config.NewRestaurantBoost * remaining
+}
+
+// cuisineDemandMultiplier is the strongest hourly demand multiplier among a
+// restaurant's cuisines at the current time
+func (s *Simulator) cuisineDemandMultiplier(restaurant *models.Restaurant) float64 {
+	if len(restaurant.Cuisines) == 0 {
+		return 1
+	}
+	hour := s.localTime(s.CurrentTime).Hour()
+	multiplier := 0.0
+	for _, cuisine := range restaurant.Cuisines {
+		multiplier = math.Max(multiplier, s.Config.CuisineDemandMultiplier(cuisine, hour))
+	}
+	return multiplier
+}
+
 func (s *Simulator) calculateDistance(loc1, loc2 models.Location) float64 {
 	// convert latitude and longitude from degrees to radians
 	lat1 := degreesToRadians(loc1.Lat)
@@ -374,12 +489,17 @@ func (s *Simulator) updateTrafficConditions() {
 }
 
 func (s *Simulator) generateTrafficDensity(t time.Time) float64 {
-	baseTraffic := 0.5 + 0.5*math.Sin(float64(t.Hour())/24*2*math.Pi)
+	baseTraffic := 0.5 + 0.5*math.Sin(float64(s.localTime(t).Hour())/24*2*math.Pi)
 	randomFactor := 1 + (s.Rng.Float64()-0.5)*s.Config.TrafficVariability
 	return baseTraffic * randomFactor
 }
 
 func (s *Simulator) generateOrders() {
+	if s.platformDown() {
+		// nobody can order until the outage ends
+		return
+	}
+
 	var pgOutput *output.PostgresOutput
 	if s.Config.OutputTypes != nil && contains(s.Config.OutputTypes, "postgres") {
 		var err error
@@ -397,12 +517,34 @@ func (s *Simulator) generateOrders() {
 
 	for _, user := range s.Users {
 		if s.shouldPlaceOrder(user) {
-			order := s.createOrder(user)
-			s.assignDeliveryPartner(order)
+			b := s.fillBasket(user)
+			if b == nil {
+				continue
+			}
+			placedAt := s.orderPlacementTime(time.Time{})
+			if s.abandonCart(user, b, placedAt) {
+				continue
+			}
+			order := s.createOrder(user, b)
+			placeOrderAt(order, placedAt)
+			s.scheduleAcceptance(order, s.getRestaurant(order.RestaurantID))
+			s.recordDeliveryDistance(order)
+			s.recordDailyOrderPlaced(order)
+			s.recordMetricsOrderPlaced(order)
+			if placedAt.After(s.CurrentTime) {
+				// placed later in the step, so look for a partner once it is
+				s.EventQueue.Enqueue(&models.Event{
+					Time: placedAt,
+					Type: models.EventAssignDeliveryPartner,
+					Data: order,
+				})
+			} else {
+				s.assignDeliveryPartner(order)
+			}
 			s.Orders = append(s.Orders, *order)
 			orderBatch = append(orderBatch, order)
 			s.EventQueue.Enqueue(&models.Event{
-				Time: s.CurrentTime,
+				Time: order.OrderPlacedAt,
 				Type: models.EventPlaceOrder,
 				Data: user,
 			})
@@ -427,38 +569,90 @@ func (s *Simulator) addOrder(order models.Order) {
 	s.OrdersByUser[order.CustomerID] = append(s.OrdersByUser[order.CustomerID], order)
 }
 
-func (s *Simulator) createOrder(user *models.User) *models.Order {
+// basket is what a user is about to check out: the restaurant they chose and
+// the items picked from its menu, or the earlier order they are repeating
+type basket struct {
+	restaurant *models.Restaurant
+	items      []string
+	previous   *models.Order
+}
+
+// fillBasket chooses the restaurant and items for a user's next order, or
+// returns nil when there is no restaurant they can order from
+func (s *Simulator) fillBasket(user *models.User) *basket {
+	if previous := s.reorder(user); previous != nil {
+		// the same basket from the same restaurant, without browsing
+		return &basket{
+			restaurant: s.getRestaurant(previous.RestaurantID),
+			items:      append([]string(nil), previous.Items...),
+			previous:   previous,
+		}
+	}
 	restaurant := s.selectRestaurant(user)
+	if restaurant == nil {
+		return nil
+	}
 	items := s.selectMenuItems(restaurant, user)
-	totalAmount := s.calculateTotalAmount(items)
+	if len(items) == 0 {
+		return nil
+	}
+	return &basket{restaurant: restaurant, items: items}
+}
+
+// createOrder prices the user's basket into an order from the basket's restaurant
+func (s *Simulator) createOrder(user *models.User, b *basket) *models.Order {
+	restaurant, items := b.restaurant, b.items
+	var upsell *models.Upsell
+	if b.previous == nil {
+		items, upsell = s.offerUpsell(restaurant, user, items)
+	}
+	items, toppedUp := s.enforceSubtotalLimits(restaurant, user, items)
+	distance := s.calculateDistance(restaurant.Location, user.Location)
+	totalAmount, deliveryFee, charges := s.calculateTotalAmount(restaurant, items, distance)
 	prepTime := s.estimatePrepTime(restaurant, items)
-	deliveryCost := s.calculateDeliveryFee(totalAmount)
 
 	order := &models.Order{
-		ID:            generateID(),
-		CustomerID:    user.ID,
-		RestaurantID:  restaurant.ID,
-		Items:         items,
-		TotalAmount:   totalAmount,
-		DeliveryCost:  deliveryCost,
-		OrderPlacedAt: s.CurrentTime,
-		PrepStartTime: s.CurrentTime.Add(time.Minute * time.Duration(s.Rng.Intn(5))),
-		Status:        "placed",
-		PaymentMethod: s.selectPaymentMethod(),
+		ID:             generateID(),
+		CustomerID:     user.ID,
+		RestaurantID:   restaurant.ID,
+		Items:          items,
+		TotalAmount:    totalAmount,
+		DeliveryCost:   deliveryFee.Total(),
+		DistanceFee:    deliveryFee.Distance,
+		TaxRate:        charges.TaxRate,
+		TaxAmount:      charges.Tax,
+		ServiceFeeRate: charges.ServiceFeeRate,
+		ServiceFee:     charges.ServiceFee,
+		DiscountAmount: charges.Discount,
+		ToppedUp:       toppedUp,
+		Upsell:         upsell,
+		Tip:            s.sampleTip(s.calculateSubtotal(items)),
+		OrderPlacedAt:  s.CurrentTime,
+		PrepStartTime:  s.CurrentTime.Add(time.Minute * time.Duration(s.Rng.Intn(5))),
+		Status:         "placed",
+		PaymentMethod:  s.selectPaymentMethod(restaurant),
 		Address: models.Address{
 			Latitude:  user.Location.Lat,
 			Longitude: user.Location.Lon,
 		},
 	}
 
+	order.DeliveryInstruction, order.DeliveryNote = s.selectDeliveryInstruction()
+	order.HomepageFeatured = s.homepageFeatured(restaurant)
+	order.Viral = s.viral(restaurant)
 	order.PickupTime = order.PrepStartTime.Add(time.Minute * time.Duration(prepTime))
+	if b.previous != nil {
+		order.ReorderOf = b.previous.ID
+	}
 	return order
 }
 
-func (s *Simulator) createAndAddOrder(user *models.User) (*models.Order, error) {
-	// select a restaurant
-	restaurant := s.selectRestaurant(user)
-	if restaurant == nil {
+func (s *Simulator) createAndAddOrder(user *models.User, placedAt time.Time) (*models.Order, error) {
+	defer delete(s.rejectedRestaurants, user.ID)
+
+	// select a restaurant and fill the basket
+	b := s.fillBasket(user)
+	if b == nil {
 		// no suitable restaurant found, maybe schedule a retry later
 		s.EventQueue.Enqueue(&models.Event{
 			Time: s.CurrentTime.Add(15 * time.Minute),
@@ -467,10 +661,20 @@ func (s *Simulator) createAndAddOrder(user *models.User) (*models.Order, error)
 		})
 		return nil, fmt.Errorf("no suitable restaurant found")
 	}
+	restaurant := b.restaurant
+	if s.abandonCart(user, b, placedAt) {
+		// the user dropped out at checkout, so there is no order
+		return nil, nil
+	}
 
 	// create a new order
-	order := s.createOrder(user)
-	order.RestaurantID = restaurant.ID
+	order := s.createOrder(user, b)
+	placeOrderAt(order, placedAt)
+	s.maybeAddSecondRestaurant(order, restaurant, user)
+	accepted := s.scheduleAcceptance(order, restaurant)
+	s.recordDeliveryDistance(order)
+	s.recordDailyOrderPlaced(order)
+	s.recordMetricsOrderPlaced(order)
 
 	// add the order to OrdersByUser
 	s.OrdersByUser[user.ID] = append(s.OrdersByUser[user.ID], *order)
@@ -480,6 +684,12 @@ func (s *Simulator) createAndAddOrder(user *models.User) (*models.Order, error)
 
 	// Add the order to the simulator's orders
 	s.addOrder(*order)
+	s.notifyUser(order, models.NotificationOrderConfirmed)
+
+	if !accepted {
+		// the platform rejects it before preparation starts
+		return order, nil
+	}
 
 	// Schedule prepare order event
 	s.EventQueue.Enqueue(&models.Event{
@@ -492,130 +702,217 @@ func (s *Simulator) createAndAddOrder(user *models.User) (*models.Order, error)
 }
 
 func (s *Simulator) updateOrderStatuses() {
-	for i, order := range s.Orders {
-		switch order.Status {
-		case models.OrderStatusPlaced:
-			if s.CurrentTime.After(order.PrepStartTime) {
-				s.Orders[i].Status = models.OrderStatusPreparing
-				s.EventQueue.Enqueue(&models.Event{
-					Time: s.CurrentTime,
-					Type: models.EventPrepareOrder,
-					Data: &s.Orders[i],
-				})
-			}
-		case models.OrderStatusPreparing:
-			if s.CurrentTime.After(order.PickupTime) || s.CurrentTime.Equal(order.PickupTime) {
-				s.Orders[i].Status = models.OrderStatusReady
-				log.Printf("Order %s is ready for pickup at %s", order.ID, s.CurrentTime.Format(time.RFC3339))
-				s.EventQueue.Enqueue(&models.Event{
-					Time: s.CurrentTime,
-					Type: models.EventOrderReady,
-					Data: &s.Orders[i],
-				})
-			}
-		case models.OrderStatusReady:
-			if order.DeliveryPartnerID == "" {
-				// if no partner assigned, try to assign one
-				s.assignDeliveryPartner(&s.Orders[i])
-			} else if s.isDeliveryPartnerAtRestaurant(s.Orders[i]) {
-				s.Orders[i].Status = models.OrderStatusPickedUp
-				log.Printf("Order %s picked up by partner %s at %s", order.ID, order.DeliveryPartnerID, s.CurrentTime.Format(time.RFC3339))
-				s.EventQueue.Enqueue(&models.Event{
-					Time: s.CurrentTime,
-					Type: models.EventPickUpOrder,
-					Data: &s.Orders[i],
-				})
+	// the pass works from a snapshot of order IDs, resolved to their current
+	// position in s.Orders, so it stays correct however the slice is appended
+	// to or compacted along the way
+	ids := make([]string, len(s.Orders))
+	for i := range s.Orders {
+		ids[i] = s.Orders[i].ID
+	}
+
+	// ready orders waiting for a partner; offered most valuable first when tips set priority
+	var waiting []string
+	index := s.orderIndex()
+	for _, id := range ids {
+		i, ok := index[id]
+		if ok && (i >= len(s.Orders) || s.Orders[i].ID != id) {
+			// the slice was compacted since the index was built
+			index = s.orderIndex()
+			i, ok = index[id]
+		}
+		if !ok {
+			// removed since the pass started
+			continue
+		}
+		if s.advanceOrderStatus(i) {
+			waiting = append(waiting, id)
+		}
+	}
+
+	index = s.orderIndex()
+	var unassigned []int
+	for _, id := range waiting {
+		if i, ok := index[id]; ok {
+			unassigned = append(unassigned, i)
+		}
+	}
+
+	if s.Config.TipPriorityWeight > 0 {
+		s.sortByOrderValue(unassigned)
+	}
+	for _, i := range unassigned {
+		s.assignDeliveryPartner(&s.Orders[i])
+	}
+}
+
+// advanceOrderStatus moves s.Orders[i] on to its next status when it is due,
+// and reports whether it is a ready order still waiting for a partner
+func (s *Simulator) advanceOrderStatus(i int) bool {
+	order := s.Orders[i]
+	switch order.Status {
+	case models.OrderStatusPlaced:
+		if s.CurrentTime.After(order.PrepStartTime) {
+			s.Orders[i].Status = models.OrderStatusPreparing
+			s.EventQueue.Enqueue(&models.Event{
+				Time: s.CurrentTime,
+				Type: models.EventPrepareOrder,
+				Data: &s.Orders[i],
+			})
+		}
+	case models.OrderStatusPreparing:
+		if s.CurrentTime.After(order.PickupTime) || s.CurrentTime.Equal(order.PickupTime) {
+			s.Orders[i].Status = models.OrderStatusReady
+			log.Printf("Order %s is ready for pickup at %s", order.ID, s.CurrentTime.Format(time.RFC3339))
+			s.EventQueue.Enqueue(&models.Event{
+				Time: s.CurrentTime,
+				Type: models.EventOrderReady,
+				Data: &s.Orders[i],
+			})
+		}
+	case models.OrderStatusReady:
+		if order.DeliveryPartnerID == "" {
+			// if no partner assigned, try to assign one
+			return true
+		} else if s.isDeliveryPartnerAtRestaurant(s.Orders[i]) {
+			if s.advancePickup(&s.Orders[i], s.getDeliveryPartner(order.DeliveryPartnerID)) {
+				return false
 			}
-		case models.OrderStatusPickedUp:
-			s.Orders[i].Status = models.OrderStatusInTransit
-			s.Orders[i].InTransitTime = s.CurrentTime
-			log.Printf("Order %s is now in transit at %s", order.ID, s.CurrentTime.Format(time.RFC3339))
+			s.Orders[i].Status = models.OrderStatusPickedUp
+			s.recordMetricsPickup(&s.Orders[i])
+			log.Printf("Order %s picked up by partner %s at %s", order.ID, order.DeliveryPartnerID, s.CurrentTime.Format(time.RFC3339))
 			s.EventQueue.Enqueue(&models.Event{
 				Time: s.CurrentTime,
-				Type: models.EventOrderInTransit,
+				Type: models.EventPickUpOrder,
 				Data: &s.Orders[i],
 			})
+		}
+	case models.OrderStatusPickedUp:
+		s.Orders[i].Status = models.OrderStatusInTransit
+		s.Orders[i].InTransitTime = s.CurrentTime
+		log.Printf("Order %s is now in transit at %s", order.ID, s.CurrentTime.Format(time.RFC3339))
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventOrderInTransit,
+			Data: &s.Orders[i],
+		})
 
-		case models.OrderStatusInTransit:
-			partner := s.getDeliveryPartner(order.DeliveryPartnerID)
-			if partner == nil {
-				log.Printf("Error: Delivery partner not found for order %s", order.ID)
-				continue
-			}
+	case models.OrderStatusInTransit:
+		partner := s.getDeliveryPartner(order.DeliveryPartnerID)
+		if partner == nil {
+			log.Printf("Error: Delivery partner not found for order %s", order.ID)
+			return false
+		}
 
-			user := s.getUser(order.CustomerID)
-			if user == nil {
-				log.Printf("Error: User not found for order %s", order.ID)
-				continue
-			}
+		user := s.getUser(order.CustomerID)
+		if user == nil {
+			log.Printf("Error: User not found for order %s", order.ID)
+			return false
+		}
 
-			if s.isAtLocation(partner.CurrentLocation, user.Location) {
-				// order has been delivered
-				s.Orders[i].Status = models.OrderStatusDelivered
-				s.Orders[i].ActualDeliveryTime = s.CurrentTime
-				partner.Status = models.PartnerStatusAvailable
-				partner.CurrentOrderID = ""
-				log.Printf("Order %s delivered at %s", order.ID, s.CurrentTime.Format(time.RFC3339))
-				s.EventQueue.Enqueue(&models.Event{
-					Time: s.CurrentTime,
-					Type: models.EventDeliverOrder,
-					Data: &s.Orders[i],
-				})
-				// schedule review creation for later
-				s.EventQueue.Enqueue(&models.Event{
-					Time: s.CurrentTime.Add(30 * time.Minute), // assume user leaves review after 30 minutes
-					Type: models.EventGenerateReview,
-					Data: &s.Orders[i],
-				})
+		if s.isAtLocation(partner.CurrentLocation, user.Location) {
+			// order has been delivered
+			s.Orders[i].Status = models.OrderStatusDelivered
+			s.Orders[i].ActualDeliveryTime = s.CurrentTime.Add(s.navigationDelay(&s.Orders[i]) + s.deliveryHandlingTime(&s.Orders[i]) + s.sampleDoorDwell(&s.Orders[i]))
+			s.recordDailyDelivery(&s.Orders[i])
+			s.recordMetricsDelivery(&s.Orders[i])
+			s.recordETAAccuracy(&s.Orders[i])
+			s.recordPartnerDelivery(partner, &s.Orders[i])
+			s.settleOrder(&s.Orders[i], partner)
+			s.trackUnreviewed(&s.Orders[i])
+			s.notifyDelivered(&s.Orders[i])
+			s.startHandover(partner, &s.Orders[i])
+			log.Printf("Order %s delivered at %s", order.ID, s.Orders[i].ActualDeliveryTime.Format(time.RFC3339))
+			// schedule review creation for later
+			s.EventQueue.Enqueue(&models.Event{
+				Time: s.Orders[i].ActualDeliveryTime.Add(30 * time.Minute), // assume user leaves review after 30 minutes
+				Type: models.EventGenerateReview,
+				Data: &s.Orders[i],
+			})
+			s.scheduleComplaintCheck(&s.Orders[i])
+		} else {
+			// order is still in transit
+			nextCheckTime := s.CurrentTime.Add(s.Config.InTransitCheckInterval)
+			if s.CurrentTime.After(order.EstimatedDeliveryTime) {
+				log.Printf("Order %s is past its estimated delivery time. Current: %s, Estimated: %s, Next check: %s",
+					order.ID, s.CurrentTime.Format(time.RFC3339), order.EstimatedDeliveryTime.Format(time.RFC3339), nextCheckTime.Format(time.RFC3339))
 			} else {
-				// order is still in transit
-				nextCheckTime := s.CurrentTime.Add(5 * time.Minute)
-				if s.CurrentTime.After(order.EstimatedDeliveryTime) {
-					log.Printf("Order %s is past its estimated delivery time. Current: %s, Estimated: %s, Next check: %s",
-						order.ID, s.CurrentTime.Format(time.RFC3339), order.EstimatedDeliveryTime.Format(time.RFC3339), nextCheckTime.Format(time.RFC3339))
-				} else {
-					log.Printf("Order %s still in transit. Current: %s, Estimated: %s, Next check: %s",
-						order.ID, s.CurrentTime.Format(time.RFC3339), order.EstimatedDeliveryTime.Format(time.RFC3339), nextCheckTime.Format(time.RFC3339))
-				}
-
-				// Schedule next check event
-				s.EventQueue.Enqueue(&models.Event{
-					Time: nextCheckTime,
-					Type: models.EventCheckDeliveryStatus,
-					Data: &s.Orders[i],
-				})
+				log.Printf("Order %s still in transit. Current: %s, Estimated: %s, Next check: %s",
+					order.ID, s.CurrentTime.Format(time.RFC3339), order.EstimatedDeliveryTime.Format(time.RFC3339), nextCheckTime.Format(time.RFC3339))
 			}
 
-		case models.OrderStatusDelivered:
-			// check if it's time to generate a review
-			if s.CurrentTime.Sub(s.Orders[i].ActualDeliveryTime) >= s.Config.ReviewGenerationDelay {
-				if s.shouldGenerateReview(&s.Orders[i]) {
-					s.handleGenerateReview(&s.Orders[i])
-				}
+			// Schedule next check event
+			s.EventQueue.Enqueue(&models.Event{
+				Time: nextCheckTime,
+				Type: models.EventCheckDeliveryStatus,
+				Data: &s.Orders[i],
+			})
+		}
+
+	case models.OrderStatusDelivered:
+		// check if it's time to generate a review
+		if s.CurrentTime.Sub(s.Orders[i].ActualDeliveryTime) >= s.Config.ReviewGenerationDelay {
+			if s.shouldGenerateReview(&s.Orders[i]) {
+				s.handleGenerateReview(&s.Orders[i])
 			}
 		}
 	}
+	return false
 }
 
 func (s *Simulator) shouldPlaceOrder(user *models.User) bool {
-	hourFactor := 1.0
-	if s.isPeakHour(s.CurrentTime) {
-		hourFactor = s.Config.PeakHourFactor
-	}
-	if s.isWeekend(s.CurrentTime) {
-		hourFactor *= s.Config.WeekendFactor
-	}
+	hourFactor := s.timeDemandFactor(s.CurrentTime)
+	hourFactor *= s.calculateEventMultiplier(user.Location, s.CurrentTime)
+	hourFactor *= s.feeDemandFactor(user)
+	hourFactor *= s.outageCatchUpFactor()
 
 	orderProbability := user.OrderFrequency * hourFactor / (24 * 60) // Convert to per-minute probability
 	return s.Rng.Float64() < orderProbability
 }
 
+// timeDemandFactor scales the chance of an order at t for peak hours and weekends
+func (s *Simulator) timeDemandFactor(t time.Time) float64 {
+	factor := 1.0
+	if s.isPeakHour(t) {
+		factor = s.Config.PeakHourFactor
+	}
+	if s.isWeekend(t) {
+		factor *= s.Config.WeekendFactor
+	}
+	return factor
+}
+
 func (s *Simulator) generateNextOrderTime(user *models.User) time.Time {
 	// base time interval (in hours) derived from user's order frequency
 	baseInterval := 24.0 / user.OrderFrequency
 
+	// apply factors to base interval
+	adjustedInterval := baseInterval * s.orderIntervalFactor(s.CurrentTime)
+
+	// add some randomness (±20% of the adjusted interval)
+	randomFactor := 0.8 + (0.4 * s.Rng.Float64())
+	finalInterval := adjustedInterval * randomFactor
+
+	// convert interval to duration
+	duration := time.Duration(finalInterval * float64(time.Hour))
+
+	// calculate next order time
+	nextOrderTime := s.CurrentTime.Add(duration)
+
+	// ensure the next order time is not before the current time
+	if nextOrderTime.Before(s.CurrentTime) {
+		nextOrderTime = s.CurrentTime.Add(15 * time.Minute)
+	}
+
+	return nextOrderTime
+}
+
+// orderIntervalFactor scales the time until a user's next order, placed at t,
+// for the time of day and day of the week
+func (s *Simulator) orderIntervalFactor(t time.Time) float64 {
+	local := s.localTime(t)
+
 	// adjust interval based on time of day
-	hourOfDay := float64(s.CurrentTime.Hour())
+	hourOfDay := float64(local.Hour())
 	var timeOfDayFactor float64
 	switch {
 	case hourOfDay >= 7 && hourOfDay < 10: // Breakfast
@@ -631,7 +928,7 @@ func (s *Simulator) generateNextOrderTime(user *models.User) time.Time {
 	}
 
 	// adjust interval based on day of week
-	dayOfWeek := s.CurrentTime.Weekday()
+	dayOfWeek := local.Weekday()
 	var dayOfWeekFactor float64
 	if dayOfWeek == time.Saturday || dayOfWeek == time.Sunday {
 		dayOfWeekFactor = 0.9 // More likely to order on weekends
@@ -639,25 +936,7 @@ func (s *Simulator) generateNextOrderTime(user *models.User) time.Time {
 		dayOfWeekFactor = 1.1
 	}
 
-	// apply factors to base interval
-	adjustedInterval := baseInterval * timeOfDayFactor * dayOfWeekFactor
-
-	// add some randomness (±20% of the adjusted interval)
-	randomFactor := 0.8 + (0.4 * s.Rng.Float64())
-	finalInterval := adjustedInterval * randomFactor
-
-	// convert interval to duration
-	duration := time.Duration(finalInterval * float64(time.Hour))
-
-	// calculate next order time
-	nextOrderTime := s.CurrentTime.Add(duration)
-
-	// ensure the next order time is not before the current time
-	if nextOrderTime.Before(s.CurrentTime) {
-		nextOrderTime = s.CurrentTime.Add(15 * time.Minute)
-	}
-
-	return nextOrderTime
+	return timeOfDayFactor * dayOfWeekFactor
 }
 
 func (s *Simulator) assignPartnerToOrder(partner *models.DeliveryPartner, order *models.Order) error {
@@ -730,11 +1009,15 @@ func (s *Simulator) getPartnerCurrentOrder(partner *models.DeliveryPartner) *mod
 }
 
 func (s *Simulator) cancelStaleOrders() {
-	maxOrderDuration := 3 * time.Hour
+	maxOrderDuration := s.Config.StaleOrderTimeout
 	for i, order := range s.Orders {
 		if order.Status != models.OrderStatusDelivered && order.Status != models.OrderStatusCancelled {
 			if s.CurrentTime.Sub(order.OrderPlacedAt) > maxOrderDuration {
 				s.Orders[i].Status = models.OrderStatusCancelled
+				s.Orders[i].CancelledBy = models.CancelledBySystem
+				s.Orders[i].CancellationReason = models.CancelReasonTimeout
+				s.recordDailyCancellation(&s.Orders[i])
+				s.recordMetricsCancellation(&s.Orders[i])
 				log.Printf("Order %s cancelled due to timeout. Placed at: %s, Current time: %s",
 					order.ID, order.OrderPlacedAt.Format(time.RFC3339), s.CurrentTime.Format(time.RFC3339))
 
@@ -753,14 +1036,26 @@ func (s *Simulator) cancelStaleOrders() {
 	}
 }
 
+// orderIndex maps each order ID to its current position in s.Orders
+func (s *Simulator) orderIndex() map[string]int {
+	index := make(map[string]int, len(s.Orders))
+	for i := range s.Orders {
+		index[s.Orders[i].ID] = i
+	}
+	return index
+}
+
 func (s *Simulator) removeCompletedOrders() {
 	var activeOrders []models.Order
 	for _, order := range s.Orders {
 		if order.Status != models.OrderStatusDelivered && order.Status != models.OrderStatusCancelled {
 			activeOrders = append(activeOrders, order)
+		} else {
+			s.orderDropped(order.ID)
 		}
 	}
 	s.Orders = activeOrders
+	s.forgetDroppedOrders()
 }
 
 func (s *Simulator) persistOrderBatch(pgOutput *output.PostgresOutput, orders []*models.Order) error {
@@ -788,42 +1083,44 @@ func (s *Simulator) assignDeliveryPartner(order *models.Order) {
 		log.Printf("Error: Restaurant not found for order %s", order.ID)
 		return
 	}
-	availablePartners := s.getAvailablePartnersNear(restaurant.Location)
+	availablePartners := s.partnersWithCashRoom(s.getAvailablePartnersNear(restaurant.Location), order)
 	log.Printf("Attempting to assign partner for order %s. Available partners: %d", order.ID, len(availablePartners))
-	if len(availablePartners) > 0 {
-		selectedPartner := availablePartners[s.Rng.Intn(len(availablePartners))]
-		if selectedPartner != nil {
-			order.DeliveryPartnerID = selectedPartner.ID
-			selectedPartner.Status = models.PartnerStatusEnRoutePickup
-			selectedPartner.CurrentOrderID = order.ID
-			// update the partner in the slice
-			for i, p := range s.DeliveryPartners {
-				if p.ID == selectedPartner.ID {
-					s.DeliveryPartners[i].Status = models.PartnerStatusEnRoutePickup
-					s.DeliveryPartners[i].CurrentOrderID = order.ID
-					log.Printf("Assigned partner %s to order %s", selectedPartner.ID, order.ID)
-					break
-				}
+	selectedPartner := s.selectAcceptingPartner(availablePartners, order)
+	if selectedPartner != nil {
+		s.recordAssignmentWait(order)
+		order.DeliveryPartnerID = selectedPartner.ID
+		selectedPartner.Status = models.PartnerStatusEnRoutePickup
+		selectedPartner.CurrentOrderID = order.ID
+		// update the partner in the slice
+		for i, p := range s.DeliveryPartners {
+			if p.ID == selectedPartner.ID {
+				s.DeliveryPartners[i].Status = models.PartnerStatusEnRoutePickup
+				s.DeliveryPartners[i].CurrentOrderID = order.ID
+				log.Printf("Assigned partner %s to order %s", selectedPartner.ID, order.ID)
+				break
 			}
+		}
 
-			// update the order in the Orders slice
-			for i, o := range s.Orders {
-				if o.ID == order.ID {
-					s.Orders[i] = *order
-					break
-				}
+		// update the order in the Orders slice
+		for i, o := range s.Orders {
+			if o.ID == order.ID {
+				s.Orders[i] = *order
+				break
 			}
+		}
 
-			// set the estimated delivery time
-			order.EstimatedDeliveryTime = s.estimateDeliveryTime(selectedPartner, order)
+		// set the estimated delivery time
+		order.EstimatedDeliveryTime = s.estimateDeliveryTime(selectedPartner, order)
 
-			s.notifyDeliveryPartner(selectedPartner, order)
-			log.Printf("Assigned partner %s to order %s. Estimated delivery time: %s",
-				selectedPartner.ID, order.ID, order.EstimatedDeliveryTime.Format(time.RFC3339))
-		}
+		s.notifyDeliveryPartner(selectedPartner, order)
+		s.maybeScheduleGhosting(selectedPartner, order)
+		s.recordFirstPickup(selectedPartner, order)
+		s.pingOnStatusChange(selectedPartner, order.ID)
+		log.Printf("Assigned partner %s to order %s. Estimated delivery time: %s",
+			selectedPartner.ID, order.ID, order.EstimatedDeliveryTime.Format(time.RFC3339))
 	} else {
-		// if no partners are available, schedule a retry
-		retryTime := s.CurrentTime.Add(5 * time.Minute)
+		// if no partners are available or all declined, schedule a retry
+		retryTime := s.CurrentTime.Add(s.Config.AssignmentRetryInterval)
 		s.EventQueue.Enqueue(&models.Event{
 			Time: retryTime,
 			Type: models.EventAssignDeliveryPartner,
@@ -847,11 +1144,14 @@ func (s *Simulator) getAvailablePartnersNear(location models.Location) []*models
 	availablePartners := make([]*models.DeliveryPartner, 0)
 	for i := range s.DeliveryPartners {
 		partner := s.DeliveryPartners[i]
+		if !partner.DeactivatedAt.IsZero() {
+			continue
+		}
 		isNear := s.isNearLocation(partner.CurrentLocation, location)
+		distance := s.calculateDistance(partner.CurrentLocation, location)
 		log.Printf("Partner %s status: %s, isNear: %v, distance: %.2f km",
-			partner.ID, partner.Status, isNear,
-			s.calculateDistance(partner.CurrentLocation, location))
-		if partner.Status == models.PartnerStatusAvailable && isNear {
+			partner.ID, partner.Status, isNear, distance)
+		if partner.Status == models.PartnerStatusAvailable && isNear && s.withinAcceptanceRadius(distance) {
 			availablePartners = append(availablePartners, partner)
 		}
 	}
@@ -861,7 +1161,7 @@ func (s *Simulator) getAvailablePartnersNear(location models.Location) []*models
 
 func (s *Simulator) isDeliveryPartnerAtRestaurant(order models.Order) bool {
 	partner := s.getDeliveryPartner(order.DeliveryPartnerID)
-	restaurant := s.getRestaurant(order.RestaurantID)
+	restaurant := s.pickupRestaurant(&order)
 	if partner == nil || restaurant == nil {
 		return false
 	}
@@ -911,7 +1211,7 @@ func (s *Simulator) updateDeliveryPartnerLocations() {
 
 			var destination models.Location
 			if partner.Status == models.PartnerStatusEnRoutePickup {
-				restaurant := s.getRestaurant(order.RestaurantID)
+				restaurant := s.pickupRestaurant(order)
 				if restaurant == nil {
 					log.Printf("Error: Restaurant not found for order %s", order.ID)
 					continue
@@ -926,7 +1226,7 @@ func (s *Simulator) updateDeliveryPartnerLocations() {
 				destination = user.Location
 			}
 
-			newLocation = s.moveTowards(partner.CurrentLocation, destination, duration)
+			newLocation = s.movePartner(partner, destination, duration)
 			locationUpdated = true
 
 			if s.isAtLocation(newLocation, destination) {
@@ -934,15 +1234,15 @@ func (s *Simulator) updateDeliveryPartnerLocations() {
 					s.DeliveryPartners[i].Status = models.PartnerStatusWaitingForPickup
 					log.Printf("Partner %s arrived at restaurant for order %s", partner.ID, order.ID)
 				} else {
-					s.DeliveryPartners[i].Status = models.PartnerStatusAvailable
-					s.DeliveryPartners[i].CurrentOrderID = ""
-					log.Printf("Partner %s completed delivery of order %s", partner.ID, order.ID)
+					log.Printf("Partner %s reached the customer for order %s", partner.ID, order.ID)
 					s.handleDeliverOrder(order)
 				}
 			}
 		}
 
 		if locationUpdated {
+			s.updatePartnerMetrics(partner, newLocation, duration)
+
 			timeDiff := s.CurrentTime.Sub(partner.LastUpdateTime).Hours()
 			if timeDiff > 0 {
 				distance := s.calculateDistance(partner.CurrentLocation, newLocation)
@@ -969,7 +1269,7 @@ func (s *Simulator) updateDeliveryPartnerLocations() {
 
 func (s *Simulator) estimateArrivalTime(from, to models.Location) time.Time {
 	distance := s.calculateDistance(from, to)
-	travelTime := distance / s.Config.PartnerMoveSpeed // Assuming PartnerMoveSpeed is in km/hour
+	travelTime := distance / s.trafficSpeed(from) // km/hour, slowed by current traffic
 
 	// Add some variability to the travel time
 	variability := 0.2 // 20% variability
@@ -985,17 +1285,16 @@ func (s *Simulator) estimateDeliveryTime(partner *models.DeliveryPartner, order
 		return s.CurrentTime.Add(30 * time.Minute)
 	}
 
-	restaurant := s.getRestaurant(order.RestaurantID)
+	stops := pickupStops(order)
+	restaurant := s.getRestaurant(stops[len(stops)-1])
 	if restaurant == nil {
 		log.Printf("Warning: Restaurant not found for order %s. Using default delivery estimate.", order.ID)
 		return s.CurrentTime.Add(30 * time.Minute)
 	}
 
-	// estimate time from current location to restaurant (if not already there)
-	timeToRestaurant := time.Duration(0)
-	if !s.isAtLocation(partner.CurrentLocation, restaurant.Location) {
-		timeToRestaurant = s.estimateArrivalTime(partner.CurrentLocation, restaurant.Location).Sub(s.CurrentTime)
-	}
+	// estimate time from current location to the restaurant, or through each
+	// restaurant still to visit for a multi-restaurant order
+	timeToRestaurant := s.remainingPickupTime(partner, order)
 
 	// estimate time from restaurant to user
 	timeToUser := s.estimateArrivalTime(restaurant.Location, user.Location).Sub(s.CurrentTime)
@@ -1003,8 +1302,9 @@ func (s *Simulator) estimateDeliveryTime(partner *models.DeliveryPartner, order
 	// add some buffer time for order handoff at restaurant and to customer, for finding parking space etc
 	bufferTime := 5 * time.Minute
 
-	// calculate total estimated time
+	// calculate total estimated time, allowing for the partner's experience
 	totalEstimatedTime := timeToRestaurant + timeToUser + bufferTime
+	totalEstimatedTime = time.Duration(float64(totalEstimatedTime) * s.etaExperienceBias(partner))
 
 	// add some overall variability to account for unforeseen circumstances
 	variability := 0.1 // 10% variability
@@ -1101,7 +1401,7 @@ func (s *Simulator) selectMenuItems(restaurant *models.Restaurant, user *models.
 		totalProb := 0.0
 
 		for i, item := range eligibleItems {
-			prob := item.Popularity
+			prob := item.Popularity * s.featuredDishBoost(restaurant, item)
 
 			// Consider user preferences (assuming User struct has Preferences field)
 			for _, pref := range user.Preferences {
@@ -1131,6 +1431,19 @@ func (s *Simulator) selectMenuItems(restaurant *models.Restaurant, user *models.
 		}
 	}
 
+	if len(selectedItems) == 0 {
+		// none of the usual courses suit the user, so they settle for any dish that does
+		var suitable []string
+		for _, itemID := range restaurant.MenuItems {
+			if item := s.getMenuItem(itemID); item != nil && !s.hasConflictingIngredients(item, user.DietaryRestrictions) {
+				suitable = append(suitable, itemID)
+			}
+		}
+		if len(suitable) > 0 {
+			selectedItems = append(selectedItems, suitable[s.Rng.Intn(len(suitable))])
+		}
+	}
+
 	return selectedItems
 }
 
@@ -1142,18 +1455,47 @@ func (s *Simulator) getMenuItem(itemID string) *models.MenuItem {
 	return item
 }
 
+// hasConflictingIngredients reports whether an item is unsuitable for any of
+// the user's dietary restrictions, i.e. lacks the matching dietary tag
+// servesUser reports whether a restaurant's menu has at least one item the
+// user's dietary restrictions allow
+func (s *Simulator) servesUser(restaurant *models.Restaurant, user *models.User) bool {
+	for _, itemID := range restaurant.MenuItems {
+		if item := s.getMenuItem(itemID); item != nil && !s.hasConflictingIngredients(item, user.DietaryRestrictions) {
+			return true
+		}
+	}
+	return false
+}
+
+// servingUser keeps the restaurants that serve something the user can eat
+func (s *Simulator) servingUser(restaurants []*models.Restaurant, user *models.User) []*models.Restaurant {
+	kept := restaurants[:0:0]
+	for _, restaurant := range restaurants {
+		if s.servesUser(restaurant, user) {
+			kept = append(kept, restaurant)
+		}
+	}
+	return kept
+}
+
 func (s *Simulator) hasConflictingIngredients(item *models.MenuItem, restrictions []string) bool {
-	for _, ingredient := range item.Ingredients {
-		for _, restriction := range restrictions {
-			if strings.EqualFold(ingredient, restriction) {
-				return true
+	for _, restriction := range restrictions {
+		suitable := false
+		for _, tag := range item.DietaryTags {
+			if strings.EqualFold(tag, restriction) {
+				suitable = true
+				break
 			}
 		}
+		if !suitable {
+			return true
+		}
 	}
 	return false
 }
 
-func (s *Simulator) calculateTotalAmount(items []string) float64 {
+func (s *Simulator) calculateTotalAmount(restaurant *models.Restaurant, items []string, distance float64) (float64, deliveryFee, orderCharges) {
 	var subtotal float64
 	var discountableTotal float64
 
@@ -1180,35 +1522,60 @@ func (s *Simulator) calculateTotalAmount(items []string) float64 {
 		}
 	}
 
-	// Calculate tax
-	taxAmount := subtotal * s.Config.TaxRate
+	// Calculate tax, at the restaurant's own rates where it has them
+	taxRate, serviceFeeRate := s.restaurantRates(restaurant)
+	taxAmount := subtotal * taxRate
 
 	// Calculate delivery fee (if applicable)
-	deliveryFee := s.calculateDeliveryFee(subtotal)
+	fee := s.calculateDeliveryFee(subtotal, distance)
 
 	// Calculate service fee
-	serviceFee := subtotal * s.Config.ServiceFeePercentage
+	serviceFee := subtotal * serviceFeeRate
 
 	// Calculate total
-	total := subtotal + taxAmount + deliveryFee + serviceFee - discountAmount
+	total := subtotal + taxAmount + fee.Total() + serviceFee - discountAmount
+
+	charges := orderCharges{
+		TaxRate:        taxRate,
+		Tax:            math.Round(taxAmount*100) / 100,
+		ServiceFeeRate: serviceFeeRate,
+		ServiceFee:     math.Round(serviceFee*100) / 100,
+		Discount:       math.Round(discountAmount*100) / 100,
+	}
 
 	// Round to two decimal places
-	return math.Round(total*100) / 100
+	return math.Round(total*100) / 100, fee, charges
+}
+
+// deliveryFee is the delivery fee charged on an order, broken down by component
+type deliveryFee struct {
+	Base       float64
+	SmallOrder float64
+	Distance   float64
 }
 
-func (s *Simulator) calculateDeliveryFee(subtotal float64) float64 {
+func (f deliveryFee) Total() float64 {
+	return math.Round((f.Base+f.SmallOrder+f.Distance)*100) / 100
+}
+
+// calculateDeliveryFee prices delivery of an order over distance km. Orders
+// above the free delivery threshold pay nothing, however far they travel.
+func (s *Simulator) calculateDeliveryFee(subtotal, distance float64) deliveryFee {
 	if subtotal >= s.Config.FreeDeliveryThreshold {
-		return 0
+		return deliveryFee{}
 	}
 
 	// base delivery fee
-	fee := s.Config.BaseDeliveryFee
+	fee := deliveryFee{Base: s.Config.BaseDeliveryFee}
 
 	// additional fee for small orders
 	if subtotal < s.Config.SmallOrderThreshold {
-		fee += s.Config.SmallOrderFee
+		fee.SmallOrder = s.Config.SmallOrderFee
 	}
 
+	// surcharge for distance tiers
+	fee.Distance = math.Round(s.Config.DistanceFee(distance)*100) / 100
+
 	return fee
 }
 
@@ -1220,7 +1587,7 @@ func (s *Simulator) updateRestaurantMetrics(restaurant *models.Restaurant) {
 			totalPrepTime += order.PickupTime.Sub(order.PrepStartTime).Minutes()
 		}
 	}
-	if len(restaurant.CurrentOrders) > 0 {
+	if len(restaurant.CurrentOrders) > 0 && s.Config.PrepTimeLearningRate <= 0 {
 		restaurant.AvgPrepTime = totalPrepTime / float64(len(restaurant.CurrentOrders))
 	}
 
@@ -1232,8 +1599,12 @@ func (s *Simulator) updateRestaurantMetrics(restaurant *models.Restaurant) {
 }
 
 func (s *Simulator) adjustRestaurantCapacity(restaurant *models.Restaurant) int {
-	// Base capacity
-	baseCapacity := restaurant.Capacity
+	// Capacity is held at its degraded level for the duration of a kitchen incident
+	if restaurant.KitchenIncident != nil {
+		return restaurant.Capacity
+	}
+
+	baseCapacity := s.baseCapacity(restaurant)
 
 	// Time-based adjustment
 	timeAdjustment := s.getTimeBasedAdjustment(s.CurrentTime)
@@ -1256,8 +1627,17 @@ func (s *Simulator) adjustRestaurantCapacity(restaurant *models.Restaurant) int
 	return newCapacity
 }
 
+// baseCapacity is the restaurant's own venue capacity, falling back to the
+// middle of the configured capacity range for restaurants without one
+func (s *Simulator) baseCapacity(restaurant *models.Restaurant) int {
+	if restaurant.BaseCapacity > 0 {
+		return restaurant.BaseCapacity
+	}
+	return max(1, (s.Config.MinCapacity+s.Config.MaxCapacity)/2)
+}
+
 func (s *Simulator) getTimeBasedAdjustment(currentTime time.Time) float64 {
-	hour := currentTime.Hour()
+	hour := s.localTime(currentTime).Hour()
 	switch {
 	case hour >= 11 && hour < 14: // Lunch rush
 		return 1.3
@@ -1295,7 +1675,7 @@ func (s *Simulator) getDemandBasedAdjustment(restaurant *models.Restaurant) floa
 }
 
 func (s *Simulator) getDayOfWeekAdjustment(currentTime time.Time) float64 {
-	switch currentTime.Weekday() {
+	switch s.localTime(currentTime).Weekday() {
 	case time.Friday, time.Saturday:
 		return 1.2 // Increase capacity on weekends
 	case time.Sunday:
@@ -1316,21 +1696,46 @@ func (s *Simulator) estimatePrepTime(restaurant *models.Restaurant, items []stri
 		}
 	}
 
+	// blend in how long these particular items take to make
+	if itemTime := s.itemPrepTime(items); itemTime > 0 {
+		weight := s.Config.ItemPrepTimeWeight
+		baseTime = (1-weight)*baseTime + weight*itemTime
+	}
+
 	// Adjust prep time based on order complexity
 	adjustedTime := baseTime * (1 + (totalComplexity/float64(len(items))-1)*0.2)
 
 	// Consider restaurant's current load
-	currentLoad := float64(len(restaurant.CurrentOrders)) / float64(restaurant.Capacity)
-	loadFactor := 1 + (currentLoad * 0.5) // Up to 50% increase for full capacity
+	loadFactor := s.kitchenLoadFactor(restaurant)
 
 	// Add some randomness to account for unforeseen factors
 	randomFactor := 1 + (s.Rng.Float64()-0.5)*0.1 // ±5% random variation
 
-	finalPrepTime := adjustedTime * loadFactor * randomFactor
+	finalPrepTime := adjustedTime * loadFactor * randomFactor * kitchenPrepFactor(restaurant)
 
 	return math.Max(finalPrepTime, restaurant.MinPrepTime)
 }
 
+// itemPrepOverlap is the share of each item's own prep time that adds to an
+// order's prep beyond its slowest item, as the kitchen works on items in parallel
+const itemPrepOverlap = 0.3
+
+// itemPrepTime is how long an order's items take to make from their own prep
+// times: the slowest item, plus part of the time for each of the others. It
+// is 0 if none of the items have a prep time.
+func (s *Simulator) itemPrepTime(items []string) float64 {
+	var longest, total float64
+	for _, itemID := range items {
+		item := s.getMenuItem(itemID)
+		if item == nil || item.PrepTime <= 0 {
+			continue
+		}
+		longest = math.Max(longest, item.PrepTime)
+		total += item.PrepTime
+	}
+	return longest + (total-longest)*itemPrepOverlap
+}
+
 func (s *Simulator) isUrbanArea(loc models.Location) bool {
 	// Implement logic to determine if a location is in an urban area
 	// This could be based on population density data or predefined urban zones
@@ -1339,8 +1744,13 @@ func (s *Simulator) isUrbanArea(loc models.Location) bool {
 	return s.calculateDistance(loc, cityCenter) <= s.Config.UrbanRadius
 }
 
-func (s *Simulator) selectPaymentMethod() string {
-	methods := []string{"card", "cash", "wallet"}
+// selectPaymentMethod picks how the customer pays, from the methods the
+// restaurant accepts
+func (s *Simulator) selectPaymentMethod(restaurant *models.Restaurant) string {
+	methods := restaurant.AcceptedPaymentMethods
+	if len(methods) == 0 {
+		methods = []string{models.PaymentCard, models.PaymentCash, models.PaymentWallet}
+	}
 	return methods[s.Rng.Intn(len(methods))]
 }
 
@@ -1357,30 +1767,24 @@ func (s *Simulator) moveTowardsHotspot(partner *models.DeliveryPartner, duration
 	cityCenter := models.Location{Lat: s.Config.CityLat, Lon: s.Config.CityLon}
 	if s.calculateDistance(partner.CurrentLocation, cityCenter) > s.Config.NearLocationThreshold {
 		// if partner is too far from city center, move towards it
-		return s.moveTowards(partner.CurrentLocation, cityCenter, duration)
+		return s.movePartner(partner, cityCenter, duration)
 	}
 
 	// find the nearest restaurant or hotspot
 	nearestLocation := s.findNearestRestaurantOrHotspot(partner.CurrentLocation)
 
 	// move towards the location
-	return s.moveTowards(partner.CurrentLocation, nearestLocation, duration)
+	return s.movePartner(partner, nearestLocation, duration)
 }
 
 func (s *Simulator) findNearestHotspot(loc models.Location) models.Location {
-	// define a list of hotspots
-	hotspots := []models.Hotspot{
-		{Location: models.Location{Lat: s.Config.CityLat, Lon: s.Config.CityLon}, Weight: 1.0},                 // City center
-		{Location: models.Location{Lat: s.Config.CityLat + 0.01, Lon: s.Config.CityLon + 0.01}, Weight: 0.8},   // Business district
-		{Location: models.Location{Lat: s.Config.CityLat - 0.015, Lon: s.Config.CityLon - 0.005}, Weight: 0.7}, // University area
-		{Location: models.Location{Lat: s.Config.CityLat + 0.008, Lon: s.Config.CityLon - 0.012}, Weight: 0.6}, // Shopping mall
-		{Location: models.Location{Lat: s.Config.CityLat - 0.02, Lon: s.Config.CityLon + 0.018}, Weight: 0.5},  // Residential area
-	}
-
 	var nearestHotspot models.Hotspot
 	minDistance := math.Inf(1)
 
-	for _, hotspot := range hotspots {
+	for _, hotspot := range s.hotspots() {
+		if hotspot.Weight <= 0 {
+			continue
+		}
 		distance := s.calculateDistance(loc, hotspot.Location)
 
 		// adjust distance by hotspot weight (more important hotspots seem "closer")
@@ -1423,7 +1827,7 @@ func (s *Simulator) findNearestRestaurantOrHotspot(loc models.Location) models.L
 
 func (s *Simulator) moveTowards(from, to models.Location, duration time.Duration) models.Location {
 	distance := s.calculateDistance(from, to)
-	speed := s.Config.PartnerMoveSpeed * (1 + (s.Rng.Float64()*0.2 - 0.1)) // Add 10% randomness
+	speed := s.trafficSpeed(from) * (1 + (s.Rng.Float64()*0.2 - 0.1)) // Add 10% randomness
 
 	// calculate max distance that can be moved in this duration
 	maxDistance := speed * duration.Hours()
@@ -1445,6 +1849,9 @@ func (s *Simulator) moveTowards(from, to models.Location, duration time.Duration
 	}
 }
 
+// isNearLocation reports whether two locations are close enough for a partner
+// to count as available for the other: within twice near_location_threshold,
+// widened by half off-peak and narrowed by a fifth when both are urban
 func (s *Simulator) isNearLocation(loc1, loc2 models.Location) bool {
 	distance := s.calculateDistance(loc1, loc2)
 
@@ -1464,6 +1871,13 @@ func (s *Simulator) isNearLocation(loc1, loc2 models.Location) bool {
 	return distance <= threshold*2
 }
 
+// withinAcceptanceRadius reports whether a partner distance km from a
+// restaurant would accept its order. Unlike isNearLocation the radius is
+// fixed, partner_acceptance_radius at all hours and in all areas.
+func (s *Simulator) withinAcceptanceRadius(distance float64) bool {
+	return s.Config.PartnerAcceptanceRadius <= 0 || distance <= s.Config.PartnerAcceptanceRadius
+}
+
 func (s *Simulator) isAtLocation(loc1, loc2 models.Location) bool {
 	distance := s.calculateDistance(loc1, loc2)
 	return distance <= deliveryThreshold // consider locations the same if they're within 100 meters
@@ -1516,8 +1930,8 @@ func (s *Simulator) adjustPrepTime(restaurant *models.Restaurant) float64 {
 	currentLoad := float64(len(restaurant.CurrentOrders)) / float64(restaurant.Capacity)
 	loadFactor := 1 + (currentLoad * s.Config.RestaurantLoadFactor)
 
-	// Adjust prep time based on current load
-	adjustedPrepTime := restaurant.AvgPrepTime * loadFactor
+	// Adjust prep time based on current load and any kitchen incident
+	adjustedPrepTime := restaurant.AvgPrepTime * loadFactor * kitchenPrepFactor(restaurant)
 
 	// Ensure prep time doesn't go below minimum or become NaN
 	if math.IsNaN(adjustedPrepTime) || adjustedPrepTime < restaurant.MinPrepTime {
@@ -1581,12 +1995,12 @@ func (s *Simulator) getRecentCompletedOrders(restaurantID string, count int) []m
 }
 
 func (s *Simulator) isPeakHour(t time.Time) bool {
-	hour := t.Hour()
+	hour := s.localTime(t).Hour()
 	return (hour >= 11 && hour <= 14) || (hour >= 18 && hour <= 21)
 }
 
 func (s *Simulator) isWeekend(t time.Time) bool {
-	day := t.Weekday()
+	day := s.localTime(t).Weekday()
 	return day == time.Saturday || day == time.Sunday
 }
 
@@ -1672,7 +2086,8 @@ func (s *Simulator) serializeRestaurantsToCSV(filename string) error {
 	header := []string{
 		"ID", "Name", "Latitude", "Longitude", "Cuisines", "MenuItemIds", "Rating", "TotalRatings",
 		"PrepTime", "MinPrepTime", "AvgPrepTime", "PickupEfficiency", "Capacity",
-		"Host", "Phone", "Town", "SlugName", "WebsiteLogoURL", "Offline", "Currency",
+		"Host", "Phone", "Town", "SlugName", "WebsiteLogoURL", "Offline", "Currency", "AcceptedPaymentMethods",
+		"BadActor", "CancelRate",
 	}
 	if err := writer.Write(header); err != nil {
 		return err
@@ -1701,6 +2116,9 @@ func (s *Simulator) serializeRestaurantsToCSV(filename string) error {
 			restaurant.WebsiteLogoURL,
 			restaurant.Offline,
 			strconv.Itoa(restaurant.Currency),
+			strings.Join(restaurant.AcceptedPaymentMethods, "|"),
+			strconv.FormatBool(restaurant.BadActor),
+			strconv.FormatFloat(restaurant.CancelRate, 'f', 3, 64),
 		}
 		if err := writer.Write(row); err != nil {
 			return err
@@ -1791,7 +2209,7 @@ func (s *Simulator) serializeMenuItemsToCSV(filename string) error {
 	defer writer.Flush()
 
 	// Write header
-	header := []string{"ID", "RestaurantID", "Name", "Description", "Price", "Type", "Popularity", "PrepComplexity", "Ingredients"}
+	header := []string{"ID", "RestaurantID", "Name", "Description", "Price", "Type", "Popularity", "PrepComplexity", "Ingredients", "DietaryTags"}
 	if err := writer.Write(header); err != nil {
 		return err
 	}
@@ -1808,6 +2226,7 @@ func (s *Simulator) serializeMenuItemsToCSV(filename string) error {
 			strconv.FormatFloat(menuItem.Popularity, 'f', 2, 64),
 			strconv.FormatFloat(menuItem.PrepComplexity, 'f', 2, 64),
 			strings.Join(menuItem.Ingredients, "|"),
+			strings.Join(menuItem.DietaryTags, "|"),
 		}
 		if err := writer.Write(row); err != nil {
 			return err
@@ -0,0 +1,122 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"sort"
+	"time"
+)
+
+// homepageFeatured reports whether the platform is promoting the restaurant right now
+func (s *Simulator) homepageFeatured(restaurant *models.Restaurant) bool {
+	return !restaurant.HomepageFeaturedUntil.IsZero() && s.CurrentTime.Before(restaurant.HomepageFeaturedUntil)
+}
+
+// addHomepageFeatured adds featured and viral restaurants that can deliver to
+// loc to the candidates a user chooses from, however far they are from the user
+func (s *Simulator) addHomepageFeatured(candidates []*models.Restaurant, loc models.Location) []*models.Restaurant {
+	for _, restaurant := range s.Restaurants {
+		if !(s.homepageFeatured(restaurant) || s.viral(restaurant)) || restaurantOffline(restaurant) {
+			continue
+		}
+		if s.calculateDistance(loc, restaurant.Location) > s.Config.MaxDeliveryRadius {
+			continue
+		}
+		listed := false
+		for _, candidate := range candidates {
+			if candidate.ID == restaurant.ID {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			candidates = append(candidates, restaurant)
+		}
+	}
+	return candidates
+}
+
+// updateHomepageFeatures ends expired homepage features, starts those
+// scheduled in homepage_features, and fills empty random slots at
+// homepage_feature_daily_rate. Starts and ends are emitted so promoted
+// demand can be told apart from organic demand.
+func (s *Simulator) updateHomepageFeatures() {
+	ids := make([]string, 0, len(s.Restaurants))
+	for id := range s.Restaurants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	featured := 0
+	for _, id := range ids {
+		restaurant := s.Restaurants[id]
+		if restaurant.HomepageFeaturedUntil.IsZero() {
+			continue
+		}
+		if s.CurrentTime.Before(restaurant.HomepageFeaturedUntil) {
+			featured++
+			continue
+		}
+		s.enqueueHomepageFeature(models.EventHomepageFeatureEnded, &models.HomepageFeature{
+			RestaurantID: id,
+			Start:        restaurant.HomepageFeaturedFrom,
+			Until:        restaurant.HomepageFeaturedUntil,
+		})
+		restaurant.HomepageFeaturedFrom = time.Time{}
+		restaurant.HomepageFeaturedUntil = time.Time{}
+	}
+
+	for i, schedule := range s.Config.HomepageFeatures {
+		if s.homepageScheduled[i] || s.CurrentTime.Before(schedule.Start) {
+			continue
+		}
+		if s.homepageScheduled == nil {
+			s.homepageScheduled = make(map[int]bool)
+		}
+		s.homepageScheduled[i] = true
+		restaurant, ok := s.Restaurants[schedule.RestaurantID]
+		if !ok {
+			log.Printf("Warning: homepage_features %d names unknown restaurant %s", i, schedule.RestaurantID)
+			continue
+		}
+		s.startHomepageFeature(restaurant, schedule.Start.Add(schedule.Duration), true)
+	}
+
+	if s.Config.HomepageFeatureSlots <= 0 || len(ids) == 0 {
+		return
+	}
+	stepProbability := s.Config.HomepageFeatureDailyRate * simulationTimeStep.Hours() / 24
+	for slot := featured; slot < s.Config.HomepageFeatureSlots; slot++ {
+		if s.Rng.Float64() >= stepProbability {
+			continue
+		}
+		restaurant := s.Restaurants[ids[s.Rng.Intn(len(ids))]]
+		if s.homepageFeatured(restaurant) || restaurantOffline(restaurant) {
+			continue
+		}
+		s.startHomepageFeature(restaurant, s.CurrentTime.Add(s.Config.HomepageFeatureDuration), false)
+	}
+}
+
+func (s *Simulator) startHomepageFeature(restaurant *models.Restaurant, until time.Time, scheduled bool) {
+	if !until.After(s.CurrentTime) {
+		return
+	}
+	restaurant.HomepageFeaturedFrom = s.CurrentTime
+	restaurant.HomepageFeaturedUntil = until
+	log.Printf("Restaurant %s featured on the homepage until %s", restaurant.ID, until.Format(time.RFC3339))
+	s.enqueueHomepageFeature(models.EventHomepageFeatureStarted, &models.HomepageFeature{
+		RestaurantID: restaurant.ID,
+		Start:        s.CurrentTime,
+		Until:        until,
+		Scheduled:    scheduled,
+	})
+}
+
+func (s *Simulator) enqueueHomepageFeature(eventType string, feature *models.HomepageFeature) {
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: eventType,
+		Data: feature,
+	})
+}
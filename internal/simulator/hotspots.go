@@ -0,0 +1,73 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"time"
+)
+
+// defaultHotspotRadius is used when hotspot_radius is not configured, in km
+const defaultHotspotRadius = 2.0
+
+func (s *Simulator) hotspots() []models.Hotspot {
+	return s.Config.DemandHotspots()
+}
+
+// isNearHotspotType reports whether loc is within the hotspot radius of any
+// hotspot of the given type
+func (s *Simulator) isNearHotspotType(loc models.Location, hotspotType string) bool {
+	radius := s.Config.HotspotRadius
+	if radius <= 0 {
+		radius = defaultHotspotRadius
+	}
+	for _, hotspot := range s.hotspots() {
+		if hotspot.Type == hotspotType && s.calculateDistance(loc, hotspot.Location) <= radius {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Simulator) isUniversityArea(loc models.Location) bool {
+	return s.isNearHotspotType(loc, models.HotspotTypeUniversity)
+}
+
+// isExamPeriod reports whether t falls in a typical university exam season
+func isExamPeriod(t time.Time) bool {
+	switch t.Month() {
+	case time.May:
+		return t.Day() >= 10
+	case time.June:
+		return t.Day() <= 7
+	case time.December:
+		return t.Day() <= 20
+	}
+	return false
+}
+
+// calculateEventMultiplier scales order demand at loc for effects tied to
+// nearby hotspots: late-night and exam-season orders around the university,
+// and weekday lunches in the business district
+func (s *Simulator) calculateEventMultiplier(loc models.Location, t time.Time) float64 {
+	multiplier := 1.0
+	t = s.localTime(t)
+	hour := t.Hour()
+
+	if s.isUniversityArea(loc) {
+		if hour >= 22 || hour < 2 {
+			multiplier *= 1.3
+		}
+		if isExamPeriod(t) {
+			multiplier *= 1.2
+		}
+	}
+
+	if s.isNearHotspotType(loc, models.HotspotTypeBusiness) {
+		if s.isWeekend(t) {
+			multiplier *= 0.8
+		} else if hour >= 11 && hour < 14 {
+			multiplier *= 1.3
+		}
+	}
+
+	return multiplier
+}
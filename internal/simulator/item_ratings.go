@@ -0,0 +1,56 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// itemRatingPopularityStep is how far a one-star swing from a neutral item
+// rating moves the item's popularity
+const itemRatingPopularityStep = 0.005
+
+// rateItems adds dish-level ratings to item_rating_rate of reviews that rate
+// the food. Each dish's rating follows the food rating to the degree set by
+// item_rating_correlation, and otherwise the dish's own appeal, so popular
+// dishes stand out even in a mixed review.
+func (s *Simulator) rateItems(order *models.Order, review *models.Review) {
+	if review.FoodRating == 0 || s.Config.ItemRatingRate <= 0 || s.Rng.Float64() >= s.Config.ItemRatingRate {
+		return
+	}
+	correlation := s.Config.ItemRatingCorrelation
+	rated := make(map[string]bool, len(order.Items))
+	for _, id := range order.Items {
+		item := s.getMenuItem(id)
+		if item == nil || rated[id] {
+			continue
+		}
+		rated[id] = true
+		appeal := 1 + 4*item.Popularity
+		rating := correlation*review.FoodRating + (1-correlation)*appeal + s.Rng.NormFloat64()*0.5
+		review.ItemRatings = append(review.ItemRatings, models.ItemRating{
+			ItemID: id,
+			Rating: math.Round(math.Max(1, math.Min(5, rating))*10) / 10,
+		})
+	}
+}
+
+// updateItemPopularity feeds a review's dish ratings back into how popular
+// each dish is: well-rated dishes get ordered more
+func (s *Simulator) updateItemPopularity(review *models.Review) {
+	for _, itemRating := range review.ItemRatings {
+		item := s.getMenuItem(itemRating.ItemID)
+		if item == nil {
+			continue
+		}
+		item.Popularity = math.Max(0.01, math.Min(1, item.Popularity+itemRatingPopularityStep*(itemRating.Rating-3)))
+	}
+}
+
+// withItemRatings adds a review's dish ratings, on the output scale, to its event
+func (s *Simulator) withItemRatings(event ReviewEvent, review *models.Review) ReviewEvent {
+	for _, itemRating := range review.ItemRatings {
+		event.ItemRatingIDs = append(event.ItemRatingIDs, itemRating.ItemID)
+		event.ItemRatings = append(event.ItemRatings, s.Config.OutputRating(itemRating.Rating))
+	}
+	return event
+}
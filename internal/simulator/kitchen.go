@@ -0,0 +1,74 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"time"
+)
+
+var kitchenIncidentTypes = []string{
+	models.KitchenIncidentEquipmentFailure,
+	models.KitchenIncidentStaffShortage,
+	models.KitchenIncidentSupplyShortage,
+}
+
+// updateKitchenIncident ends an expired kitchen incident or randomly starts a new one
+func (s *Simulator) updateKitchenIncident(restaurant *models.Restaurant) {
+	if incident := restaurant.KitchenIncident; incident != nil {
+		if s.CurrentTime.Before(incident.EndTime) {
+			return
+		}
+		restaurant.Capacity = incident.CapacityAtStart
+		restaurant.KitchenIncident = nil
+		log.Printf("Kitchen incident (%s) at restaurant %s resolved at %s",
+			incident.Type, restaurant.ID, s.CurrentTime.Format(time.RFC3339))
+		return
+	}
+
+	if !s.Config.KitchenDegradationEnabled {
+		return
+	}
+
+	// convert the daily rate into a per-time-step probability
+	stepProbability := s.Config.KitchenDegradationDailyRate * simulationTimeStep.Hours() / 24
+	if s.Rng.Float64() >= stepProbability {
+		return
+	}
+
+	minMinutes := s.Config.KitchenDegradationMinMinutes
+	maxMinutes := math.Max(minMinutes, s.Config.KitchenDegradationMaxMinutes)
+	duration := minMinutes + s.Rng.Float64()*(maxMinutes-minMinutes)
+
+	// severity between 50% and 100% of the configured factors
+	severity := 0.5 + s.Rng.Float64()*0.5
+	incident := &models.KitchenIncident{
+		Type:            kitchenIncidentTypes[s.Rng.Intn(len(kitchenIncidentTypes))],
+		StartTime:       s.CurrentTime,
+		EndTime:         s.CurrentTime.Add(time.Duration(duration * float64(time.Minute))),
+		PrepTimeFactor:  1 + (s.Config.KitchenDegradationPrepFactor-1)*severity,
+		CapacityFactor:  1 - (1-s.Config.KitchenDegradationCapacityFactor)*severity,
+		CapacityAtStart: restaurant.Capacity,
+	}
+	restaurant.KitchenIncident = incident
+	restaurant.Capacity = max(1, int(float64(restaurant.Capacity)*incident.CapacityFactor))
+
+	log.Printf("Kitchen incident (%s) at restaurant %s until %s: prep x%.2f, capacity x%.2f",
+		incident.Type, restaurant.ID, incident.EndTime.Format(time.RFC3339), incident.PrepTimeFactor, incident.CapacityFactor)
+}
+
+// kitchenPrepFactor returns the prep time multiplier from an active kitchen incident
+func kitchenPrepFactor(restaurant *models.Restaurant) float64 {
+	if restaurant.KitchenIncident == nil {
+		return 1.0
+	}
+	return restaurant.KitchenIncident.PrepTimeFactor
+}
+
+// kitchenIncidentType returns the type of the active kitchen incident, or "" if none
+func kitchenIncidentType(restaurant *models.Restaurant) string {
+	if restaurant.KitchenIncident == nil {
+		return ""
+	}
+	return restaurant.KitchenIncident.Type
+}
@@ -0,0 +1,38 @@
+package simulator
+
+import (
+	"time"
+)
+
+// timeLocation is the city's time zone, loaded once per simulator
+func (s *Simulator) timeLocation() *time.Location {
+	if s.location == nil {
+		s.location = s.Config.TimeLocation()
+	}
+	return s.location
+}
+
+// localTime is t on the city's wall clock, used for time-of-day, weekday and
+// seasonal patterns. Simulated time always advances in UTC, so across a DST
+// change each step still covers the same real time: the skipped local hour
+// simply never occurs and the repeated one occurs twice, as it does for real
+// customers.
+func (s *Simulator) localTime(t time.Time) time.Time {
+	if !s.Config.LocalTimeDemand {
+		return t
+	}
+	return t.In(s.timeLocation())
+}
+
+// daysInYear is 366 for leap years and 365 otherwise
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+// yearFraction is how far through its calendar year t is, from 0 to 1,
+// measured against the actual length of that year so Feb 29 doesn't shift
+// the seasons
+func yearFraction(t time.Time) float64 {
+	elapsed := float64(t.YearDay()-1) + (float64(t.Hour())+float64(t.Minute())/60)/24
+	return elapsed / float64(daysInYear(t.Year()))
+}
@@ -0,0 +1,58 @@
+package simulator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestPeakHoursFollowLocalClockAcrossDST(t *testing.T) {
+	// British Summer Time starts at 01:00 UTC on 31 March 2024
+	s := NewSimulator(&models.Config{Timezone: "Europe/London", LocalTimeDemand: true})
+	if s.timeLocation() == time.UTC {
+		t.Skip("Europe/London time zone data not available")
+	}
+
+	for _, tc := range []struct {
+		utc  time.Time
+		peak bool
+	}{
+		{time.Date(2024, 3, 30, 10, 30, 0, 0, time.UTC), false}, // 10:30 GMT
+		{time.Date(2024, 3, 31, 10, 30, 0, 0, time.UTC), true},  // 11:30 BST, lunch has started
+		{time.Date(2024, 3, 30, 21, 30, 0, 0, time.UTC), true},  // 21:30 GMT
+		{time.Date(2024, 3, 31, 21, 30, 0, 0, time.UTC), false}, // 22:30 BST, dinner is over
+	} {
+		if got := s.isPeakHour(tc.utc); got != tc.peak {
+			t.Errorf("isPeakHour(%s) = %v, want %v (local %s)", tc.utc.Format(time.RFC3339), got, tc.peak, s.localTime(tc.utc).Format("15:04 MST"))
+		}
+	}
+
+	// with local time off, the same instants are judged on UTC
+	s.Config.LocalTimeDemand = false
+	if s.isPeakHour(time.Date(2024, 3, 31, 10, 30, 0, 0, time.UTC)) {
+		t.Error("10:30 UTC counted as peak with local_time_demand off")
+	}
+}
+
+func TestYearFractionHandlesLeapYears(t *testing.T) {
+	if got := daysInYear(2024); got != 366 {
+		t.Errorf("daysInYear(2024) = %d, want 366", got)
+	}
+	if got := daysInYear(2023); got != 365 {
+		t.Errorf("daysInYear(2023) = %d, want 365", got)
+	}
+
+	// the leap day sits between 28 February and 1 March, a day apart from each
+	day := 1.0 / 366
+	feb28 := yearFraction(time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC))
+	feb29 := yearFraction(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC))
+	mar1 := yearFraction(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if math.Abs(feb29-feb28-day) > 1e-12 || math.Abs(mar1-feb29-day) > 1e-12 {
+		t.Errorf("fractions %v, %v, %v should be %v apart", feb28, feb29, mar1, day)
+	}
+	if last := yearFraction(time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)); last >= 1 {
+		t.Errorf("yearFraction on the last minute of 2024 = %v, want below 1", last)
+	}
+}
@@ -0,0 +1,106 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+// maybeAddSecondRestaurant turns an order into a multi-restaurant basket by
+// adding items from a second venue near the first. The partner collects from
+// both, in order, before heading to the customer.
+func (s *Simulator) maybeAddSecondRestaurant(order *models.Order, first *models.Restaurant, user *models.User) {
+	if !s.Config.MultiRestaurantOrdersEnabled || s.Rng.Float64() >= s.Config.MultiRestaurantOrderProbability {
+		return
+	}
+
+	var candidates []*models.Restaurant
+	for _, restaurant := range s.getNearbyRestaurants(first.Location, s.Config.MultiRestaurantMaxDistance) {
+		if restaurant.ID != first.ID && len(restaurant.MenuItems) > 0 {
+			candidates = append(candidates, restaurant)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	// nearby restaurants come out of a map, so sort them to keep runs reproducible
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+	second := candidates[s.Rng.Intn(len(candidates))]
+
+	extra := s.selectMenuItems(second, user)
+	if len(extra) == 0 {
+		return
+	}
+	order.Items = append(order.Items, extra...)
+	order.RestaurantIDs = []string{first.ID, second.ID}
+
+	// priced as one basket, with the delivery distance from the farther venue
+	distance := math.Max(s.calculateDistance(first.Location, user.Location), s.calculateDistance(second.Location, user.Location))
+	// and taxed at the first restaurant's rates
+	totalAmount, deliveryFee, charges := s.calculateTotalAmount(first, order.Items, distance)
+	order.TotalAmount = totalAmount
+	order.DeliveryCost = deliveryFee.Total()
+	order.DistanceFee = deliveryFee.Distance
+	order.TaxRate, order.TaxAmount = charges.TaxRate, charges.Tax
+	order.ServiceFeeRate, order.ServiceFee = charges.ServiceFeeRate, charges.ServiceFee
+	order.DiscountAmount = charges.Discount
+
+	// the basket is ready once the slower kitchen is done
+	prepTime := s.estimatePrepTime(second, extra)
+	if ready := order.PrepStartTime.Add(time.Minute * time.Duration(prepTime)); ready.After(order.PickupTime) {
+		order.PickupTime = ready
+	}
+}
+
+// pickupStops lists the restaurants an order is collected from, in pickup order
+func pickupStops(order *models.Order) []string {
+	if len(order.RestaurantIDs) > 0 {
+		return order.RestaurantIDs
+	}
+	return []string{order.RestaurantID}
+}
+
+// pickupRestaurant is the restaurant the partner should collect from next
+func (s *Simulator) pickupRestaurant(order *models.Order) *models.Restaurant {
+	stops := pickupStops(order)
+	return s.getRestaurant(stops[min(order.PickupsCompleted, len(stops)-1)])
+}
+
+// advancePickup records a collection at a restaurant that isn't the last stop
+// and sends the partner on to the next one. It returns false once the partner
+// is at the last stop, where the order is picked up as normal.
+func (s *Simulator) advancePickup(order *models.Order, partner *models.DeliveryPartner) bool {
+	stops := pickupStops(order)
+	if order.PickupsCompleted >= len(stops)-1 {
+		return false
+	}
+	order.PickupsCompleted++
+	if partner != nil {
+		partner.Status = models.PartnerStatusEnRoutePickup
+		s.pingOnStatusChange(partner, order.ID)
+	}
+	log.Printf("Collected part of order %s from restaurant %s, heading to restaurant %s",
+		order.ID, stops[order.PickupsCompleted-1], stops[order.PickupsCompleted])
+	return true
+}
+
+// remainingPickupTime estimates how long the partner needs to visit the
+// restaurants still to be collected from, in order
+func (s *Simulator) remainingPickupTime(partner *models.DeliveryPartner, order *models.Order) time.Duration {
+	stops := pickupStops(order)
+	from := partner.CurrentLocation
+	var total time.Duration
+	for _, id := range stops[min(order.PickupsCompleted, len(stops)-1):] {
+		restaurant := s.getRestaurant(id)
+		if restaurant == nil {
+			continue
+		}
+		if !s.isAtLocation(from, restaurant.Location) {
+			total += s.estimateArrivalTime(from, restaurant.Location).Sub(s.CurrentTime)
+		}
+		from = restaurant.Location
+	}
+	return total
+}
@@ -0,0 +1,97 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"sort"
+)
+
+// notifyUser queues a notification about an order if that kind of
+// notification is enabled. Each kind is sent at most once per order, as some
+// lifecycle transitions are reached by more than one path.
+func (s *Simulator) notifyUser(order *models.Order, notificationType string) {
+	if !s.Config.NotificationsEnabled || !contains(s.Config.NotificationTypesEnabled(), notificationType) {
+		return
+	}
+	if s.notified == nil {
+		s.notified = make(map[string]bool)
+	}
+	key := order.ID + "/" + notificationType
+	if s.notified[key] {
+		return
+	}
+	s.notified[key] = true
+
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventNotification,
+		Data: &models.Notification{
+			ID:      generateID(),
+			UserID:  order.CustomerID,
+			Order:   order,
+			Type:    notificationType,
+			Channel: s.selectNotificationChannel(),
+			SentAt:  s.CurrentTime,
+		},
+	})
+}
+
+// notifyDelivered sends the delivered notification, schedules a review
+// reminder and forgets the order's earlier notifications
+func (s *Simulator) notifyDelivered(order *models.Order) {
+	s.notifyUser(order, models.NotificationDelivered)
+	s.forgetNotifications(order)
+	if s.Config.NotificationsEnabled && contains(s.Config.NotificationTypesEnabled(), models.NotificationReviewReminder) {
+		s.EventQueue.Enqueue(&models.Event{
+			Time: order.ActualDeliveryTime.Add(s.Config.ReviewReminderDelay),
+			Type: models.EventNotification,
+			Data: &models.Notification{
+				ID:      generateID(),
+				UserID:  order.CustomerID,
+				Order:   order,
+				Type:    models.NotificationReviewReminder,
+				Channel: s.selectNotificationChannel(),
+				SentAt:  order.ActualDeliveryTime.Add(s.Config.ReviewReminderDelay),
+			},
+		})
+	}
+}
+
+// handleReviewReminder sends the review reminder for an order that is still
+// unreviewed and hasn't been reminded, then gives it another chance of a
+// review at the reminder's conversion rate
+func (s *Simulator) handleReviewReminder(notification *models.Notification) {
+	order := notification.Order
+	if notification.Type != models.NotificationReviewReminder || order.ReviewGenerated || order.ReviewReminderID != "" {
+		return
+	}
+	order.ReviewReminderID = notification.ID
+	s.handleGenerateReview(order)
+}
+
+// forgetNotifications drops the record of which notifications an order has
+// had once it can't be notified again
+func (s *Simulator) forgetNotifications(order *models.Order) {
+	for _, notificationType := range models.DefaultNotificationTypes {
+		delete(s.notified, order.ID+"/"+notificationType)
+	}
+}
+
+func (s *Simulator) selectNotificationChannel() string {
+	weights := s.Config.NotificationChannelWeights()
+	channels := make([]string, 0, len(weights))
+	total := 0.0
+	for channel, weight := range weights {
+		channels = append(channels, channel)
+		total += weight
+	}
+	sort.Strings(channels)
+
+	roll := s.Rng.Float64() * total
+	for _, channel := range channels {
+		if roll < weights[channel] {
+			return channel
+		}
+		roll -= weights[channel]
+	}
+	return channels[len(channels)-1]
+}
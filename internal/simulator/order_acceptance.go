@@ -0,0 +1,116 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"time"
+)
+
+// scheduleAcceptance samples how long the restaurant takes to accept a new
+// order and queues its response. Preparation can't start before the order is
+// accepted. A restaurant slower than acceptance_sla never gets to: the
+// platform rejects the order when the SLA runs out, and false is returned.
+func (s *Simulator) scheduleAcceptance(order *models.Order, restaurant *models.Restaurant) bool {
+	sla := s.Config.AcceptanceSLA
+	if sla <= 0 || restaurant == nil {
+		return true
+	}
+
+	latency := s.sampleAcceptanceLatency(restaurant)
+	acceptedAt := order.OrderPlacedAt.Add(latency)
+	if order.PrepStartTime.Before(acceptedAt) {
+		delay := acceptedAt.Sub(order.PrepStartTime)
+		order.PrepStartTime = acceptedAt
+		order.PickupTime = order.PickupTime.Add(delay)
+	}
+
+	acceptance := &models.OrderAcceptance{
+		OrderID:      order.ID,
+		CustomerID:   order.CustomerID,
+		RestaurantID: restaurant.ID,
+		PlacedAt:     order.OrderPlacedAt,
+		Latency:      latency,
+		SLA:          sla,
+		Rejected:     latency > sla,
+	}
+	at := acceptedAt
+	if acceptance.Rejected {
+		at = order.OrderPlacedAt.Add(sla)
+	} else {
+		order.AcceptedAt = acceptedAt
+	}
+	s.EventQueue.Enqueue(&models.Event{
+		Time: at,
+		Type: models.EventOrderAcceptance,
+		Data: acceptance,
+	})
+	return !acceptance.Rejected
+}
+
+// sampleAcceptanceLatency draws a log-normal acceptance time around the
+// restaurant's cuisine median, slower while the kitchen is at capacity
+func (s *Simulator) sampleAcceptanceLatency(restaurant *models.Restaurant) time.Duration {
+	median := float64(s.Config.AcceptanceTime(restaurant.Cuisines))
+	if restaurant.Capacity > 0 && len(restaurant.CurrentOrders) >= restaurant.Capacity {
+		median *= 2
+	}
+	return time.Duration(median * math.Exp(s.Rng.NormFloat64()*s.Config.AcceptanceTimeSpread))
+}
+
+// handleOrderAcceptance auto-rejects an order the restaurant didn't accept in
+// time: it is cancelled and refunded, and the customer picks another
+// restaurant straight away
+func (s *Simulator) handleOrderAcceptance(acceptance *models.OrderAcceptance) {
+	if !acceptance.Rejected {
+		return
+	}
+	order := s.getOrderByID(acceptance.OrderID)
+	if order == nil || order.Status != models.OrderStatusPlaced {
+		// already cancelled by the customer
+		return
+	}
+
+	order.CancelledBy = models.CancelledBySystem
+	order.CancellationReason = models.CancelReasonAcceptanceTimeout
+	s.handleCancelOrder(order)
+	if restaurant := s.getRestaurant(acceptance.RestaurantID); restaurant != nil {
+		for i, current := range restaurant.CurrentOrders {
+			if current.ID == order.ID {
+				restaurant.CurrentOrders = append(restaurant.CurrentOrders[:i], restaurant.CurrentOrders[i+1:]...)
+				break
+			}
+		}
+	}
+	log.Printf("Order %s auto-rejected after restaurant %s missed the %s acceptance SLA", order.ID, acceptance.RestaurantID, acceptance.SLA)
+
+	user := s.getUser(acceptance.CustomerID)
+	if user == nil {
+		return
+	}
+	if s.rejectedRestaurants == nil {
+		s.rejectedRestaurants = make(map[string]string)
+	}
+	s.rejectedRestaurants[user.ID] = acceptance.RestaurantID
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventPlaceOrder,
+		Data: user,
+	})
+}
+
+// withoutRejectedRestaurant drops the restaurant that just auto-rejected the
+// user's order from the restaurants offered to them
+func (s *Simulator) withoutRejectedRestaurant(restaurants []*models.Restaurant, user *models.User) []*models.Restaurant {
+	rejected, ok := s.rejectedRestaurants[user.ID]
+	if !ok {
+		return restaurants
+	}
+	kept := restaurants[:0:0]
+	for _, restaurant := range restaurants {
+		if restaurant.ID != rejected {
+			kept = append(kept, restaurant)
+		}
+	}
+	return kept
+}
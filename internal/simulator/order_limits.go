@@ -0,0 +1,63 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"sort"
+)
+
+// enforceSubtotalLimits keeps an order's item subtotal within the configured
+// minimum and maximum. Baskets under the minimum are topped up with extra items
+// from the restaurant's menu that suit the user's dietary restrictions, and
+// reports whether that happened; baskets over the maximum lose their most
+// expensive items. Fees are worked out afterwards,
+// so a topped-up basket can still attract the small order fee if the minimum
+// is below small_order_threshold.
+func (s *Simulator) enforceSubtotalLimits(restaurant *models.Restaurant, user *models.User, items []string) ([]string, bool) {
+	subtotal := s.calculateSubtotal(items)
+
+	toppedUp := false
+	if s.Config.MinOrderSubtotal > 0 && subtotal < s.Config.MinOrderSubtotal {
+		var suitable []*models.MenuItem
+		for _, itemID := range restaurant.MenuItems {
+			if item := s.getMenuItem(itemID); item != nil && !s.hasConflictingIngredients(item, user.DietaryRestrictions) {
+				suitable = append(suitable, item)
+			}
+		}
+		for added := 0; len(suitable) > 0 && subtotal < s.Config.MinOrderSubtotal && added < 20; added++ {
+			item := suitable[s.Rng.Intn(len(suitable))]
+			items = append(items, item.ID)
+			subtotal += item.Price
+			toppedUp = true
+		}
+	}
+
+	if s.Config.MaxOrderSubtotal > 0 && subtotal > s.Config.MaxOrderSubtotal {
+		// drop the most expensive items first, always keeping one
+		sort.SliceStable(items, func(i, j int) bool {
+			return s.itemPrice(items[i]) < s.itemPrice(items[j])
+		})
+		for len(items) > 1 && subtotal > s.Config.MaxOrderSubtotal {
+			subtotal -= s.itemPrice(items[len(items)-1])
+			items = items[:len(items)-1]
+		}
+	}
+
+	return items, toppedUp
+}
+
+// calculateSubtotal sums the menu prices of an order's items, before fees,
+// tax and discounts
+func (s *Simulator) calculateSubtotal(items []string) float64 {
+	subtotal := 0.0
+	for _, itemID := range items {
+		subtotal += s.itemPrice(itemID)
+	}
+	return subtotal
+}
+
+func (s *Simulator) itemPrice(itemID string) float64 {
+	if item := s.getMenuItem(itemID); item != nil {
+		return item.Price
+	}
+	return 0
+}
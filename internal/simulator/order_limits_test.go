@@ -0,0 +1,36 @@
+package simulator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestTopUpKeepsToDietaryRestrictions(t *testing.T) {
+	s := NewSimulator(&models.Config{Seed: 3, MinOrderSubtotal: 30})
+	restaurant := &models.Restaurant{ID: "r1"}
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("item-%d", i)
+		item := &models.MenuItem{ID: id, RestaurantID: restaurant.ID, Price: 4}
+		if i == 0 {
+			item.DietaryTags = []string{models.DietVegan}
+		}
+		s.MenuItems[id] = item
+		restaurant.MenuItems = append(restaurant.MenuItems, id)
+	}
+	user := &models.User{ID: "u1", DietaryRestrictions: []string{"Vegan"}}
+
+	items, toppedUp := s.enforceSubtotalLimits(restaurant, user, []string{"item-0"})
+	if !toppedUp {
+		t.Fatal("a 4.00 basket was not topped up to the 30.00 minimum")
+	}
+	for _, id := range items {
+		if id != "item-0" {
+			t.Fatalf("topped up with %s, which is not vegan", id)
+		}
+	}
+	if subtotal := s.calculateSubtotal(items); subtotal < 30 {
+		t.Errorf("topped-up subtotal %.2f, want at least 30.00", subtotal)
+	}
+}
@@ -0,0 +1,31 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"time"
+)
+
+// orderPlacementTime is when an order that fell due at due is placed. By
+// default every order lands on the current time step. With
+// order_placement_smoothing on, an order keeps the time it fell due, or takes
+// a random point within the current step when it has none or it lies outside
+// the neighbouring steps, so placements spread evenly rather than bunching
+// every 10 minutes.
+func (s *Simulator) orderPlacementTime(due time.Time) time.Time {
+	if !s.Config.OrderPlacementSmoothing {
+		return s.CurrentTime
+	}
+	if due.IsZero() || due.Before(s.CurrentTime.Add(-simulationTimeStep)) || due.After(s.CurrentTime.Add(simulationTimeStep)) {
+		return s.CurrentTime.Add(time.Duration(s.Rng.Int63n(int64(simulationTimeStep))))
+	}
+	return due
+}
+
+// placeOrderAt moves an order's placement, and the preparation times that
+// follow from it, to placedAt
+func placeOrderAt(order *models.Order, placedAt time.Time) {
+	offset := placedAt.Sub(order.OrderPlacedAt)
+	order.OrderPlacedAt = placedAt
+	order.PrepStartTime = order.PrepStartTime.Add(offset)
+	order.PickupTime = order.PickupTime.Add(offset)
+}
@@ -0,0 +1,66 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestSmoothedOrdersSpreadAcrossTheStep(t *testing.T) {
+	sim := NewSimulator(testConfig(t, map[string]interface{}{
+		"start_date":                "2024-03-01T15:00:00Z",
+		"order_frequency":           200,
+		"order_placement_smoothing": true,
+	}))
+	if err := sim.initializeData(); err != nil {
+		t.Fatal(err)
+	}
+	sim.CurrentTime = sim.Config.StartDate
+	sim.generateOrders()
+	if len(sim.Orders) < 100 {
+		t.Fatalf("placed %d orders in one step, too few to check", len(sim.Orders))
+	}
+
+	// every minute of the step gets its share rather than all landing on its start
+	perMinute := make([]int, int(simulationTimeStep/time.Minute))
+	onBoundary := 0
+	for _, order := range sim.Orders {
+		offset := order.OrderPlacedAt.Sub(sim.CurrentTime)
+		if offset < 0 || offset >= simulationTimeStep {
+			t.Fatalf("order %s placed %s into a %s step", order.ID, offset, simulationTimeStep)
+		}
+		if offset == 0 {
+			onBoundary++
+		}
+		perMinute[int(offset/time.Minute)]++
+	}
+	fair := len(sim.Orders) / len(perMinute)
+	for minute, n := range perMinute {
+		if n < fair/3 {
+			t.Errorf("%d orders placed in minute %d of the step, want about %d", n, minute, fair)
+		}
+	}
+	if onBoundary > len(sim.Orders)/20 {
+		t.Errorf("%d of %d orders placed exactly on the step boundary", onBoundary, len(sim.Orders))
+	}
+
+	// nobody is sent for an order before it has been placed
+	assignAt := make(map[string]time.Time)
+	for event := sim.EventQueue.Dequeue(); event != nil; event = sim.EventQueue.Dequeue() {
+		if event.Type == models.EventAssignDeliveryPartner {
+			assignAt[event.Data.(*models.Order).ID] = event.Time
+		}
+	}
+	for _, order := range sim.Orders {
+		if !order.OrderPlacedAt.After(sim.CurrentTime) {
+			continue
+		}
+		if order.DeliveryPartnerID != "" {
+			t.Errorf("order %s placed at %s already has a partner at %s", order.ID, order.OrderPlacedAt, sim.CurrentTime)
+		}
+		if at, ok := assignAt[order.ID]; !ok || at.Before(order.OrderPlacedAt) {
+			t.Errorf("order %s placed at %s is assigned at %s, want at its placement", order.ID, order.OrderPlacedAt, at)
+		}
+	}
+}
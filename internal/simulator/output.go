@@ -1,6 +1,7 @@
 package simulator
 
 import (
+	"compress/gzip"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -16,8 +17,10 @@ import (
 	"io"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -28,21 +31,26 @@ type OutputDestination interface {
 }
 
 type CSVOutput struct {
-	basePath string
-	folder   string
-	files    map[string]*csv.Writer
-	headers  map[string][]string
+	basePath    string
+	folder      string
+	compression string
+	appendRun   string
+	cloud       *cloudTarget
+	mu          sync.Mutex
+	files       map[string]*csv.Writer
+	outputs     map[string]*outputFile
+	headers     map[string][]string
 }
 
 type ParquetOutput struct {
-	basePath           string
-	folder             string
-	mu                 sync.Mutex
-	writers            map[string]*writer.ParquetWriter
-	writerMutexes      map[string]*sync.Mutex
-	files              map[string]source.ParquetFile
-	cloudWriterFactory cloudwriter.CloudWriterFactory
-	cloudBucketName    string
+	basePath      string
+	folder        string
+	appendRun     string
+	mu            sync.Mutex
+	writers       map[string]*writer.ParquetWriter
+	writerMutexes map[string]*sync.Mutex
+	files         map[string]source.ParquetFile
+	cloud         *cloudTarget
 }
 
 type ConsoleOutput struct{}
@@ -57,9 +65,164 @@ type KafkaOutput struct {
 }
 
 type JSONOutput struct {
-	basePath string
-	folder   string
-	files    map[string]*os.File
+	basePath    string
+	folder      string
+	compression string
+	appendRun   string
+	cloud       *cloudTarget
+	mu          sync.Mutex
+	files       map[string]*outputFile
+}
+
+// cloudTarget is the bucket file outputs write to when the output destination
+// is cloud storage rather than the local filesystem
+type cloudTarget struct {
+	factory cloudwriter.CloudWriterFactory
+	bucket  string
+	prefix  string
+}
+
+// newCloudTarget returns nil when output should be written locally
+func newCloudTarget(config *models.Config) (*cloudTarget, error) {
+	if config.OutputDestination == "local" {
+		return nil, nil
+	}
+
+	var factory cloudwriter.CloudWriterFactory
+	var err error
+	cs := config.CloudStorage
+	switch cs.Provider {
+	case "gcs":
+		factory, err = cloudwriter.NewGCSWriterFactory(cs.AccessKeyID, cs.SecretAccessKey)
+	case "s3":
+		factory, err = cloudwriter.NewS3CompatibleWriterFactory(cs.Region, cs.Endpoint, cs.AccessKeyID, cs.SecretAccessKey)
+	case "azure":
+		log.Printf("Warning: Azure Blob Storage output is not supported yet, writing files locally")
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported cloud storage provider: %s", cs.Provider)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud writer factory: %w", err)
+	}
+
+	return &cloudTarget{factory: factory, bucket: cs.BucketName, prefix: cs.Prefix}, nil
+}
+
+// newWriter creates a writer for relPath (folder/topic/partition/file) under the configured prefix
+func (t *cloudTarget) newWriter(relPath string) (cloudwriter.CloudWriter, error) {
+	return t.factory.NewWriter(t.bucket, path.Join(t.prefix, filepath.ToSlash(relPath)))
+}
+
+// appendRunID names the files an appending run adds where it can't append to
+// existing ones, and is empty when output files are truncated
+func appendRunID(config *models.Config) string {
+	if config.OutputMode != models.OutputModeAppend {
+		return ""
+	}
+	return time.Now().UTC().Format("20060102T150405")
+}
+
+// withRunSuffix inserts the run ID before a file name's extension
+func withRunSuffix(relPath, run string) string {
+	dir, file := filepath.Split(relPath)
+	ext := filepath.Ext(file)
+	return filepath.Join(dir, strings.TrimSuffix(file, ext)+"-"+run+ext)
+}
+
+// outputFile is a partition file, local or in cloud storage, with an optional
+// compression layer on top
+type outputFile struct {
+	target   io.WriteCloser
+	gz       *gzip.Writer
+	existing string // Local path of the non-empty file being appended to, empty for a new file
+}
+
+// createOutputFile creates a partition file, or when appendRun is set opens
+// the existing local file for appending. Appended gzip output is a new gzip
+// member, which readers decompress as one stream. Cloud objects can't be
+// appended to, so an appending run writes its own object next to them.
+func createOutputFile(cloud *cloudTarget, basePath, relPath, compression, appendRun string) (*outputFile, error) {
+	if cloud != nil && appendRun != "" {
+		relPath = withRunSuffix(relPath, appendRun)
+	}
+	if compression == "gzip" {
+		relPath += ".gz"
+	}
+
+	var existing string
+
+	var target io.WriteCloser
+	if cloud != nil {
+		cloudWriter, err := cloud.newWriter(relPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cloud file writer: %w", err)
+		}
+		target = cloudWriter
+	} else {
+		localPath := filepath.Join(basePath, relPath)
+		if err := os.MkdirAll(filepath.Dir(localPath), os.ModePerm); err != nil {
+			return nil, err
+		}
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if appendRun != "" {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+		file, err := os.OpenFile(localPath, flags, 0666)
+		if err != nil {
+			return nil, err
+		}
+		if appendRun != "" {
+			if info, err := file.Stat(); err == nil && info.Size() > 0 {
+				existing = localPath
+			}
+		}
+		target = file
+	}
+
+	f := &outputFile{target: target, existing: existing}
+	if compression == "gzip" {
+		f.gz = gzip.NewWriter(target)
+	}
+	return f, nil
+}
+
+// readCSVHeader reads the header row of an existing CSV output file
+func readCSVHeader(localPath string, gzipped bool) ([]string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if gzipped {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return csv.NewReader(r).Read()
+}
+
+func (f *outputFile) Write(p []byte) (int, error) {
+	if f.gz != nil {
+		return f.gz.Write(p)
+	}
+	return f.target.Write(p)
+}
+
+// Close flushes and closes the compression layer before closing the file
+func (f *outputFile) Close() error {
+	if f.gz != nil {
+		if err := f.gz.Close(); err != nil {
+			f.target.Close()
+			return err
+		}
+	}
+	return f.target.Close()
 }
 
 type CloudParquetFile struct {
@@ -80,55 +243,58 @@ func (c *CloudParquetFile) Create(name string) (source.ParquetFile, error) {
 	return c, nil
 }
 
-func NewCSVOutput(basePath, folder string) *CSVOutput {
-	return &CSVOutput{
-		basePath: basePath,
-		folder:   folder,
-		files:    make(map[string]*csv.Writer),
-		headers:  make(map[string][]string),
+func NewCSVOutput(config *models.Config) (*CSVOutput, error) {
+	cloud, err := newCloudTarget(config)
+	if err != nil {
+		return nil, err
 	}
+	return &CSVOutput{
+		basePath:    config.OutputPath,
+		folder:      config.OutputFolder,
+		compression: config.OutputCompression,
+		appendRun:   appendRunID(config),
+		cloud:       cloud,
+		files:       make(map[string]*csv.Writer),
+		outputs:     make(map[string]*outputFile),
+		headers:     make(map[string][]string),
+	}, nil
 }
 
-func NewJSONOutput(basePath, folder string) *JSONOutput {
-	return &JSONOutput{
-		basePath: basePath,
-		folder:   folder,
-		files:    make(map[string]*os.File),
+func NewJSONOutput(config *models.Config) (*JSONOutput, error) {
+	cloud, err := newCloudTarget(config)
+	if err != nil {
+		return nil, err
 	}
+	return &JSONOutput{
+		basePath:    config.OutputPath,
+		folder:      config.OutputFolder,
+		compression: config.OutputCompression,
+		appendRun:   appendRunID(config),
+		cloud:       cloud,
+		files:       make(map[string]*outputFile),
+	}, nil
 }
 
 func NewParquetOutput(config *models.Config) (*ParquetOutput, error) {
 	p := &ParquetOutput{
 		basePath:      config.OutputPath,
 		folder:        config.OutputFolder,
+		appendRun:     appendRunID(config),
 		writers:       make(map[string]*writer.ParquetWriter),
 		writerMutexes: make(map[string]*sync.Mutex),
 		files:         make(map[string]source.ParquetFile),
 	}
 
-	if config.OutputDestination != "local" {
-		var factory cloudwriter.CloudWriterFactory
-		var err error
-
-		switch config.CloudStorage.Provider {
-		case "gcs":
-		case "s3":
-			factory, err = cloudwriter.NewS3WriterFactory(config.CloudStorage.Region)
-		case "azure":
-		default:
-			return nil, fmt.Errorf("unsupported cloud storage provider: %s", config.CloudStorage.Provider)
-		}
-
-		if err != nil {
-			return nil, fmt.Errorf("failed to create cloud writer factory: %w", err)
-		}
-
-		p.cloudWriterFactory = factory
-		p.cloudBucketName = config.CloudStorage.BucketName
+	cloud, err := newCloudTarget(config)
+	if err != nil {
+		return nil, err
 	}
+	p.cloud = cloud
 
-	// clean up existing .parquet files
-	p.cleanup()
+	// clean up existing .parquet files, unless this run adds to them
+	if p.appendRun == "" {
+		p.cleanup()
+	}
 
 	return p, nil
 }
@@ -182,25 +348,31 @@ func (c *CSVOutput) WriteMessage(topic string, msg []byte) error {
 	hour := eventTime.Hour()
 
 	partitionPath := fmt.Sprintf("year=%d/month=%02d/day=%02d/hour=%02d", year, month, day, hour)
-	fullPath := filepath.Join(c.basePath, c.folder, topic, partitionPath)
+	relPath := filepath.Join(c.folder, topic, partitionPath, "data.csv")
 
-	if err := os.MkdirAll(fullPath, os.ModePerm); err != nil {
-		return err
-	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	fileKey := fmt.Sprintf("%s_%s", topic, partitionPath)
 	csvWriter, ok := c.files[fileKey]
 	if !ok {
-		file, err := os.Create(filepath.Join(fullPath, "data.csv"))
+		file, err := createOutputFile(c.cloud, c.basePath, relPath, c.compression, c.appendRun)
 		if err != nil {
 			return err
 		}
 		csvWriter = csv.NewWriter(file)
 		c.files[fileKey] = csvWriter
+		c.outputs[fileKey] = file
 
-		// Write headers if this is a new file
+		// Write headers if this is a new file; rows appended to an existing
+		// file follow its header, so fields it doesn't have are left out
 		headers := c.getHeaders(event)
-		if err := csvWriter.Write(headers); err != nil {
+		if file.existing != "" {
+			headers, err = readCSVHeader(file.existing, c.compression == "gzip")
+			if err != nil {
+				return fmt.Errorf("failed to read header of %s: %w", file.existing, err)
+			}
+		} else if err := csvWriter.Write(headers); err != nil {
 			return err
 		}
 		c.headers[fileKey] = headers
@@ -235,13 +407,20 @@ func (c *CSVOutput) getHeaders(event map[string]interface{}) []string {
 }
 
 func (c *CSVOutput) Close() error {
-	for _, csvWriter := range c.files {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var lastErr error
+	for key, csvWriter := range c.files {
 		csvWriter.Flush()
 		if err := csvWriter.Error(); err != nil {
-			return err
+			lastErr = err
+		}
+		if err := c.outputs[key].Close(); err != nil {
+			lastErr = err
 		}
 	}
-	return nil
+	return lastErr
 }
 
 func (j *JSONOutput) WriteMessage(topic string, msg []byte) error {
@@ -260,17 +439,16 @@ func (j *JSONOutput) WriteMessage(topic string, msg []byte) error {
 	hour := eventTime.Hour()
 
 	partitionPath := fmt.Sprintf("year=%d/month=%02d/day=%02d/hour=%02d", year, month, day, hour)
-	fullPath := filepath.Join(j.basePath, j.folder, topic, partitionPath)
+	relPath := filepath.Join(j.folder, topic, partitionPath, "data.json")
 
-	if err := os.MkdirAll(fullPath, os.ModePerm); err != nil {
-		return err
-	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
 
 	fileKey := fmt.Sprintf("%s_%s", topic, partitionPath)
 	file, ok := j.files[fileKey]
 	if !ok {
 		var err error
-		file, err = os.Create(filepath.Join(fullPath, "data.json"))
+		file, err = createOutputFile(j.cloud, j.basePath, relPath, j.compression, j.appendRun)
 		if err != nil {
 			return err
 		}
@@ -282,20 +460,23 @@ func (j *JSONOutput) WriteMessage(topic string, msg []byte) error {
 		return err
 	}
 
-	if _, err := file.Write(jsonData); err != nil {
+	if _, err := file.Write(append(jsonData, '\n')); err != nil {
 		return err
 	}
-	_, err = file.WriteString("\n")
-	return err
+	return nil
 }
 
 func (j *JSONOutput) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var lastErr error
 	for _, file := range j.files {
 		if err := file.Close(); err != nil {
-			return err
+			lastErr = err
 		}
 	}
-	return nil
+	return lastErr
 }
 
 func (p *ParquetOutput) WriteMessage(topic string, msg []byte) error {
@@ -316,8 +497,10 @@ func (p *ParquetOutput) WriteMessage(topic string, msg []byte) error {
 	partitionPath := fmt.Sprintf("year=%d/month=%02d/day=%02d/hour=%02d", year, month, day, hour)
 	fullPath := filepath.Join(p.basePath, p.folder, topic, partitionPath)
 
-	if err := os.MkdirAll(fullPath, os.ModePerm); err != nil {
-		return err
+	if p.cloud == nil {
+		if err := os.MkdirAll(fullPath, os.ModePerm); err != nil {
+			return err
+		}
 	}
 
 	writerKey := fmt.Sprintf("%s_%s", topic, partitionPath)
@@ -326,7 +509,7 @@ func (p *ParquetOutput) WriteMessage(topic string, msg []byte) error {
 	if !ok {
 		// create new writer if it doesn't exist
 		var err error
-		pw, err = p.createNewWriter(writerKey, fullPath, topic)
+		pw, err = p.createNewWriter(writerKey, fullPath, topic, partitionPath)
 		if err != nil {
 			p.mu.Unlock()
 			return fmt.Errorf("failed to create new writer: %w", err)
@@ -344,29 +527,6 @@ func (p *ParquetOutput) WriteMessage(topic string, msg []byte) error {
 		return fmt.Errorf("ParquetWriter is nil for key: %s", writerKey)
 	}
 
-	if !ok {
-		filePath := filepath.Join(fullPath, "data.parquet")
-		fw, err := local.NewLocalFileWriter(filePath)
-		if err != nil {
-			return err
-		}
-
-		sc, err := GetSchema(topic)
-		if err != nil {
-			return fmt.Errorf("failed to create schema: %w", err)
-		}
-
-		pw, err = writer.NewParquetWriter(fw, nil, 4)
-		if err != nil {
-			return err
-		}
-		pw.SchemaHandler = sc
-
-		p.mu.Lock() // lock before writing to the map
-		p.writers[writerKey] = pw
-		p.mu.Unlock() // unlock immediately after
-	}
-
 	if err := pw.Write(event); err != nil {
 		return fmt.Errorf("failed to write event: %w", err)
 	}
@@ -393,18 +553,24 @@ func (p *ParquetOutput) cleanup() {
 	}
 }
 
-func (p *ParquetOutput) createNewWriter(writerKey, fullPath, topic string) (*writer.ParquetWriter, error) {
+func (p *ParquetOutput) createNewWriter(writerKey, fullPath, topic, partitionPath string) (*writer.ParquetWriter, error) {
+	// a parquet file can't be reopened for writing, so each appending run adds
+	// its own file to the partition
+	fileName := "data.parquet"
+	if p.appendRun != "" {
+		fileName = withRunSuffix(fileName, p.appendRun)
+	}
+
 	var fw source.ParquetFile
 	var err error
-	if p.cloudWriterFactory != nil {
-		objectPath := filepath.Join(p.folder, topic, fullPath, "data.parquet")
-		cloudWriter, err := p.cloudWriterFactory.NewWriter(p.cloudBucketName, objectPath)
+	if p.cloud != nil {
+		cloudWriter, err := p.cloud.newWriter(filepath.Join(p.folder, topic, partitionPath, fileName))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create cloud file writer: %w", err)
 		}
 		fw = NewCloudParquetFile(cloudWriter)
 	} else {
-		filePath := filepath.Join(fullPath, "data.parquet")
+		filePath := filepath.Join(fullPath, fileName)
 		fw, err = local.NewLocalFileWriter(filePath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create local file writer: %w", err)
@@ -527,15 +693,18 @@ func (c *ConsoleOutput) WriteMessage(topic string, msg []byte) error {
 	return nil
 }
 
-func (s *Simulator) determineOutputDestination() OutputDestination {
+func (s *Simulator) determineOutputDestination() (OutputDestination, error) {
+	if s.output != nil {
+		return s.output, nil
+	}
 	if s.Config.KafkaEnabled {
 		if s.Config.KafkaUseLocal {
 			// use Sarama for local Kafka
 			saramaProducer, err := simulator.NewSaramaProducer(s.Config)
 			if err != nil {
-				log.Fatalf("Failed to create Sarama producer: %v", err)
+				return nil, fmt.Errorf("failed to create Sarama producer: %w", err)
 			}
-			return saramaProducer
+			return newThrottledOutput(saramaProducer, s.Config), nil
 		} else {
 			// use Confluent's Kafka client for Confluent Cloud
 			confluentConfig := kafka.ConfigMap{
@@ -557,31 +726,39 @@ func (s *Simulator) determineOutputDestination() OutputDestination {
 
 			confluentProducer, err := simulator.NewConfluentProducer(confluentConfig)
 			if err != nil {
-				log.Fatalf("Failed to create Confluent Kafka producer: %v", err)
+				return nil, fmt.Errorf("failed to create Confluent Kafka producer: %w", err)
 			}
-			return confluentProducer
+			return newThrottledOutput(confluentProducer, s.Config), nil
 		}
 	} else if s.Config.OutputPath != "" {
 		switch s.Config.OutputFormat {
 		case "parquet":
 			parquetOutput, err := NewParquetOutput(s.Config)
 			if err != nil {
-				log.Fatalf("Failed to create Parquet output: %s", err)
+				return nil, fmt.Errorf("failed to create Parquet output: %w", err)
 			}
-			return parquetOutput
+			return parquetOutput, nil
 		case "postgres":
 			pgOutput, err := output.NewPostgresOutput(&s.Config.Database)
 			if err != nil {
-				log.Fatalf("Failed to create Postgres output: %s", err)
+				return nil, fmt.Errorf("failed to create Postgres output: %w", err)
 			}
-			return pgOutput
+			return pgOutput, nil
 		case "json":
-			return NewJSONOutput(s.Config.OutputPath, s.Config.OutputFolder)
+			jsonOutput, err := NewJSONOutput(s.Config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create JSON output: %w", err)
+			}
+			return jsonOutput, nil
 		case "csv":
-			return NewCSVOutput(s.Config.OutputPath, s.Config.OutputFolder)
+			csvOutput, err := NewCSVOutput(s.Config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create CSV output: %w", err)
+			}
+			return csvOutput, nil
 		default:
-			log.Fatalf("Unsupported output format: %s", s.Config.OutputFormat)
+			return nil, fmt.Errorf("unsupported output format: %s", s.Config.OutputFormat)
 		}
 	}
-	return &ConsoleOutput{}
+	return &ConsoleOutput{}, nil
 }
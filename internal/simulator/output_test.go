@@ -0,0 +1,97 @@
+package simulator
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// readGzipOutput returns the decompressed contents of the single .gz file
+// written under dir
+func readGzipOutput(t *testing.T, dir string) string {
+	t.Helper()
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && strings.HasSuffix(path, ".gz") {
+			paths = append(paths, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("got %d compressed files, want 1: %v", len(paths), paths)
+	}
+
+	file, err := os.Open(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func gzipOutputConfig(t *testing.T) *models.Config {
+	return &models.Config{
+		OutputDestination: "local",
+		OutputPath:        t.TempDir(),
+		OutputFolder:      "events",
+		OutputCompression: "gzip",
+	}
+}
+
+const compressedMessage = `{"eventType":"order_placed","orderId":"order-1","timestamp":1709290800}`
+
+func TestJSONOutputGzipReadBack(t *testing.T) {
+	config := gzipOutputConfig(t)
+	out, err := NewJSONOutput(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := out.WriteMessage("order_placed_events", []byte(compressedMessage)); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.TrimSpace(readGzipOutput(t, config.OutputPath)); got != compressedMessage {
+		t.Errorf("read back %q, want %q", got, compressedMessage)
+	}
+}
+
+func TestCSVOutputGzipReadBack(t *testing.T) {
+	config := gzipOutputConfig(t)
+	out, err := NewCSVOutput(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := out.WriteMessage("order_placed_events", []byte(compressedMessage)); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(readGzipOutput(t, config.OutputPath)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want a header and one row: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], "orderId") || !strings.Contains(lines[1], "order-1") {
+		t.Errorf("unexpected CSV contents %q", lines)
+	}
+}
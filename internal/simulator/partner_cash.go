@@ -0,0 +1,68 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"time"
+)
+
+// collectCash adds what the customer paid in cash, tip included, to the cash
+// the partner carries
+func (s *Simulator) collectCash(partner *models.DeliveryPartner, order *models.Order) {
+	if order.PaymentMethod != models.PaymentCash {
+		return
+	}
+	partner.CashOnHand += order.TotalAmount + order.Tip
+}
+
+// partnersWithCashRoom drops, for a cash order, partners who couldn't collect
+// its payment without going over partner_cash_limit. Card and wallet orders
+// can go to anyone, and so can a cash order worth more than the limit on its
+// own, which no partner could otherwise ever take.
+func (s *Simulator) partnersWithCashRoom(partners []*models.DeliveryPartner, order *models.Order) []*models.DeliveryPartner {
+	limit := s.Config.PartnerCashLimit
+	if limit <= 0 || order.PaymentMethod != models.PaymentCash {
+		return partners
+	}
+	amount := order.TotalAmount + order.Tip
+	if amount > limit {
+		return partners
+	}
+	eligible := partners[:0:0]
+	for _, partner := range partners {
+		if partner.CashOnHand+amount <= limit {
+			eligible = append(eligible, partner)
+		}
+	}
+	return eligible
+}
+
+// scheduleCashDrops sends idle partners carrying at least
+// partner_cash_drop_share of the cash limit to drop it off. They are offline
+// for partner_cash_drop_duration and come back with nothing on hand.
+func (s *Simulator) scheduleCashDrops() {
+	limit := s.Config.PartnerCashLimit
+	if limit <= 0 {
+		return
+	}
+	threshold := limit * s.Config.PartnerCashDropShare
+	for _, partner := range s.DeliveryPartners {
+		if partner == nil || partner.Status != models.PartnerStatusAvailable || partner.CashOnHand < threshold {
+			continue
+		}
+		drop := &models.PartnerCashDrop{
+			PartnerID: partner.ID,
+			Amount:    partner.CashOnHand,
+			Until:     s.CurrentTime.Add(s.Config.PartnerCashDropDuration),
+		}
+		partner.CashOnHand = 0
+		partner.Status = models.PartnerStatusOffline
+		partner.OfflineUntil = drop.Until
+		log.Printf("Partner %s dropping off %.2f in cash until %s", partner.ID, drop.Amount, drop.Until.Format(time.RFC3339))
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventPartnerCashDrop,
+			Data: drop,
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package simulator
+
+import (
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestPartnersWithCashRoom(t *testing.T) {
+	s := NewSimulator(&models.Config{Seed: 1, PartnerCashLimit: 100})
+	partners := []*models.DeliveryPartner{
+		{ID: "empty"},
+		{ID: "half", CashOnHand: 50},
+		{ID: "full", CashOnHand: 95},
+	}
+	tests := []struct {
+		name   string
+		order  *models.Order
+		wanted []string
+	}{
+		{"card order", &models.Order{PaymentMethod: models.PaymentCard, TotalAmount: 40}, []string{"empty", "half", "full"}},
+		{"small cash order", &models.Order{PaymentMethod: models.PaymentCash, TotalAmount: 30, Tip: 5}, []string{"empty", "half"}},
+		{"large cash order", &models.Order{PaymentMethod: models.PaymentCash, TotalAmount: 90}, []string{"empty"}},
+		{"cash order over the limit", &models.Order{PaymentMethod: models.PaymentCash, TotalAmount: 110}, []string{"empty", "half", "full"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.partnersWithCashRoom(partners, tt.order)
+			if len(got) != len(tt.wanted) {
+				t.Fatalf("got %d partners, want %v", len(got), tt.wanted)
+			}
+			for i, partner := range got {
+				if partner.ID != tt.wanted[i] {
+					t.Errorf("partner %d is %s, want %s", i, partner.ID, tt.wanted[i])
+				}
+			}
+		})
+	}
+}
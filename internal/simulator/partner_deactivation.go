@@ -0,0 +1,73 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+)
+
+// enforcePartnerRatings deactivates partners whose rating has fallen below
+// partner_deactivation_rating once they have enough ratings to judge. A
+// partner carrying an order finishes the delivery first; orders not yet
+// collected are handed back for reassignment.
+func (s *Simulator) enforcePartnerRatings() {
+	threshold := s.Config.PartnerDeactivationRating
+	if threshold <= 0 {
+		return
+	}
+	for _, partner := range s.DeliveryPartners {
+		if partner == nil || partner.Status == models.PartnerStatusDeactivated {
+			continue
+		}
+		if partner.TotalRatings < float64(s.Config.PartnerDeactivationMinRatings) || partner.Rating >= threshold {
+			continue
+		}
+		if s.partnerCarryingOrder(partner) {
+			continue
+		}
+		s.deactivatePartner(partner)
+	}
+}
+
+// partnerCarryingOrder reports whether the partner has collected an order
+// that hasn't been delivered yet
+func (s *Simulator) partnerCarryingOrder(partner *models.DeliveryPartner) bool {
+	for _, order := range s.Orders {
+		if order.DeliveryPartnerID != partner.ID {
+			continue
+		}
+		if order.Status == models.OrderStatusPickedUp || order.Status == models.OrderStatusInTransit {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Simulator) deactivatePartner(partner *models.DeliveryPartner) {
+	deactivation := &models.PartnerDeactivation{
+		PartnerID:     partner.ID,
+		Rating:        partner.Rating,
+		TotalRatings:  partner.TotalRatings,
+		DeactivatedAt: s.CurrentTime,
+	}
+	for i := range s.Orders {
+		order := &s.Orders[i]
+		if order.DeliveryPartnerID != partner.ID || order.Status == models.OrderStatusDelivered || order.Status == models.OrderStatusCancelled {
+			continue
+		}
+		// released orders are picked up by the next assignment pass
+		order.DeliveryPartnerID = ""
+		deactivation.HandedOffOrders = append(deactivation.HandedOffOrders, order.ID)
+	}
+
+	partner.Status = models.PartnerStatusDeactivated
+	partner.CurrentOrderID = ""
+	partner.DeactivatedAt = s.CurrentTime
+	log.Printf("Deactivated partner %s with rating %.2f over %.0f ratings, handing off %d orders",
+		partner.ID, partner.Rating, partner.TotalRatings, len(deactivation.HandedOffOrders))
+
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventPartnerDeactivated,
+		Data: deactivation,
+	})
+}
@@ -0,0 +1,69 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/factories"
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"time"
+)
+
+// updatePartnerExperience moves a partner along the learning curve. Experience
+// closes about two thirds of the gap to fully experienced every
+// partner_learning_days of tenure, so new partners improve quickly over their
+// first weeks and then level off.
+func (s *Simulator) updatePartnerExperience(partner *models.DeliveryPartner) {
+	if s.Config.PartnerLearningDays <= 0 {
+		return
+	}
+	tenureDays := math.Max(0, s.CurrentTime.Sub(partner.JoinDate).Hours()/24)
+	partner.Experience = 1 - math.Exp(-tenureDays/s.Config.PartnerLearningDays)
+}
+
+// experienceSpeedFactor is the share of full speed a partner manages given
+// their experience; a brand new partner is slower by partner_experience_speed_impact
+func (s *Simulator) experienceSpeedFactor(partner *models.DeliveryPartner) float64 {
+	return 1 - s.Config.PartnerExperienceSpeedImpact*(1-partner.Experience)
+}
+
+// movePartner moves a partner towards a location, at a speed scaled by their experience
+func (s *Simulator) movePartner(partner *models.DeliveryPartner, to models.Location, duration time.Duration) models.Location {
+	effective := time.Duration(float64(duration) * s.experienceSpeedFactor(partner))
+	return s.moveTowards(partner.CurrentLocation, to, effective)
+}
+
+// navigationDelay is the time an inexperienced partner loses finding the
+// drop-off point, such as the wrong entrance or a missed turn
+func (s *Simulator) navigationDelay(order *models.Order) time.Duration {
+	partner := s.getDeliveryPartner(order.DeliveryPartnerID)
+	if partner == nil || s.Config.PartnerNavigationErrorRate <= 0 {
+		return 0
+	}
+	if s.Rng.Float64() >= s.Config.PartnerNavigationErrorRate*(1-partner.Experience) {
+		return 0
+	}
+	return time.Duration(3+s.Rng.Intn(10)) * time.Minute
+}
+
+// growPartners adds new delivery partners in line with partner_growth_rate.
+// New partners join with no experience.
+func (s *Simulator) growPartners() {
+	dailyGrowthRate := math.Pow(1+s.Config.PartnerGrowthRate, 1.0/365.0) - 1
+	daysSinceStart := s.CurrentTime.Sub(s.Config.StartDate).Hours() / 24
+	expectedPartners := float64(s.Config.InitialPartners) * math.Pow(1+dailyGrowthRate, daysSinceStart)
+
+	newPartnersToAdd := int(expectedPartners) - len(s.DeliveryPartners)
+	if newPartnersToAdd <= 0 {
+		return
+	}
+	partnerFactory := &factories.DeliveryPartnerFactory{}
+	for i := 0; i < newPartnersToAdd; i++ {
+		var partner *models.DeliveryPartner
+		s.growth.Generate(func() { partner = partnerFactory.CreateDeliveryPartner(s.Config) })
+		partner.JoinDate = s.CurrentTime
+		partner.Experience = 0
+		partner.LastUpdateTime = s.CurrentTime
+		s.DeliveryPartners = append(s.DeliveryPartners, partner)
+	}
+	log.Printf("Added %d new delivery partners. Total partners: %d", newPartnersToAdd, len(s.DeliveryPartners))
+}
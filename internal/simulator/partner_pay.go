@@ -0,0 +1,66 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// recordPartnerDelivery adds a completed delivery, its tip and its
+// per-delivery pay to the shift. Cash
+// the partner collected is added to what they carry.
+func (s *Simulator) recordPartnerDelivery(partner *models.DeliveryPartner, order *models.Order) {
+	s.collectCash(partner, order)
+	stats := &partner.ShiftStats
+	stats.Deliveries++
+	stats.Tips += order.Tip
+	stats.DeliveryPay += s.partnerDeliveryPay(order)
+}
+
+// partnerDeliveryPay is the per-delivery and per-km pay for an order, 0 when
+// partners are paid purely by the hour
+func (s *Simulator) partnerDeliveryPay(order *models.Order) float64 {
+	if s.Config.PartnerPayModel == models.PayModelHourly {
+		return 0
+	}
+	distance := 0.0
+	if restaurant := s.getRestaurant(order.RestaurantID); restaurant != nil {
+		distance = s.calculateDistance(restaurant.Location, orderLocation(order))
+	}
+	return s.Config.PartnerPerDeliveryPay + s.Config.PartnerPerKmPay*distance
+}
+
+// accruePartnerPay counts a time step towards each working partner's shift.
+// Hourly pay accrues whether or not the partner has an order; partners who
+// are offline or deactivated earn nothing.
+func (s *Simulator) accruePartnerPay() {
+	for _, partner := range s.DeliveryPartners {
+		if partner == nil || partner.Status == models.PartnerStatusOffline || partner.Status == models.PartnerStatusDeactivated {
+			continue
+		}
+		partner.ShiftStats.OnlineMinutes += simulationTimeStep.Minutes()
+		if s.Config.PartnerPayModel == models.PayModelHourly {
+			partner.ShiftStats.HourlyPay += s.Config.PartnerHourlyRate * simulationTimeStep.Hours()
+		}
+	}
+}
+
+// shiftPayout settles a partner's pay for a finished shift. Under the hybrid
+// model per-delivery pay is topped up to the hourly floor for time online.
+func (s *Simulator) shiftPayout(partner *models.DeliveryPartner, stats models.PartnerShiftStats) *models.PartnerPayout {
+	payout := &models.PartnerPayout{
+		PartnerID:     partner.ID,
+		PayModel:      s.Config.PartnerPayModel,
+		ShiftStart:    stats.ShiftStart,
+		ShiftEnd:      stats.ShiftEnd,
+		OnlineMinutes: stats.OnlineMinutes,
+		Deliveries:    stats.Deliveries,
+		DeliveryPay:   stats.DeliveryPay,
+		HourlyPay:     stats.HourlyPay,
+		Tips:          stats.Tips,
+	}
+	if s.Config.PartnerPayModel == models.PayModelHybrid {
+		floor := s.Config.PartnerHourlyFloor * stats.OnlineMinutes / 60
+		payout.GuaranteeTopUp = math.Max(0, floor-stats.DeliveryPay)
+	}
+	return payout
+}
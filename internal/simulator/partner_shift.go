@@ -0,0 +1,145 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"time"
+)
+
+// selectAcceptingPartner offers an order to the candidates in random order and
+// returns the first to accept, or nil if every one of them declines. With
+// tip_priority_weight set, low-value orders are declined more often.
+func (s *Simulator) selectAcceptingPartner(candidates []*models.DeliveryPartner, order *models.Order) *models.DeliveryPartner {
+	declineRate := s.valueDeclineRate(order)
+	for _, i := range s.Rng.Perm(len(candidates)) {
+		partner := candidates[i]
+		if partner == nil {
+			continue
+		}
+		partner.ShiftStats.Offers++
+		if s.Rng.Float64() < declineRate {
+			partner.ShiftStats.Declined++
+			continue
+		}
+		return partner
+	}
+	return nil
+}
+
+// updatePartnerMetrics adds a location update to the partner's shift stats:
+// the distance covered and, while available, the time spent idle
+func (s *Simulator) updatePartnerMetrics(partner *models.DeliveryPartner, newLocation models.Location, elapsed time.Duration) {
+	partner.ShiftStats.DistanceKm += s.calculateDistance(partner.CurrentLocation, newLocation)
+	s.updatePartnerExperience(partner)
+	if partner.Status == models.PartnerStatusAvailable && elapsed > 0 {
+		partner.ShiftStats.IdleMinutes += elapsed.Minutes()
+	}
+}
+
+// schedulePartnerShiftSummaries emits a summary for each partner whose shift
+// has run its configured length, and starts their next shift
+func (s *Simulator) schedulePartnerShiftSummaries() {
+	if s.Config.PartnerShiftLength <= 0 {
+		return
+	}
+	for _, partner := range s.DeliveryPartners {
+		if partner == nil {
+			continue
+		}
+		if partner.ShiftStats.ShiftStart.IsZero() {
+			partner.ShiftStats.ShiftStart = s.CurrentTime
+			continue
+		}
+		if s.CurrentTime.Sub(partner.ShiftStats.ShiftStart) < s.Config.PartnerShiftLength {
+			continue
+		}
+
+		summary := partner.ShiftStats
+		summary.PartnerID = partner.ID
+		summary.ShiftEnd = s.CurrentTime
+		summary.Experience = partner.Experience
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventPartnerShiftSummary,
+			Data: &summary,
+		})
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventPartnerPayout,
+			Data: s.shiftPayout(partner, summary),
+		})
+		partner.ShiftStats = models.PartnerShiftStats{ShiftStart: s.CurrentTime}
+		s.startShiftFromHome(partner)
+	}
+}
+
+// startShiftFromHome puts a partner who is between orders back at home for the
+// start of their next shift, with partner_placement "home"
+func (s *Simulator) startShiftFromHome(partner *models.DeliveryPartner) {
+	if s.Config.PartnerPlacement != models.PartnerPlacementHome || partner.Status != models.PartnerStatusAvailable {
+		return
+	}
+	partner.CurrentLocation = partner.HomeLocation
+}
+
+// recordFirstPickup notes how far from home the partner's first pickup of the
+// shift is, with partner_placement "home"
+func (s *Simulator) recordFirstPickup(partner *models.DeliveryPartner, order *models.Order) {
+	if s.Config.PartnerPlacement != models.PartnerPlacementHome || partner.ShiftStats.FirstPickupAssigned {
+		return
+	}
+	restaurant := s.pickupRestaurant(order)
+	if restaurant == nil {
+		return
+	}
+	partner.ShiftStats.HomeToFirstPickupKm = s.calculateDistance(partner.HomeLocation, restaurant.Location)
+	partner.ShiftStats.FirstPickupAssigned = true
+}
+
+func (s *Simulator) newPartnerShiftSummaryEvent(baseEvent BaseEvent, stats *models.PartnerShiftStats) PartnerShiftSummaryEvent {
+	event := PartnerShiftSummaryEvent{
+		BaseEvent:  baseEvent,
+		ShiftStart: stats.ShiftStart,
+		ShiftEnd:   stats.ShiftEnd,
+	}
+
+	include := func(field string) bool {
+		return len(s.Config.PartnerShiftSummaryFields) == 0 || contains(s.Config.PartnerShiftSummaryFields, field)
+	}
+	if include("offers") {
+		offers := int32(stats.Offers)
+		event.Offers = &offers
+	}
+	if include("declined") {
+		declined := int32(stats.Declined)
+		event.Declined = &declined
+	}
+	if include("acceptance_rate") {
+		rate := 1.0
+		if stats.Offers > 0 {
+			rate = float64(stats.Offers-stats.Declined) / float64(stats.Offers)
+		}
+		event.AcceptanceRate = &rate
+	}
+	if include("deliveries") {
+		deliveries := int32(stats.Deliveries)
+		event.Deliveries = &deliveries
+	}
+	if include("idle_minutes") {
+		idle := math.Round(stats.IdleMinutes*10) / 10
+		event.IdleMinutes = &idle
+	}
+	if include("experience") {
+		experience := math.Round(stats.Experience*1000) / 1000
+		event.Experience = &experience
+	}
+	if include("distance_km") {
+		distance := math.Round(s.Config.OutputDistance(stats.DistanceKm)*100) / 100
+		event.DistanceKm = &distance
+	}
+	if include("home_to_first_pickup_km") && stats.FirstPickupAssigned {
+		distance := math.Round(s.Config.OutputDistance(stats.HomeToFirstPickupKm)*100) / 100
+		event.HomeToFirstPickupKm = &distance
+	}
+	return event
+}
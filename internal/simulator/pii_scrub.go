@@ -0,0 +1,109 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+// addressTextFields are the street-level parts of a delivery address
+var addressTextFields = []string{"house_no", "flat", "address1", "address2", "postcode"}
+
+// scrubPII replaces the identifying fields listed in pii_scrub_fields in a
+// serialized event. Text is hashed, so the same value always scrubs to the
+// same token, or replaced with a random token with pii_scrub_mode
+// "randomize"; coordinates are rounded to pii_location_precision decimal
+// places. IDs are left alone so events still join across streams.
+func (s *Simulator) scrubPII(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var event map[string]interface{}
+	if err := decoder.Decode(&event); err != nil {
+		return nil, fmt.Errorf("failed to decode event for scrubbing: %w", err)
+	}
+
+	fields := s.Config.PIIScrubFields
+	if contains(fields, models.PIIFieldAddress) {
+		if address, ok := event["deliveryAddress"].(map[string]interface{}); ok {
+			for _, key := range addressTextFields {
+				address[key] = s.scrubText(address[key])
+			}
+			address["latitude"] = s.coarsenCoordinate(address["latitude"])
+			address["longitude"] = s.coarsenCoordinate(address["longitude"])
+		}
+	}
+	if contains(fields, models.PIIFieldDeliveryNote) {
+		scrubKey(event, "deliveryNote", s.scrubText)
+	}
+	if contains(fields, models.PIIFieldComment) {
+		scrubKey(event, "comment", s.scrubText)
+	}
+	if contains(fields, models.PIIFieldRestaurantName) {
+		scrubKey(event, "restaurantName", s.scrubText)
+	}
+	if contains(fields, models.PIIFieldLocation) {
+		s.coarsenLocations(event)
+	}
+	return json.Marshal(event)
+}
+
+// scrubKey replaces the value at key, if the event has one
+func scrubKey(event map[string]interface{}, key string, scrub func(interface{}) interface{}) {
+	if value, ok := event[key]; ok {
+		event[key] = scrub(value)
+	}
+}
+
+// scrubText hashes or randomizes a non-empty string value
+func (s *Simulator) scrubText(value interface{}) interface{} {
+	text, ok := value.(string)
+	if !ok || text == "" {
+		return value
+	}
+	if s.Config.PIIScrubMode == models.PIIScrubRandomize {
+		if s.piiRng == nil {
+			s.piiRng = rand.New(rand.NewSource(int64(s.Config.Seed)))
+		}
+		return fmt.Sprintf("%016x", s.piiRng.Uint64())
+	}
+	sum := sha256.Sum256([]byte(strconv.Itoa(s.Config.Seed) + ":" + text))
+	return hex.EncodeToString(sum[:8])
+}
+
+// coarsenCoordinate rounds a coordinate to pii_location_precision decimal places
+func (s *Simulator) coarsenCoordinate(value interface{}) interface{} {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	coordinate, err := number.Float64()
+	if err != nil {
+		return value
+	}
+	scale := math.Pow(10, float64(s.Config.PIILocationPrecision))
+	return math.Round(coordinate*scale) / scale
+}
+
+// coarsenLocations rounds every lat/lon pair in the event, however deeply nested
+func (s *Simulator) coarsenLocations(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if key == "lat" || key == "lon" {
+				v[key] = s.coarsenCoordinate(field)
+				continue
+			}
+			s.coarsenLocations(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			s.coarsenLocations(item)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"time"
+)
+
+// platformDown reports whether ordering is halted by a platform outage
+func (s *Simulator) platformDown() bool {
+	return s.outage != nil && s.CurrentTime.Before(s.outage.Until)
+}
+
+// updatePlatformOutage starts a platform-wide outage at outage_rate a day
+// and ends it once its time is up. Orders in flight carry on regardless; only
+// new orders fail, and during an app crash partners' location pings don't
+// reach the platform either.
+func (s *Simulator) updatePlatformOutage() {
+	if s.outage != nil && !s.outageEnded && !s.CurrentTime.Before(s.outage.Until) {
+		s.outageEnded = true
+		log.Printf("Platform outage %s ended: %d orders missed, %d placed again", s.outage.ID, s.outage.MissedOrders, s.outage.Retried)
+		ended := *s.outage
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventPlatformOutageEnded,
+			Data: &ended,
+		})
+	}
+	if s.Config.OutageRate <= 0 || s.platformDown() {
+		return
+	}
+	if s.Rng.Float64() >= s.Config.OutageRate*simulationTimeStep.Hours()/24 {
+		return
+	}
+
+	reason := models.OutageReasonPayments
+	if s.Rng.Float64() < 0.5 {
+		reason = models.OutageReasonAppCrash
+	}
+	duration := time.Duration(float64(s.Config.OutageDuration) * (0.5 + s.Rng.Float64()))
+	s.outage = &models.PlatformOutage{
+		ID:     generateID(),
+		Reason: reason,
+		Start:  s.CurrentTime,
+		Until:  s.CurrentTime.Add(duration),
+	}
+	s.outageEnded = false
+	log.Printf("Platform outage %s (%s) until %s", s.outage.ID, reason, s.outage.Until.Format(time.RFC3339))
+	started := *s.outage
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventPlatformOutageStarted,
+		Data: &started,
+	})
+}
+
+// deferOrderAttempt handles a customer trying to order while the platform is
+// down. With outage_catch_up_share of customers trying again within
+// outage_catch_up_window of the outage ending, and the rest giving up until
+// their next order, demand dips during the outage and spikes after it.
+func (s *Simulator) deferOrderAttempt(user *models.User) {
+	s.outage.MissedOrders++
+	next := s.generateNextOrderTime(user)
+	if s.Rng.Float64() < s.Config.OutageCatchUpShare {
+		s.outage.Retried++
+		next = s.outage.Until
+		if window := s.Config.OutageCatchUpWindow; window > 0 {
+			next = next.Add(time.Duration(s.Rng.Int63n(int64(window))))
+		}
+	}
+	s.EventQueue.Enqueue(&models.Event{
+		Time: next,
+		Type: models.EventPlaceOrder,
+		Data: user,
+	})
+}
+
+// outageCatchUpFactor raises order rates for outage_catch_up_window after an
+// outage, as customers who couldn't order come back: the missed demand,
+// scaled by outage_catch_up_share, spread over the window
+func (s *Simulator) outageCatchUpFactor() float64 {
+	window := s.Config.OutageCatchUpWindow
+	if s.outage == nil || window <= 0 || s.platformDown() || s.CurrentTime.Sub(s.outage.Until) >= window {
+		return 1
+	}
+	length := s.outage.Until.Sub(s.outage.Start)
+	return 1 + s.Config.OutageCatchUpShare*float64(length)/float64(window)
+}
+
+// pingLost reports whether an app outage keeps a partner's location ping from
+// reaching the platform
+func (s *Simulator) pingLost() bool {
+	return s.platformDown() && s.outage.Reason == models.OutageReasonAppCrash
+}
@@ -0,0 +1,45 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// recordPrepTime keeps the time the order took from the start of preparation
+// to ready, over the restaurant's last prep_time_learning_window orders
+func (s *Simulator) recordPrepTime(restaurant *models.Restaurant, order *models.Order) {
+	if s.Config.PrepTimeLearningRate <= 0 || order.PrepStartTime.IsZero() {
+		return
+	}
+	realized := s.CurrentTime.Sub(order.PrepStartTime).Minutes()
+	if realized <= 0 {
+		return
+	}
+
+	if s.prepTimeHistory == nil {
+		s.prepTimeHistory = make(map[string][]float64)
+	}
+	history := append(s.prepTimeHistory[restaurant.ID], realized)
+	if window := s.Config.PrepTimeLearningWindow; len(history) > window {
+		history = history[len(history)-window:]
+	}
+	s.prepTimeHistory[restaurant.ID] = history
+}
+
+// learnPrepTime moves the restaurant's average prep time, which its estimates
+// start from, a prep_time_learning_rate step toward the mean of its recent
+// realized prep times
+func (s *Simulator) learnPrepTime(restaurant *models.Restaurant) {
+	history := s.prepTimeHistory[restaurant.ID]
+	if s.Config.PrepTimeLearningRate <= 0 || len(history) == 0 {
+		return
+	}
+
+	var total float64
+	for _, minutes := range history {
+		total += minutes
+	}
+	realized := total / float64(len(history))
+	restaurant.AvgPrepTime += (realized - restaurant.AvgPrepTime) * s.Config.PrepTimeLearningRate
+	restaurant.AvgPrepTime = math.Max(restaurant.AvgPrepTime, restaurant.MinPrepTime)
+}
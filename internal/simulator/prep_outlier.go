@@ -0,0 +1,36 @@
+package simulator
+
+import "math"
+
+// prepOutlierMinFactor is the smallest slowdown that counts as an outlier
+const prepOutlierMinFactor = 1.5
+
+// prepOutlierMaxFactor is the largest slowdown when
+// prep_time_outlier_max_factor is unset
+const prepOutlierMaxFactor = 6.0
+
+// prepTimeOutlierFactor returns how much longer than usual an order takes to
+// prepare. noise is the order's ordinary variability draw in [-1, 1]; only
+// orders that were already running slow (noise > 0) can become outliers, so
+// the median prep time is unchanged and only the right tail grows. Outlier
+// slowdowns follow a Pareto distribution with shape prep_time_outlier_shape,
+// capped at prep_time_outlier_max_factor.
+func (s *Simulator) prepTimeOutlierFactor(noise float64) float64 {
+	rate := s.Config.PrepTimeOutlierRate
+	if rate <= 0 || noise <= 0 {
+		return 1
+	}
+	// half of orders are slow, so they become outliers at twice the overall rate
+	if s.Rng.Float64() >= math.Min(1, 2*rate) {
+		return 1
+	}
+	shape := s.Config.PrepTimeOutlierShape
+	if shape <= 0 {
+		shape = 1.5
+	}
+	maxFactor := s.Config.PrepTimeOutlierMaxFactor
+	if maxFactor < prepOutlierMinFactor {
+		maxFactor = prepOutlierMaxFactor
+	}
+	return math.Min(maxFactor, prepOutlierMinFactor*math.Pow(1-s.Rng.Float64(), -1/shape))
+}
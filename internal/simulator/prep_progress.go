@@ -0,0 +1,37 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"time"
+)
+
+// schedulePrepProgress queues progress updates at every prep progress interval
+// from the start of preparation until the kitchen expects the order to be
+// ready. Progress is reported against the estimate, since that is all a
+// tracking screen would know.
+func (s *Simulator) schedulePrepProgress(order *models.Order, estimatedPrepTime float64) {
+	interval := s.Config.PrepProgressInterval
+	if interval <= 0 {
+		return
+	}
+	estimatedReadyAt := order.PrepStartTime.Add(time.Duration(estimatedPrepTime * float64(time.Minute)))
+	for t := order.PrepStartTime.Add(interval); t.Before(estimatedReadyAt); t = t.Add(interval) {
+		s.EventQueue.Enqueue(&models.Event{
+			Time: t,
+			Type: models.EventPrepProgress,
+			Data: &models.PrepProgress{Order: order, EstimatedReadyAt: estimatedReadyAt},
+		})
+	}
+}
+
+// prepProgressPercent is the share of the estimated prep time that has
+// elapsed at t, capped below 100 until the order is actually ready
+func prepProgressPercent(progress *models.PrepProgress, t time.Time) float64 {
+	total := progress.EstimatedReadyAt.Sub(progress.Order.PrepStartTime)
+	if total <= 0 {
+		return 99
+	}
+	percent := float64(t.Sub(progress.Order.PrepStartTime)) / float64(total) * 100
+	return math.Round(math.Max(0, math.Min(99, percent)))
+}
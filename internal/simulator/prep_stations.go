@@ -0,0 +1,41 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// prepStations is how many orders the restaurant's kitchen can prepare at
+// once: prep_stations for a venue of the median size between min_capacity and
+// max_capacity, scaled by the venue's base capacity, and at least one
+func (s *Simulator) prepStations(restaurant *models.Restaurant) int {
+	lo, hi := float64(s.Config.MinCapacity), float64(s.Config.MaxCapacity)
+	if lo < 1 || hi < lo {
+		lo, hi = 10, 50
+	}
+	size := float64(restaurant.BaseCapacity)
+	if size <= 0 {
+		size = float64(restaurant.Capacity)
+	}
+	stations := math.Round(float64(s.Config.PrepStations) * size / math.Sqrt(lo*hi))
+	return int(math.Max(1, stations))
+}
+
+// kitchenLoadFactor is how much the orders already in the kitchen slow a new
+// one down. With prep_stations set, a new order starts straight away while a
+// station is free and otherwise queues, waiting a share of a prep time for
+// each order ahead of it beyond the stations. Without it, prep slows by up to
+// half at full capacity.
+func (s *Simulator) kitchenLoadFactor(restaurant *models.Restaurant) float64 {
+	inKitchen := len(restaurant.CurrentOrders)
+	if s.Config.PrepStations <= 0 {
+		currentLoad := float64(inKitchen) / float64(restaurant.Capacity)
+		return 1 + (currentLoad * 0.5) // Up to 50% increase for full capacity
+	}
+
+	stations := s.prepStations(restaurant)
+	if inKitchen < stations {
+		return 1
+	}
+	return 1 + float64(inKitchen-stations+1)/float64(stations)
+}
@@ -0,0 +1,50 @@
+package simulator
+
+import (
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// firstSlowdown is how many orders must already be in the kitchen before a
+// new one is slowed down
+func firstSlowdown(s *Simulator, restaurant *models.Restaurant) int {
+	restaurant.CurrentOrders = nil
+	for len(restaurant.CurrentOrders) < restaurant.Capacity {
+		if s.kitchenLoadFactor(restaurant) > 1 {
+			return len(restaurant.CurrentOrders)
+		}
+		restaurant.CurrentOrders = append(restaurant.CurrentOrders, models.Order{})
+	}
+	return -1
+}
+
+func TestMoreStationsSlowDownLater(t *testing.T) {
+	// a median-sized venue, so prep_stations applies unscaled
+	newKitchen := func(stations int) (*Simulator, *models.Restaurant) {
+		s := NewSimulator(&models.Config{PrepStations: stations, MinCapacity: 10, MaxCapacity: 40})
+		return s, &models.Restaurant{ID: "r", Capacity: 20, BaseCapacity: 20}
+	}
+	one, oneKitchen := newKitchen(1)
+	four, fourKitchen := newKitchen(4)
+
+	if got := firstSlowdown(one, oneKitchen); got != 1 {
+		t.Errorf("1-station kitchen first slows with %d orders in, want 1", got)
+	}
+	if got := firstSlowdown(four, fourKitchen); got != 4 {
+		t.Errorf("4-station kitchen first slows with %d orders in, want 4", got)
+	}
+
+	for load := 1; load <= 10; load++ {
+		oneKitchen.CurrentOrders = make([]models.Order, load)
+		fourKitchen.CurrentOrders = make([]models.Order, load)
+		if f1, f4 := one.kitchenLoadFactor(oneKitchen), four.kitchenLoadFactor(fourKitchen); f4 >= f1 {
+			t.Errorf("with %d orders in, 4-station factor %.2f not below 1-station %.2f", load, f4, f1)
+		}
+	}
+
+	// a venue twice the median size gets twice the stations
+	if got := four.prepStations(&models.Restaurant{BaseCapacity: 40}); got != 8 {
+		t.Errorf("stations for a double-sized venue = %d, want 8", got)
+	}
+}
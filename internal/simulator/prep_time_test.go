@@ -0,0 +1,69 @@
+package simulator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// prepTimeSimulator has a quiet restaurant whose menu items all take itemPrep
+// minutes and have average complexity
+func prepTimeSimulator(itemPrep float64) (*Simulator, *models.Restaurant, []string) {
+	s := NewSimulator(&models.Config{Seed: 5, ItemPrepTimeWeight: 1})
+	restaurant := &models.Restaurant{ID: "r1", AvgPrepTime: 15, MinPrepTime: 1, Capacity: 20}
+	var items []string
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("item-%d", i)
+		s.MenuItems[id] = &models.MenuItem{ID: id, RestaurantID: restaurant.ID, PrepTime: itemPrep, PrepComplexity: 1}
+		items = append(items, id)
+	}
+	return s, restaurant, items
+}
+
+func TestItemPrepTimeOneVersusFiveItems(t *testing.T) {
+	s, _, items := prepTimeSimulator(10)
+	if got := s.itemPrepTime(items[:1]); got != 10 {
+		t.Errorf("one 10-minute item takes %v minutes, want 10", got)
+	}
+	// the slowest item, plus 30% of each of the other four
+	if got, want := s.itemPrepTime(items), 10+4*10*itemPrepOverlap; got != want {
+		t.Errorf("five 10-minute items take %v minutes, want %v", got, want)
+	}
+}
+
+func TestEstimatePrepTimeGrowsWithItems(t *testing.T) {
+	s, restaurant, items := prepTimeSimulator(10)
+	one := s.estimatePrepTime(restaurant, items[:1])
+	five := s.estimatePrepTime(restaurant, items)
+
+	// estimates carry ±5% noise around 10 and 22 minutes
+	if one < 9.5 || one > 10.5 {
+		t.Errorf("one item estimated at %.1f minutes, want about 10", one)
+	}
+	if five < 20.9 || five > 23.1 {
+		t.Errorf("five items estimated at %.1f minutes, want about 22", five)
+	}
+}
+
+func TestPrepTimeOutlierFactorIsCapped(t *testing.T) {
+	s := NewSimulator(&models.Config{
+		Seed:                     42,
+		PrepTimeOutlierRate:      0.5,
+		PrepTimeOutlierShape:     0.3, // a very heavy tail
+		PrepTimeOutlierMaxFactor: 4,
+	})
+	capped := 0
+	for i := 0; i < 10000; i++ {
+		factor := s.prepTimeOutlierFactor(0.5)
+		if factor > 4 {
+			t.Fatalf("outlier factor %.2f above prep_time_outlier_max_factor 4", factor)
+		}
+		if factor == 4 {
+			capped++
+		}
+	}
+	if capped == 0 {
+		t.Error("no outlier reached the cap, want the heavy tail to hit it")
+	}
+}
@@ -0,0 +1,45 @@
+package simulator
+
+import (
+	"log"
+	"time"
+)
+
+// queueMonitor tracks the event queue depth over a run so a simulation that is
+// falling behind, or enqueueing events in a runaway loop, is visible in the log
+type queueMonitor struct {
+	lastLoggedAt time.Time
+	peak         int
+	peakAt       time.Time
+	overLimit    bool
+}
+
+// checkQueueDepth logs the queue depth every queue_depth_log_interval of
+// simulated time and warns when it crosses queue_depth_warning_threshold
+func (s *Simulator) checkQueueDepth() {
+	depth := s.EventQueue.Len()
+	monitor := &s.queueMonitor
+	if depth > monitor.peak {
+		monitor.peak = depth
+		monitor.peakAt = s.CurrentTime
+	}
+
+	if interval := s.Config.QueueDepthLogInterval; interval > 0 && s.CurrentTime.Sub(monitor.lastLoggedAt) >= interval {
+		log.Printf("Event queue depth at %s: %d", s.CurrentTime.Format(time.RFC3339), depth)
+		monitor.lastLoggedAt = s.CurrentTime
+	}
+
+	threshold := s.Config.QueueDepthWarningThreshold
+	if threshold <= 0 {
+		return
+	}
+	switch {
+	case depth > threshold && !monitor.overLimit:
+		log.Printf("Warning: event queue depth %d exceeds %d at %s; the simulation may be falling behind or enqueueing events in a loop",
+			depth, threshold, s.CurrentTime.Format(time.RFC3339))
+		monitor.overLimit = true
+	case depth <= threshold && monitor.overLimit:
+		log.Printf("Event queue depth back under %d at %s: %d", threshold, s.CurrentTime.Format(time.RFC3339), depth)
+		monitor.overLimit = false
+	}
+}
@@ -0,0 +1,174 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"sort"
+)
+
+// ratingCorrectionThreshold is the smallest change in a rating worth emitting
+const ratingCorrectionThreshold = 0.005
+
+// ratingBaseline is an entity's rating and rating count from before its first
+// simulated review, standing in for the reviews it had before the run started
+type ratingBaseline struct {
+	rating, count float64
+}
+
+type reviewRatingTotals struct {
+	sum   float64
+	count int
+}
+
+// recordRatingBaseline remembers an entity's rating before its first simulated review
+func (s *Simulator) recordRatingBaseline(id string, rating, count float64) {
+	if s.Config.RatingRecomputeInterval <= 0 {
+		return
+	}
+	if s.ratingBaselines == nil {
+		s.ratingBaselines = make(map[string]ratingBaseline)
+	}
+	if _, ok := s.ratingBaselines[id]; !ok {
+		s.ratingBaselines[id] = ratingBaseline{rating: rating, count: count}
+	}
+}
+
+// recordRestaurantRatingBaseline records the restaurant's long-run rating,
+// its historical window when reputation windows are in use
+func (s *Simulator) recordRestaurantRatingBaseline(restaurant *models.Restaurant) {
+	rating := restaurant.Rating
+	if s.Config.ReputationRecoveryEnabled {
+		initRatingWindows(restaurant)
+		rating = restaurant.RatingWindows.Historical
+	}
+	s.recordRatingBaseline(restaurant.ID, rating, restaurant.TotalRatings)
+}
+
+// scheduleRatingRecomputation rebuilds every reviewed restaurant's and
+// partner's rating from its reviews once per rating_recompute_interval.
+// Incremental updates weight recent reviews and drift from the true mean over
+// long runs; the rebuild replaces the long-run rating with the mean of the
+// entity's non-ignored reviews and its pre-run baseline. Restaurants using
+// reputation windows keep their recent window, so the displayed rating is
+// still blended from both.
+func (s *Simulator) scheduleRatingRecomputation() {
+	interval := s.Config.RatingRecomputeInterval
+	if interval <= 0 {
+		return
+	}
+	if s.lastRatingRecomputeAt.IsZero() {
+		s.lastRatingRecomputeAt = s.CurrentTime
+		return
+	}
+	if s.CurrentTime.Sub(s.lastRatingRecomputeAt) < interval {
+		return
+	}
+	s.lastRatingRecomputeAt = s.CurrentTime
+
+	restaurants := make(map[string]*reviewRatingTotals)
+	partners := make(map[string]*reviewRatingTotals)
+	for i := range s.Reviews {
+		review := &s.Reviews[i]
+		if review.IsIgnored {
+			continue
+		}
+		if review.FoodRating > 0 {
+			addReviewRating(restaurants, review.RestaurantID, review.FoodRating)
+		}
+		if review.DeliveryPartnerID != "" && review.DeliveryRating > 0 {
+			addReviewRating(partners, review.DeliveryPartnerID, review.DeliveryRating)
+		}
+	}
+
+	var corrected int
+	var totalCorrection, largestCorrection float64
+	record := func(recomputation *models.RatingRecomputation) {
+		correction := math.Abs(recomputation.Rating - recomputation.PreviousRating)
+		if correction < ratingCorrectionThreshold {
+			return
+		}
+		corrected++
+		totalCorrection += correction
+		largestCorrection = math.Max(largestCorrection, correction)
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventRatingRecomputed,
+			Data: recomputation,
+		})
+	}
+
+	for _, id := range sortedRatingIDs(restaurants) {
+		restaurant := s.Restaurants[id]
+		if restaurant == nil {
+			continue
+		}
+		mean, count := s.recomputedRating(id, restaurants[id])
+		previous := restaurant.Rating
+		if s.Config.ReputationRecoveryEnabled {
+			initRatingWindows(restaurant)
+			restaurant.RatingWindows.Historical = mean
+			restaurant.Rating = s.blendRatingWindows(&restaurant.RatingWindows)
+		} else {
+			restaurant.Rating = mean
+		}
+		restaurant.TotalRatings = count
+		record(s.ratingRecomputation(models.SummaryEntityRestaurants, id, previous, restaurant.Rating, restaurants[id].count))
+	}
+
+	for _, id := range sortedRatingIDs(partners) {
+		partner := s.getDeliveryPartner(id)
+		if partner == nil {
+			continue
+		}
+		mean, count := s.recomputedRating(id, partners[id])
+		previous := partner.Rating
+		partner.Rating = mean
+		partner.TotalRatings = count
+		record(s.ratingRecomputation(models.SummaryEntityPartners, id, previous, partner.Rating, partners[id].count))
+	}
+
+	if corrected > 0 {
+		log.Printf("Recomputed ratings for %d restaurants and %d partners: %d corrected, mean correction %.3f, largest %.3f",
+			len(restaurants), len(partners), corrected, totalCorrection/float64(corrected), largestCorrection)
+	}
+}
+
+// recomputedRating is the mean of the entity's baseline and review ratings,
+// and the rating count it covers
+func (s *Simulator) recomputedRating(id string, totals *reviewRatingTotals) (float64, float64) {
+	baseline := s.ratingBaselines[id]
+	count := baseline.count + float64(totals.count)
+	mean := (baseline.rating*baseline.count + totals.sum) / count
+	return math.Max(1, math.Min(5, mean)), count
+}
+
+func (s *Simulator) ratingRecomputation(entityType, id string, previous, rating float64, reviews int) *models.RatingRecomputation {
+	return &models.RatingRecomputation{
+		EntityType:     entityType,
+		EntityID:       id,
+		PreviousRating: previous,
+		Rating:         rating,
+		Reviews:        reviews,
+		RecomputedAt:   s.CurrentTime,
+	}
+}
+
+func addReviewRating(totals map[string]*reviewRatingTotals, id string, rating float64) {
+	t, ok := totals[id]
+	if !ok {
+		t = &reviewRatingTotals{}
+		totals[id] = t
+	}
+	t.sum += rating
+	t.count++
+}
+
+func sortedRatingIDs(totals map[string]*reviewRatingTotals) []string {
+	ids := make([]string, 0, len(totals))
+	for id := range totals {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
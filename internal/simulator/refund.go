@@ -0,0 +1,56 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"time"
+)
+
+// refundCancelledOrder returns what the customer paid for a cancelled order.
+// Cash orders are never charged, so there is nothing to refund.
+func (s *Simulator) refundCancelledOrder(order *models.Order) {
+	if order.PaymentMethod == models.PaymentCash {
+		return
+	}
+	s.initiateRefund(order, order.TotalAmount+order.Tip, models.RefundReasonCancellation, s.CurrentTime)
+}
+
+// initiateRefund queues a refund-initiated event now and a refund-completed
+// event once the refund settles with the customer's payment provider. Card
+// settlement times vary by a quarter either side of the configured delay.
+func (s *Simulator) initiateRefund(order *models.Order, amount float64, reason string, at time.Time) {
+	if amount <= 0 {
+		return
+	}
+	method := order.PaymentMethod
+	if method == models.PaymentCash {
+		// cash can't be handed back, so it is refunded as wallet credit
+		method = models.PaymentWallet
+	}
+	delay := s.Config.RefundSettlementDelay(method)
+	if delay > 0 {
+		delay = time.Duration(float64(delay) * (0.75 + s.Rng.Float64()*0.5))
+	}
+
+	refund := &models.Refund{
+		ID:            generateID(),
+		OrderID:       order.ID,
+		CustomerID:    order.CustomerID,
+		RestaurantID:  order.RestaurantID,
+		PaymentMethod: method,
+		Amount:        math.Round(amount*100) / 100,
+		Reason:        reason,
+		InitiatedAt:   at,
+		SettlesAt:     at.Add(delay),
+	}
+	s.EventQueue.Enqueue(&models.Event{
+		Time: refund.InitiatedAt,
+		Type: models.EventRefundInitiated,
+		Data: refund,
+	})
+	s.EventQueue.Enqueue(&models.Event{
+		Time: refund.SettlesAt,
+		Type: models.EventRefundCompleted,
+		Data: refund,
+	})
+}
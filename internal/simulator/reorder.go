@@ -0,0 +1,49 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// reorderWindow is how many of a user's most recent orders they pick a repeat from
+const reorderWindow = 5
+
+// reorder decides whether the user repeats one of their recent orders rather
+// than browsing, and if so returns it. Only single-restaurant orders from a
+// restaurant that is still taking orders, and whose items are all still on
+// the menu, can be repeated.
+func (s *Simulator) reorder(user *models.User) *models.Order {
+	if s.Config.ReorderRate <= 0 || s.Rng.Float64() >= s.Config.ReorderProbability(s.userSegment(user)) {
+		return nil
+	}
+
+	orders := s.OrdersByUser[user.ID]
+	if len(orders) > reorderWindow {
+		orders = orders[len(orders)-reorderWindow:]
+	}
+	var candidates []*models.Order
+	for i := range orders {
+		if s.canReorder(&orders[i]) {
+			candidates = append(candidates, &orders[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[s.Rng.Intn(len(candidates))]
+}
+
+func (s *Simulator) canReorder(order *models.Order) bool {
+	if len(order.RestaurantIDs) > 0 || len(order.Items) == 0 {
+		return false
+	}
+	restaurant := s.getRestaurant(order.RestaurantID)
+	if restaurant == nil || restaurantOffline(restaurant) {
+		return false
+	}
+	for _, id := range order.Items {
+		if s.getMenuItem(id) == nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// updateRestaurantReputation folds a review's food rating into the restaurant's
+// recent and historical rating windows and recomputes the displayed rating.
+// Negative reviews the restaurant replies to have part of their impact absorbed.
+func (s *Simulator) updateRestaurantReputation(restaurant *models.Restaurant, review *models.Review) {
+	initRatingWindows(restaurant)
+	windows := &restaurant.RatingWindows
+
+	rating := review.FoodRating
+	if rating < 3 && s.Config.ReviewResponseRate > 0 && s.Rng.Float64() < s.Config.ReviewResponseRate {
+		review.RestaurantReplied = true
+		rating += (windows.Recent - rating) * s.Config.ReviewResponseDamping
+	}
+
+	windows.Recent = updateRating(windows.Recent, rating, s.Config.RestaurantRatingAlpha)
+	windows.Historical = (windows.Historical*restaurant.TotalRatings + rating) / (restaurant.TotalRatings + 1)
+	restaurant.Rating = s.blendRatingWindows(windows)
+}
+
+// penaliseRestaurantReputation treats a restaurant cancelling an accepted
+// order like a one-star review in the recent window
+func (s *Simulator) penaliseRestaurantReputation(restaurant *models.Restaurant) {
+	initRatingWindows(restaurant)
+	windows := &restaurant.RatingWindows
+	windows.Recent = updateRating(windows.Recent, 1, s.Config.RestaurantRatingAlpha)
+	restaurant.Rating = s.blendRatingWindows(windows)
+}
+
+// recoverRestaurantReputation pulls the recent rating back towards the
+// historical one so a run of bad reviews fades over time
+func (s *Simulator) recoverRestaurantReputation(restaurant *models.Restaurant) {
+	initRatingWindows(restaurant)
+	windows := &restaurant.RatingWindows
+
+	stepDays := simulationTimeStep.Hours() / 24
+	recovered := 1 - math.Exp(-s.Config.ReputationRecoveryRate*stepDays)
+	windows.Recent += (windows.Historical - windows.Recent) * recovered
+	restaurant.Rating = s.blendRatingWindows(windows)
+}
+
+func (s *Simulator) blendRatingWindows(windows *models.RatingWindows) float64 {
+	weight := math.Max(0, math.Min(1, s.Config.ReputationRecentWeight))
+	return weight*windows.Recent + (1-weight)*windows.Historical
+}
+
+// initRatingWindows seeds both windows from the current rating the first time they are used
+func initRatingWindows(restaurant *models.Restaurant) {
+	if restaurant.RatingWindows.Recent == 0 && restaurant.RatingWindows.Historical == 0 {
+		restaurant.RatingWindows.Recent = restaurant.Rating
+		restaurant.RatingWindows.Historical = restaurant.Rating
+	}
+}
@@ -0,0 +1,55 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"time"
+)
+
+// overwhelmedCancelFactor raises a restaurant's cancel rate while it is at capacity
+const overwhelmedCancelFactor = 2.0
+
+// maybeCancelByRestaurant decides, when a restaurant accepts an order, whether
+// it will go on to cancel it. If so the cancellation is queued part way into
+// preparation, before the order would be ready, and true is returned.
+func (s *Simulator) maybeCancelByRestaurant(order *models.Order, restaurant *models.Restaurant, readyTime time.Time) bool {
+	restaurant.AcceptedOrders++
+
+	probability := restaurant.CancelRate
+	overwhelmed := len(restaurant.CurrentOrders) >= restaurant.Capacity
+	if overwhelmed {
+		probability *= overwhelmedCancelFactor
+	}
+	if s.Rng.Float64() >= probability {
+		return false
+	}
+
+	order.CancelledBy = models.CancelledByRestaurant
+	order.CancellationReason = models.CancelReasonItemUnavailable
+	if overwhelmed {
+		order.CancellationReason = models.CancelReasonOverwhelmed
+	}
+
+	// cancel somewhere between a fifth and two thirds of the way through prep
+	prepTime := readyTime.Sub(s.CurrentTime)
+	delay := time.Duration(float64(prepTime) * (0.2 + s.Rng.Float64()*0.45))
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime.Add(delay),
+		Type: models.EventCancelOrder,
+		Data: order,
+	})
+	return true
+}
+
+// recordRestaurantCancellation counts a restaurant-initiated cancellation
+// against the restaurant's reliability and reputation
+func (s *Simulator) recordRestaurantCancellation(restaurant *models.Restaurant) {
+	restaurant.CancelledOrders++
+	s.penaliseRestaurantReputation(restaurant)
+}
+
+// calculateReliabilityScore is the share of accepted orders a restaurant
+// fulfils, smoothed so a single cancellation doesn't sink a new restaurant
+func calculateReliabilityScore(restaurant *models.Restaurant) float64 {
+	const priorOrders = 20.0
+	return 1 - float64(restaurant.CancelledOrders)/(float64(restaurant.AcceptedOrders)+priorOrders)
+}
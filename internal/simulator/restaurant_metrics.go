@@ -0,0 +1,150 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"time"
+)
+
+// prepTimeTolerance is how far actual prep time can stray from the estimate
+// before an order counts as early or late
+const prepTimeTolerance = 5.0 // minutes
+
+// orderCounts tallies orders by how they ended. Restaurant metrics windows and
+// daily summaries both build on it, so they agree on what counts as placed,
+// completed and cancelled.
+type orderCounts struct {
+	placed, completed, cancelled int
+}
+
+// completionRate is the share of finished orders that were delivered rather
+// than cancelled, 0 if none finished
+func (c orderCounts) completionRate() float64 {
+	if finished := c.completed + c.cancelled; finished > 0 {
+		return float64(c.completed) / float64(finished)
+	}
+	return 0
+}
+
+// restaurantWindow accumulates a restaurant's order activity since the last
+// metrics snapshot
+type restaurantWindow struct {
+	orderCounts
+	pickedUp, late, early int
+	prepTime              float64
+}
+
+// restaurantWindowFor returns the running totals for a restaurant, or nil if
+// restaurant metrics are disabled
+func (s *Simulator) restaurantWindowFor(id string) *restaurantWindow {
+	if id == "" || s.Config.RestaurantMetricsInterval <= 0 {
+		return nil
+	}
+	if s.restaurantWindows == nil {
+		s.restaurantWindows = make(map[string]*restaurantWindow)
+	}
+	w, ok := s.restaurantWindows[id]
+	if !ok {
+		w = &restaurantWindow{}
+		s.restaurantWindows[id] = w
+	}
+	return w
+}
+
+func (s *Simulator) recordMetricsOrderPlaced(order *models.Order) {
+	if w := s.restaurantWindowFor(order.RestaurantID); w != nil {
+		w.placed++
+	}
+}
+
+// recordMetricsPickup compares the order's prep time with the restaurant's
+// current estimate
+func (s *Simulator) recordMetricsPickup(order *models.Order) {
+	w := s.restaurantWindowFor(order.RestaurantID)
+	restaurant := s.getRestaurant(order.RestaurantID)
+	if w == nil || restaurant == nil {
+		return
+	}
+	prepTime := order.PickupTime.Sub(order.PrepStartTime).Minutes()
+	if prepTime <= 0 {
+		return
+	}
+	w.prepTime += prepTime
+	w.pickedUp++
+	switch {
+	case prepTime > restaurant.AvgPrepTime+prepTimeTolerance:
+		w.late++
+	case prepTime < restaurant.AvgPrepTime-prepTimeTolerance:
+		w.early++
+	}
+}
+
+func (s *Simulator) recordMetricsDelivery(order *models.Order) {
+	if w := s.restaurantWindowFor(order.RestaurantID); w != nil {
+		w.completed++
+	}
+}
+
+func (s *Simulator) recordMetricsCancellation(order *models.Order) {
+	if w := s.restaurantWindowFor(order.RestaurantID); w != nil {
+		w.cancelled++
+	}
+}
+
+// scheduleRestaurantMetrics queues a metrics snapshot for every restaurant
+// each time the configured metrics interval elapses
+func (s *Simulator) scheduleRestaurantMetrics() {
+	interval := s.Config.RestaurantMetricsInterval
+	if interval <= 0 {
+		return
+	}
+	if s.lastRestaurantMetricsAt.IsZero() {
+		s.lastRestaurantMetricsAt = s.CurrentTime
+		s.restaurantWindows = nil
+		return
+	}
+	if s.CurrentTime.Sub(s.lastRestaurantMetricsAt) < interval {
+		return
+	}
+
+	for _, metrics := range s.calculateRestaurantOrderMetrics(s.lastRestaurantMetricsAt, s.CurrentTime) {
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventRestaurantMetrics,
+			Data: metrics,
+		})
+	}
+	s.lastRestaurantMetricsAt = s.CurrentTime
+	s.restaurantWindows = nil
+}
+
+// calculateRestaurantOrderMetrics summarises each restaurant's order activity
+// between start and end against its current prep time estimate
+func (s *Simulator) calculateRestaurantOrderMetrics(start, end time.Time) map[string]*models.RestaurantMetrics {
+	metrics := make(map[string]*models.RestaurantMetrics, len(s.Restaurants))
+	for id, restaurant := range s.Restaurants {
+		m := &models.RestaurantMetrics{
+			RestaurantID:      id,
+			WindowStart:       start,
+			WindowEnd:         end,
+			EstimatedPrepTime: restaurant.AvgPrepTime,
+			PickupEfficiency:  restaurant.PickupEfficiency,
+		}
+		metrics[id] = m
+
+		w, ok := s.restaurantWindows[id]
+		if !ok {
+			continue
+		}
+		m.OrdersPlaced = w.placed
+		m.OrdersCompleted = w.completed
+		m.OrdersCancelled = w.cancelled
+		m.LateOrders = w.late
+		m.EarlyOrders = w.early
+		m.CompletionRate = w.completionRate()
+		if w.pickedUp > 0 {
+			m.ActualPrepTime = math.Round(w.prepTime/float64(w.pickedUp)*100) / 100
+		}
+	}
+	return metrics
+}
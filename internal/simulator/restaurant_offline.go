@@ -0,0 +1,103 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+// restaurantOffline reports whether the restaurant has stopped taking orders
+func restaurantOffline(restaurant *models.Restaurant) bool {
+	return restaurant.Offline == models.RestaurantOfflineEnabled
+}
+
+// updateRestaurantAvailability takes restaurants offline at random for a
+// while and brings them back once their time is up. Restaurants are more
+// likely to stop taking orders when they are well over capacity or the
+// weather is extreme. An offline restaurant is skipped when customers choose
+// where to order from, but finishes the orders it already has.
+func (s *Simulator) updateRestaurantAvailability() {
+	if s.Config.RestaurantOfflineRate <= 0 {
+		return
+	}
+	ids := make([]string, 0, len(s.Restaurants))
+	for id := range s.Restaurants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	stepHours := simulationTimeStep.Hours()
+	for _, id := range ids {
+		restaurant := s.Restaurants[id]
+		if restaurantOffline(restaurant) {
+			if !s.CurrentTime.Before(restaurant.OfflineUntil) {
+				s.setRestaurantOnline(restaurant)
+			}
+			continue
+		}
+
+		factor, reason := s.offlinePressure(restaurant)
+		if s.Rng.Float64() < s.Config.RestaurantOfflineRate*stepHours*factor {
+			s.setRestaurantOffline(restaurant, reason)
+		}
+	}
+}
+
+// offlinePressure is how much more likely than usual the restaurant is to go
+// offline right now, and the most likely reason
+func (s *Simulator) offlinePressure(restaurant *models.Restaurant) (float64, string) {
+	factor, reason := 1.0, models.OfflineReasonUnplanned
+	if hour := s.localTime(s.CurrentTime).Hour(); hour >= 20 {
+		factor, reason = 1.5, models.OfflineReasonClosingEarly
+	}
+	if extremeWeather(s.getCurrentWeather(restaurant.Location)) {
+		factor, reason = 4, models.OfflineReasonWeather
+	}
+	if restaurant.Capacity > 0 {
+		load := float64(len(restaurant.CurrentOrders)) / float64(restaurant.Capacity)
+		if load > 1 {
+			if overload := math.Min(load*load, 5); overload > factor {
+				factor, reason = overload, models.OfflineReasonOverwhelmed
+			}
+		}
+	}
+	return factor, reason
+}
+
+// extremeWeather reports weather bad enough for a kitchen to consider closing
+func extremeWeather(weather models.WeatherCondition) bool {
+	return weather.Condition == models.WeatherSnow ||
+		weather.Precipitation >= 8 ||
+		weather.WindSpeed >= 60 ||
+		weather.Temperature >= 35
+}
+
+func (s *Simulator) setRestaurantOffline(restaurant *models.Restaurant, reason string) {
+	duration := time.Duration(float64(s.Config.RestaurantOfflineDuration) * (0.5 + s.Rng.Float64()))
+	restaurant.Offline = models.RestaurantOfflineEnabled
+	restaurant.OfflineUntil = s.CurrentTime.Add(duration)
+	log.Printf("Restaurant %s went offline (%s) until %s", restaurant.ID, reason, restaurant.OfflineUntil.Format(time.RFC3339))
+	s.enqueueRestaurantHoursChange(restaurant, models.RestaurantStatusClosed, reason)
+}
+
+func (s *Simulator) setRestaurantOnline(restaurant *models.Restaurant) {
+	restaurant.Offline = models.RestaurantOfflineDisabled
+	restaurant.OfflineUntil = s.CurrentTime
+	s.enqueueRestaurantHoursChange(restaurant, models.RestaurantStatusOpen, "")
+}
+
+func (s *Simulator) enqueueRestaurantHoursChange(restaurant *models.Restaurant, status, reason string) {
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventRestaurantHours,
+		Data: &models.RestaurantHoursChange{
+			RestaurantID: restaurant.ID,
+			Status:       status,
+			Reason:       reason,
+			Until:        restaurant.OfflineUntil,
+			OpenOrders:   len(restaurant.CurrentOrders),
+		},
+	})
+}
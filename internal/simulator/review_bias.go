@@ -0,0 +1,124 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"sort"
+)
+
+// ratingTotals sums the ratings one user segment, or one cuisine's
+// restaurants, got over the run
+type ratingTotals struct {
+	food, foodCount         float64
+	delivery, deliveryCount float64
+}
+
+// reviewRatingBias is the rating bias of the segment the order's customer is in
+func (s *Simulator) reviewRatingBias(order *models.Order) models.ReviewRatingBias {
+	user := s.getUser(order.CustomerID)
+	if user == nil {
+		return models.DefaultReviewRatingBias
+	}
+	return s.Config.ReviewRatingBiasFor(s.userSegment(user))
+}
+
+// applyFoodRatingBias shifts a sampled food rating by the customer's segment
+// food_base, any viral spike the order was placed in and the restaurant's
+// cuisine offset, keeping it between 1 and 5
+func (s *Simulator) applyFoodRatingBias(order *models.Order, foodRating float64) float64 {
+	shift := s.reviewRatingBias(order).FoodBase + viralRatingShift(order)
+	if restaurant := s.getRestaurant(order.RestaurantID); restaurant != nil {
+		shift += s.Config.CuisineRatingOffset(restaurant.Cuisines)
+	}
+	if shift == 0 {
+		return foodRating
+	}
+	return math.Max(1, math.Min(5, foodRating+shift))
+}
+
+// recordSegmentRatings adds a review's ratings to its customer's segment
+// totals for the end-of-run calibration report
+func (s *Simulator) recordSegmentRatings(review *models.Review) {
+	user := s.getUser(review.CustomerID)
+	if user == nil {
+		return
+	}
+	if s.segmentRatings == nil {
+		s.segmentRatings = make(map[string]*ratingTotals)
+	}
+	addRatingTotals(s.segmentRatings, s.userSegment(user), review)
+}
+
+// recordCuisineRatings adds a review's ratings to the totals of each cuisine
+// its restaurant serves
+func (s *Simulator) recordCuisineRatings(review *models.Review) {
+	restaurant := s.getRestaurant(review.RestaurantID)
+	if restaurant == nil {
+		return
+	}
+	if s.cuisineRatings == nil {
+		s.cuisineRatings = make(map[string]*ratingTotals)
+	}
+	for _, cuisine := range restaurant.Cuisines {
+		addRatingTotals(s.cuisineRatings, cuisine, review)
+	}
+}
+
+func addRatingTotals(totals map[string]*ratingTotals, key string, review *models.Review) {
+	ratings, ok := totals[key]
+	if !ok {
+		ratings = &ratingTotals{}
+		totals[key] = ratings
+	}
+	if review.FoodRating > 0 {
+		ratings.food += review.FoodRating
+		ratings.foodCount++
+	}
+	if review.DeliveryRating > 0 {
+		ratings.delivery += review.DeliveryRating
+		ratings.deliveryCount++
+	}
+}
+
+// logSegmentRatings reports the average ratings each user segment gave, to
+// check review_rating_bias against a target rating distribution
+func (s *Simulator) logSegmentRatings() {
+	for _, segment := range []string{models.UserSegmentOccasional, models.UserSegmentRegular, models.UserSegmentFrequent} {
+		ratings, ok := s.segmentRatings[segment]
+		if !ok {
+			continue
+		}
+		var food, delivery float64
+		if ratings.foodCount > 0 {
+			food = ratings.food / ratings.foodCount
+		}
+		if ratings.deliveryCount > 0 {
+			delivery = ratings.delivery / ratings.deliveryCount
+		}
+		log.Printf("Ratings from %s users: average food rating %.2f over %d reviews, average delivery rating %.2f over %d reviews",
+			segment, food, int(ratings.foodCount), delivery, int(ratings.deliveryCount))
+	}
+}
+
+// logCuisineRatings reports the average food rating each cuisine's
+// restaurants got next to its configured offset, to check
+// cuisine_rating_offsets show through in the ratings
+func (s *Simulator) logCuisineRatings() {
+	if len(s.Config.CuisineRatingOffsets) == 0 {
+		return
+	}
+	cuisines := make([]string, 0, len(s.cuisineRatings))
+	for cuisine := range s.cuisineRatings {
+		cuisines = append(cuisines, cuisine)
+	}
+	sort.Strings(cuisines)
+	for _, cuisine := range cuisines {
+		ratings := s.cuisineRatings[cuisine]
+		if ratings.foodCount == 0 {
+			continue
+		}
+		log.Printf("Ratings for %s restaurants: average food rating %.2f over %d reviews (offset %+.2f)",
+			cuisine, ratings.food/ratings.foodCount, int(ratings.foodCount), s.Config.CuisineRatingOffset([]string{cuisine}))
+	}
+}
@@ -0,0 +1,54 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"time"
+)
+
+// scheduleReviewEdit decides whether the customer will come back and edit a
+// review they just posted, and if so queues the edit within the edit window
+func (s *Simulator) scheduleReviewEdit(review models.Review) {
+	if s.Config.ReviewEditWindow <= 0 || s.Rng.Float64() >= s.Config.ReviewEditProbability {
+		return
+	}
+
+	// at least a minute after posting and never past the end of the window
+	delay := s.Config.ReviewEditWindow
+	if span := s.Config.ReviewEditWindow - time.Minute; span > 0 {
+		delay = time.Minute + time.Duration(s.Rng.Int63n(int64(span)+1))
+	}
+	s.EventQueue.Enqueue(&models.Event{
+		Time: review.CreatedAt.Add(delay),
+		Type: models.EventEditReview,
+		Data: &review,
+	})
+}
+
+// editReview revises a posted review's comment and its food rating, if it has
+// one. Customers whose complaint got a reply from the restaurant tend to soften
+// their review; otherwise the edit is as likely to go either way. The
+// restaurant's ratings are left as they are so each review is only counted once.
+// The edit is stamped with the time it was made, at.
+func (s *Simulator) editReview(review *models.Review, at time.Time) {
+	change := 0.5 + s.Rng.Float64()*1.5
+	if !review.RestaurantReplied && s.Rng.Float64() < 0.5 {
+		change = -change
+	}
+	if review.FoodRating > 0 {
+		review.FoodRating = math.Round(math.Max(1, math.Min(5, review.FoodRating+change))*10) / 10
+		review.OverallRating = overallRating(review.FoodRating, review.DeliveryRating)
+	}
+
+	if reviewData, ok := s.pickReviewData(change > 0); ok {
+		review.Comment = "Update: " + reviewData.Comment + " " + review.Comment
+	}
+	review.UpdatedAt = at
+
+	for i := range s.Reviews {
+		if s.Reviews[i].ID == review.ID {
+			s.Reviews[i] = *review
+			break
+		}
+	}
+}
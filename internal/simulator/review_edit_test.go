@@ -0,0 +1,32 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestReviewEditsFallWithinTheWindow(t *testing.T) {
+	window := 3 * time.Minute
+	s := NewSimulator(&models.Config{Seed: 3, ReviewEditWindow: window, ReviewEditProbability: 1})
+	posted := time.Date(2024, 3, 1, 19, 0, 0, 0, time.UTC)
+	for i := 0; i < 500; i++ {
+		s.scheduleReviewEdit(models.Review{ID: "r", CreatedAt: posted})
+		event := s.EventQueue.Dequeue()
+		if delay := event.Time.Sub(posted); delay < time.Minute || delay > window {
+			t.Fatalf("edit scheduled %s after posting, want between 1m and %s", delay, window)
+		}
+	}
+}
+
+func TestReviewEditIsStampedWithItsEventTime(t *testing.T) {
+	s := NewSimulator(&models.Config{Seed: 3})
+	s.CurrentTime = time.Date(2024, 3, 1, 19, 10, 0, 0, time.UTC)
+	at := s.CurrentTime.Add(-7 * time.Minute)
+	review := &models.Review{ID: "r", FoodRating: 3, DeliveryRating: 3}
+	s.editReview(review, at)
+	if !review.UpdatedAt.Equal(at) {
+		t.Errorf("UpdatedAt %s, want the edit event's time %s", review.UpdatedAt, at)
+	}
+}
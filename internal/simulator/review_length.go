@@ -0,0 +1,37 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"strings"
+)
+
+// composeReviewComment builds a review comment from a base comment, a remark
+// on the delivery and any remarks the order triggers
+func (s *Simulator) composeReviewComment(order *models.Order, reviewData models.ReviewData, deliveryRating float64) string {
+	comment := s.adjustCommentWithDeliveryFeedback(reviewData.Comment, deliveryRating)
+	if triggered := s.triggeredReviewPhrases(order); len(triggered) > 0 {
+		comment = joinReviewText(append([]string{comment}, triggered...)...)
+	}
+	return comment
+}
+
+// ensureReviewLength swaps a comment shorter than min_review_words for one
+// built from another base comment of the same sentiment. It gives up after a
+// few attempts and keeps the longest comment seen.
+func (s *Simulator) ensureReviewLength(order *models.Order, comment string, liked bool, deliveryRating float64) string {
+	longest := comment
+	for attempts := 0; attempts < 10 && countWords(longest) < s.Config.MinReviewWords; attempts++ {
+		reviewData, ok := s.pickReviewData(liked)
+		if !ok {
+			break
+		}
+		if candidate := s.composeReviewComment(order, reviewData, deliveryRating); countWords(candidate) > countWords(longest) {
+			longest = candidate
+		}
+	}
+	return longest
+}
+
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
@@ -0,0 +1,61 @@
+package simulator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// reviewSimulator has one delivered order to review, from a loaded config
+// with overrides applied
+func reviewSimulator(t *testing.T, overrides map[string]interface{}) (*Simulator, *models.Order) {
+	t.Helper()
+	s := NewSimulator(testConfig(t, overrides))
+	s.Restaurants["r1"] = &models.Restaurant{ID: "r1", Name: "Test Kitchen", Rating: 4, Cuisines: []string{"italian"}}
+	s.Users = []*models.User{{ID: "u1", OrderFrequency: s.Config.OrderFrequency}}
+	placed := s.CurrentTime
+	order := &models.Order{
+		ID:                    "o1",
+		CustomerID:            "u1",
+		RestaurantID:          "r1",
+		TotalAmount:           25,
+		OrderPlacedAt:         placed,
+		EstimatedDeliveryTime: placed.Add(35 * time.Minute),
+		ActualDeliveryTime:    placed.Add(35 * time.Minute),
+		Status:                models.OrderStatusDelivered,
+	}
+	return s, order
+}
+
+func TestRatingOnlyReviewFraction(t *testing.T) {
+	s, order := reviewSimulator(t, map[string]interface{}{"rating_only_review_rate": 0.3})
+
+	const n = 5000
+	ratingsOnly := 0
+	for i := 0; i < n; i++ {
+		review := s.createReview(order)
+		if review.Comment == "" {
+			ratingsOnly++
+		}
+	}
+	if got := float64(ratingsOnly) / n; math.Abs(got-0.3) > 0.03 {
+		t.Errorf("%.3f of reviews had no comment, want about 0.3", got)
+	}
+}
+
+func TestMinReviewWords(t *testing.T) {
+	s, order := reviewSimulator(t, map[string]interface{}{"min_review_words": 8})
+
+	short := 0
+	for i := 0; i < 500; i++ {
+		if countWords(s.createReview(order).Comment) < 8 {
+			short++
+		}
+	}
+	// a few may give up after ten redraws, but nearly all should be long enough
+	if short > 10 {
+		t.Errorf("%d of 500 comments were under 8 words", short)
+	}
+}
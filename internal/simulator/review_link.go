@@ -0,0 +1,78 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"sort"
+)
+
+// trackUnreviewed remembers a delivered order so it can be written as an
+// unreviewed link at the end of the run if no review arrives
+func (s *Simulator) trackUnreviewed(order *models.Order) {
+	if !s.Config.ReviewLinksUnreviewed {
+		return
+	}
+	if s.unreviewedOrders == nil {
+		s.unreviewedOrders = make(map[string]*models.OrderReviewLink)
+	}
+	s.unreviewedOrders[order.ID] = &models.OrderReviewLink{
+		OrderID:      order.ID,
+		CustomerID:   order.CustomerID,
+		RestaurantID: order.RestaurantID,
+		DeliveredAt:  order.ActualDeliveryTime,
+	}
+}
+
+// linkReview queues the link between an order and the review just written
+// for it
+func (s *Simulator) linkReview(order *models.Order, review *models.Review) {
+	delete(s.unreviewedOrders, order.ID)
+	if !s.Config.ReviewLinks {
+		return
+	}
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventOrderReviewLink,
+		Data: &models.OrderReviewLink{
+			OrderID:      order.ID,
+			CustomerID:   order.CustomerID,
+			RestaurantID: order.RestaurantID,
+			ReviewID:     review.ID,
+			DeliveredAt:  order.ActualDeliveryTime,
+			ReviewedAt:   review.CreatedAt,
+		},
+	})
+}
+
+// writeUnreviewedLinks writes a link with no review for every delivered order
+// still unreviewed at the end of the run, so consumers don't have to anti-join
+// orders against reviews to find them. It returns how many were written.
+func (s *Simulator) writeUnreviewedLinks(output OutputDestination) int {
+	ids := make([]string, 0, len(s.unreviewedOrders))
+	for id := range s.unreviewedOrders {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	written := 0
+	for _, id := range ids {
+		msg, err := s.serializeEvent(models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventOrderReviewLink,
+			Data: s.unreviewedOrders[id],
+		})
+		if err != nil {
+			log.Printf("Error serializing unreviewed order link: %v", err)
+			continue
+		}
+		if err := output.WriteMessage(msg.Topic, msg.Message); err != nil {
+			log.Printf("Failed to write message: %v", err)
+			continue
+		}
+		written++
+	}
+	if len(ids) > 0 {
+		log.Printf("Wrote %d links for orders that were never reviewed", written)
+	}
+	return written
+}
@@ -0,0 +1,41 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// omitReviewRatings leaves out the food or the delivery rating for the share of
+// reviews configured by review_food_only_rate and review_delivery_only_rate,
+// as customers who only rate one side of an order do. The omitted rating is 0
+// and the overall rating is the one that was given.
+func (s *Simulator) omitReviewRatings(review *models.Review) {
+	roll := s.Rng.Float64()
+	switch {
+	case roll < s.Config.ReviewFoodOnlyRate:
+		review.DeliveryRating = 0
+	case roll < s.Config.ReviewFoodOnlyRate+s.Config.ReviewDeliveryOnlyRate:
+		review.FoodRating = 0
+	}
+	review.OverallRating = overallRating(review.FoodRating, review.DeliveryRating)
+}
+
+// overallRating is the mean of the food and delivery ratings the customer gave
+func overallRating(food, delivery float64) float64 {
+	switch {
+	case food == 0:
+		return delivery
+	case delivery == 0:
+		return food
+	}
+	return (food + delivery) / 2
+}
+
+// outputReviewRating is a review rating on the output scale, nil when the
+// customer didn't give it
+func (s *Simulator) outputReviewRating(rating float64) *float64 {
+	if rating == 0 {
+		return nil
+	}
+	output := s.Config.OutputRating(rating)
+	return &output
+}
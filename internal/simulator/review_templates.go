@@ -0,0 +1,72 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"strings"
+	"time"
+)
+
+// reviewTemplates is the loaded review template set, or the built-in one if
+// none was loaded
+func (s *Simulator) reviewTemplates() *models.ReviewTemplates {
+	if s.Config.ReviewTemplates == nil {
+		s.Config.ReviewTemplates = models.DefaultReviewTemplates()
+	}
+	return s.Config.ReviewTemplates
+}
+
+// reviewComments is the pool of base review comments: the template file's if
+// it has any, otherwise the loaded review data
+func (s *Simulator) reviewComments() []models.ReviewData {
+	if comments := s.reviewTemplates().Comments; len(comments) > 0 {
+		return comments
+	}
+	return s.Config.ReviewData
+}
+
+// deliveryFeedbackPhrase picks a delivery remark for the band the rating falls in
+func (s *Simulator) deliveryFeedbackPhrase(deliveryRating float64) string {
+	for _, band := range s.reviewTemplates().DeliveryFeedback {
+		if deliveryRating >= band.MinRating {
+			return band.Phrases[s.Rng.Intn(len(band.Phrases))]
+		}
+	}
+	return ""
+}
+
+// triggeredReviewPhrases picks the remarks for each trigger the order matches
+func (s *Simulator) triggeredReviewPhrases(order *models.Order) []string {
+	var phrases []string
+	for _, trigger := range s.reviewTemplates().Triggers {
+		if !s.reviewTriggerMatches(trigger.Condition, order) || s.Rng.Float64() >= trigger.Probability {
+			continue
+		}
+		phrases = append(phrases, trigger.Phrases[s.Rng.Intn(len(trigger.Phrases))])
+	}
+	return phrases
+}
+
+func (s *Simulator) reviewTriggerMatches(condition string, order *models.Order) bool {
+	switch condition {
+	case models.ReviewTriggerLate:
+		return order.ActualDeliveryTime.After(order.EstimatedDeliveryTime.Add(10 * time.Minute))
+	case models.ReviewTriggerEarly:
+		return !order.ActualDeliveryTime.IsZero() && order.ActualDeliveryTime.Before(order.EstimatedDeliveryTime)
+	case models.ReviewTriggerExpensive:
+		return s.calculatePriceSatisfaction(order) > 0.5
+	case models.ReviewTriggerGoodValue:
+		return s.calculatePriceSatisfaction(order) < -0.5
+	}
+	return false
+}
+
+// joinReviewText joins review fragments into one comment
+func joinReviewText(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, " ")
+}
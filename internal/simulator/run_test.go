@@ -0,0 +1,17 @@
+package simulator
+
+import "testing"
+
+// TestRunConcurrentWorkers drives the whole worker pool; run it with -race
+// to check that processing and serialising events share state safely.
+func TestRunConcurrentWorkers(t *testing.T) {
+	config := testConfig(t, map[string]interface{}{
+		"start_date": "2024-03-01T11:00:00Z",
+		"end_date":   "2024-03-01T14:00:00Z",
+		"max_events": 0,
+		"workers":    8,
+	})
+	if len(runRecorded(t, config)) == 0 {
+		t.Fatal("the run wrote no events")
+	}
+}
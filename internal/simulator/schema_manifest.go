@@ -0,0 +1,124 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SchemaVersion is the version of the event output format as a whole. It only
+// changes for breaking changes, when an emitted event loses a field or a field
+// changes type or meaning; fields added to an event just bump its entry in
+// eventVersions. Version 2 made review ratings nullable.
+const SchemaVersion = 2
+
+// eventVersions holds the shape version of each event type that has changed
+// since it was introduced; event types not listed are at version 1
+var eventVersions = map[string]int32{
+	models.EventPlaceOrder:             9, // deliveryInstruction, deliveryNote, restaurantIds, enrichment fields, upsell fields, homepageFeatured, isReorder, tax and service fee, viral
+	models.EventDeliverOrder:           4, // deliveryInstruction, partnerExperienceTier, etaErrorSeconds, doorDwellSeconds
+	models.EventUpdateRestaurantStatus: 5, // accepted_payment_methods, bad_actor, reliability, base_capacity, avg_prep_time
+	models.EventCancelOrder:            2, // cancelledBy, reason
+	models.EventWeatherObservation:     2, // region
+	models.EventUpdatePartnerLocation:  2, // cashOnHand
+	models.EventGenerateReview:         3, // nullable foodRating and deliveryRating, itemRatings
+	models.EventEditReview:             3, // nullable foodRating and deliveryRating, itemRatings
+	models.EventPartnerShiftSummary:    2, // homeToFirstPickupKm
+	models.EventAssignDeliveryPartner:  2, // estimatedDeliveryTime, etaMinMinutes, etaMaxMinutes
+	models.EventPickUpOrder:            2, // etaMinMinutes, etaMaxMinutes
+	models.EventDailySummary:           2, // partial
+}
+
+// emittedEventTypes are the event types written to an output topic
+var emittedEventTypes = []string{
+	models.EventPlaceOrder,
+	models.EventPrepareOrder,
+	models.EventPrepProgress,
+	models.EventOrderReady,
+	models.EventAssignDeliveryPartner,
+	models.EventPickUpOrder,
+	models.EventUpdatePartnerLocation,
+	models.EventOrderInTransit,
+	models.EventCheckDeliveryStatus,
+	models.EventDeliverOrder,
+	models.EventCancelOrder,
+	models.EventUpdateUserBehaviour,
+	models.EventUpdateRestaurantStatus,
+	models.EventGenerateReview,
+	models.EventEditReview,
+	models.EventRestaurantMetrics,
+	models.EventAbandonCart,
+	models.EventPartnerShiftSummary,
+	models.EventWeatherObservation,
+	models.EventFeaturedDishStarted,
+	models.EventFeaturedDishEnded,
+	models.EventSupportTicket,
+	models.EventDailySummary,
+	models.EventPartnerGhosted,
+	models.EventPartnerDeactivated,
+	models.EventRefundInitiated,
+	models.EventRefundCompleted,
+	models.EventPartnerPayout,
+	models.EventNotification,
+	models.EventRatingRecomputed,
+	models.EventRestaurantHours,
+	models.EventPartnerCashDrop,
+	models.EventHomepageFeatureStarted,
+	models.EventHomepageFeatureEnded,
+	models.EventOrderAcceptance,
+	models.EventPlatformOutageStarted,
+	models.EventPlatformOutageEnded,
+	models.EventPartnerDualApp,
+	models.EventOrderSettlement,
+	models.EventRestaurantViral,
+	models.EventOrderReviewLink,
+}
+
+// EventVersion returns the shape version of an event type
+func EventVersion(eventType string) int32 {
+	if version, ok := eventVersions[eventType]; ok {
+		return version
+	}
+	return 1
+}
+
+type schemaManifestEvent struct {
+	EventType    string `json:"eventType"`
+	EventVersion int32  `json:"eventVersion"`
+}
+
+type schemaManifest struct {
+	SchemaVersion int32                 `json:"schemaVersion"`
+	Events        []schemaManifestEvent `json:"events"`
+}
+
+// writeSchemaManifest writes the schema and event versions next to file
+// output so consumers can check them before reading the events
+func (s *Simulator) writeSchemaManifest() error {
+	if s.Config.KafkaEnabled || s.Config.OutputPath == "" || (s.Config.OutputDestination != "" && s.Config.OutputDestination != "local") {
+		return nil
+	}
+
+	manifest := schemaManifest{SchemaVersion: SchemaVersion}
+	for _, eventType := range emittedEventTypes {
+		manifest.Events = append(manifest.Events, schemaManifestEvent{EventType: eventType, EventVersion: EventVersion(eventType)})
+	}
+	sort.Slice(manifest.Events, func(i, j int) bool {
+		return manifest.Events[i].EventType < manifest.Events[j].EventType
+	})
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schema manifest: %w", err)
+	}
+	if err := os.MkdirAll(s.Config.OutputPath, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Config.OutputPath, "schema_manifest.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write schema manifest: %w", err)
+	}
+	return nil
+}
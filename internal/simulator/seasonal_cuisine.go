@@ -0,0 +1,36 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// getCurrentSeason is the season in the city at the current simulated time
+func (s *Simulator) getCurrentSeason() string {
+	return models.SeasonAt(s.localTime(s.CurrentTime), s.Config.CityLat < 0)
+}
+
+// seasonalCuisineModifier is how much more or less appealing a cuisine is
+// this season, scaled by seasonal_cuisine_strength
+func (s *Simulator) seasonalCuisineModifier(cuisine string) float64 {
+	strength := s.Config.SeasonalCuisineStrength
+	if strength <= 0 {
+		return 1
+	}
+	modifier := s.Config.SeasonalCuisineModifier(s.getCurrentSeason(), cuisine)
+	return math.Max(0, 1+(modifier-1)*strength)
+}
+
+// seasonalCuisineMultiplier scales a restaurant's appeal by the season: its
+// most in-season cuisine lifts it, and it is only held back if all of its
+// cuisines are out of season
+func (s *Simulator) seasonalCuisineMultiplier(restaurant *models.Restaurant) float64 {
+	if len(restaurant.Cuisines) == 0 {
+		return 1
+	}
+	multiplier := 0.0
+	for _, cuisine := range restaurant.Cuisines {
+		multiplier = math.Max(multiplier, s.seasonalCuisineModifier(cuisine))
+	}
+	return multiplier
+}
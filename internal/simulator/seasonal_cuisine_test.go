@@ -0,0 +1,53 @@
+package simulator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestSeasonalCuisineWinterVersusSummer(t *testing.T) {
+	s := NewSimulator(&models.Config{CityLat: 53.0, SeasonalCuisineStrength: 1})
+	indian := &models.Restaurant{ID: "curry-house", Cuisines: []string{"Indian"}}
+	greek := &models.Restaurant{ID: "taverna", Cuisines: []string{"Greek"}}
+	pizza := &models.Restaurant{ID: "pizzeria", Cuisines: []string{"Pizza"}}
+
+	winter := time.Date(2024, 1, 15, 19, 0, 0, 0, time.UTC)
+	summer := time.Date(2024, 7, 15, 19, 0, 0, 0, time.UTC)
+	multipliers := func(at time.Time) (float64, float64, float64) {
+		s.CurrentTime = at
+		return s.seasonalCuisineMultiplier(indian), s.seasonalCuisineMultiplier(greek), s.seasonalCuisineMultiplier(pizza)
+	}
+
+	winterIndian, winterGreek, winterPizza := multipliers(winter)
+	summerIndian, summerGreek, summerPizza := multipliers(summer)
+	if winterIndian != 1.25 || summerIndian != 0.85 {
+		t.Errorf("indian: winter %.2f, summer %.2f; want 1.25 and 0.85", winterIndian, summerIndian)
+	}
+	if winterGreek != 0.9 || summerGreek != 1.2 {
+		t.Errorf("greek: winter %.2f, summer %.2f; want 0.9 and 1.2", winterGreek, summerGreek)
+	}
+	if winterPizza != 1 || summerPizza != 1 {
+		t.Errorf("pizza should be unaffected by the season, got %.2f and %.2f", winterPizza, summerPizza)
+	}
+
+	// in the southern hemisphere January is summer
+	s.Config.CityLat = -33.9
+	if southIndian, _, _ := multipliers(winter); southIndian != 0.85 {
+		t.Errorf("indian in a southern January = %.2f, want the summer 0.85", southIndian)
+	}
+}
+
+func TestSeasonalCuisineStrength(t *testing.T) {
+	s := NewSimulator(&models.Config{SeasonalCuisineStrength: 0.5})
+	s.CurrentTime = time.Date(2024, 1, 15, 19, 0, 0, 0, time.UTC)
+	if got := s.seasonalCuisineModifier("indian"); math.Abs(got-1.125) > 1e-9 {
+		t.Errorf("indian in winter at half strength = %v, want 1.125", got)
+	}
+	s.Config.SeasonalCuisineStrength = 0
+	if got := s.seasonalCuisineModifier("indian"); got != 1 {
+		t.Errorf("indian with seasonal shifts off = %v, want 1", got)
+	}
+}
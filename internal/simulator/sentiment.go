@@ -0,0 +1,69 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// calculatePriceSatisfaction compares what an order cost with what the customer
+// usually spends. It returns a value in [-1, 1]: positive for orders pricier
+// than usual (higher expectations), negative for cheaper ones.
+func (s *Simulator) calculatePriceSatisfaction(order *models.Order) float64 {
+	reference := 0.0
+	count := 0
+	for _, past := range s.OrdersByUser[order.CustomerID] {
+		if past.ID != order.ID && past.TotalAmount > 0 {
+			reference += past.TotalAmount
+			count++
+		}
+	}
+	if count > 0 {
+		reference /= float64(count)
+	} else {
+		reference = (s.Config.SmallOrderThreshold + s.Config.FreeDeliveryThreshold) / 2
+	}
+	if reference <= 0 || order.TotalAmount <= 0 {
+		return 0
+	}
+
+	return math.Max(-1, math.Min(1, math.Log2(order.TotalAmount/reference)))
+}
+
+// adjustRatingForPrice shifts a food rating for price expectations: expensive
+// orders that were merely okay rate worse, cheap orders that went well rate
+// better. The largest shift is review_price_sentiment_strength stars, scaled
+// by the customer segment's price_influence.
+func (s *Simulator) adjustRatingForPrice(order *models.Order, foodRating, deliveryRating float64) float64 {
+	expectation := s.calculatePriceSatisfaction(order)
+	experience := (foodRating + deliveryRating) / 2
+	strength := s.Config.ReviewPriceSentimentStrength * s.reviewRatingBias(order).PriceInfluence
+
+	var adjustment float64
+	switch {
+	case expectation > 0 && experience < 4.5:
+		// paid a premium for an unremarkable experience
+		adjustment = -strength * expectation
+	case expectation > 0:
+		adjustment = -strength * expectation * 0.2
+	case expectation < 0 && experience >= 3.5:
+		// good value for money
+		adjustment = -strength * expectation
+	}
+
+	return math.Max(1, math.Min(5, foodRating+adjustment))
+}
+
+// pickReviewData picks a random review template with the given sentiment
+func (s *Simulator) pickReviewData(liked bool) (models.ReviewData, bool) {
+	comments := s.reviewComments()
+	if len(comments) == 0 {
+		return models.ReviewData{}, false
+	}
+	for attempts := 0; attempts < 10; attempts++ {
+		reviewData := comments[s.Rng.Intn(len(comments))]
+		if reviewData.Liked == liked {
+			return reviewData, true
+		}
+	}
+	return models.ReviewData{}, false
+}
@@ -0,0 +1,46 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+// settleOrder splits a delivered order's total and tip between the parties
+// paid out of it and queues the settlement. The platform's net is whatever is
+// left once everyone else is paid, so rounding never stops the split
+// reconciling to the cent. Nothing is queued unless settlement_events is on.
+func (s *Simulator) settleOrder(order *models.Order, partner *models.DeliveryPartner) {
+	if !s.Config.SettlementEvents {
+		return
+	}
+	subtotal := roundCents(s.calculateSubtotal(order.Items))
+	commission := roundCents(subtotal * s.Config.RestaurantCommissionRate)
+	settlement := &models.OrderSettlement{
+		OrderID:          order.ID,
+		CustomerID:       order.CustomerID,
+		RestaurantID:     order.RestaurantID,
+		PartnerID:        partner.ID,
+		Subtotal:         subtotal,
+		Discount:         order.DiscountAmount,
+		Tax:              order.TaxAmount,
+		ServiceFee:       order.ServiceFee,
+		DeliveryFee:      roundCents(order.DeliveryCost),
+		Tip:              roundCents(order.Tip),
+		TotalAmount:      order.TotalAmount,
+		CommissionRate:   s.Config.RestaurantCommissionRate,
+		Commission:       commission,
+		RestaurantPayout: roundCents(subtotal - commission),
+		PartnerPay:       roundCents(s.partnerDeliveryPay(order)),
+	}
+	settlement.PlatformNet = roundCents(settlement.TotalAmount - settlement.RestaurantPayout - settlement.PartnerPay - settlement.Tax)
+
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventOrderSettlement,
+		Data: settlement,
+	})
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
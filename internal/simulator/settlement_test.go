@@ -0,0 +1,79 @@
+package simulator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestSettlementReconcilesToTotalPlusTip(t *testing.T) {
+	tests := []struct {
+		name       string
+		commission float64
+		payModel   string
+		discount   float64
+		tip        float64
+	}{
+		{"per delivery", 0.3, models.PayModelPerDelivery, 0, 2.5},
+		{"odd commission with discount", 0.173, models.PayModelPerDelivery, 4.07, 1.33},
+		{"hourly pay", 0.15, models.PayModelHourly, 0, 0},
+		{"no commission", 0, models.PayModelHybrid, 2, 3.01},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSimulator(&models.Config{
+				SettlementEvents:         true,
+				RestaurantCommissionRate: tt.commission,
+				PartnerPayModel:          tt.payModel,
+				PartnerPerDeliveryPay:    3.5,
+				PartnerPerKmPay:          0.83,
+			})
+			s.Restaurants["r"] = &models.Restaurant{ID: "r", Location: models.Location{Lat: 53.48, Lon: -2.24}}
+			prices := map[string]float64{"a": 9.99, "b": 4.15, "c": 12.37}
+			for id, price := range prices {
+				s.MenuItems[id] = &models.MenuItem{ID: id, RestaurantID: "r", Price: price}
+			}
+			order := &models.Order{
+				ID:             "o",
+				RestaurantID:   "r",
+				Items:          []string{"a", "b", "c", "a"},
+				Address:        models.Address{Latitude: 53.46, Longitude: -2.21},
+				DiscountAmount: tt.discount,
+				TaxAmount:      2.91,
+				ServiceFee:     1.83,
+				DeliveryCost:   3.49,
+				Tip:            tt.tip,
+			}
+			order.TotalAmount = roundCents(s.calculateSubtotal(order.Items) - order.DiscountAmount + order.TaxAmount + order.ServiceFee + order.DeliveryCost)
+
+			s.settleOrder(order, &models.DeliveryPartner{ID: "p"})
+			event := s.EventQueue.Dequeue()
+			if event == nil || event.Type != models.EventOrderSettlement {
+				t.Fatalf("queued event = %+v, want an order settlement", event)
+			}
+			st := event.Data.(*models.OrderSettlement)
+
+			paidOut := st.RestaurantPayout + st.PartnerPay + st.Tip + st.Tax + st.PlatformNet
+			if math.Abs(paidOut-(order.TotalAmount+order.Tip)) > 1e-9 {
+				t.Errorf("payout %.2f + partner %.2f + tip %.2f + tax %.2f + platform %.2f = %.4f, want total plus tip %.2f",
+					st.RestaurantPayout, st.PartnerPay, st.Tip, st.Tax, st.PlatformNet, paidOut, order.TotalAmount+order.Tip)
+			}
+			platform := st.Commission + st.ServiceFee + st.DeliveryFee - st.PartnerPay - st.Discount
+			if math.Abs(platform-st.PlatformNet) > 0.005 {
+				t.Errorf("platform net %.2f, want commission plus fees less partner pay and discount, %.2f", st.PlatformNet, platform)
+			}
+			if tt.payModel == models.PayModelHourly && st.PartnerPay != 0 {
+				t.Errorf("partner pay %.2f under hourly pay, want 0", st.PartnerPay)
+			}
+		})
+	}
+}
+
+func TestNoSettlementUnlessEnabled(t *testing.T) {
+	s := NewSimulator(&models.Config{RestaurantCommissionRate: 0.25})
+	s.settleOrder(&models.Order{ID: "o"}, &models.DeliveryPartner{ID: "p"})
+	if event := s.EventQueue.Dequeue(); event != nil {
+		t.Errorf("queued %+v with settlement_events off, want nothing", event)
+	}
+}
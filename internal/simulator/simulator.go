@@ -6,7 +6,6 @@ import (
 	"github.com/chrisdamba/foodatasim/internal/factories"
 	"github.com/chrisdamba/foodatasim/internal/models"
 	"github.com/chrisdamba/foodatasim/internal/output"
-	"github.com/jaswdr/faker"
 	"github.com/schollz/progressbar/v3"
 	"io"
 	"log"
@@ -17,6 +16,18 @@ import (
 	"time"
 )
 
+// simulationTimeStep is how far simulated time advances on each tick of Run
+const simulationTimeStep = 10 * time.Minute
+
+// Simulator owns the simulation state and drives the event loop.
+//
+// Concurrency model: Run dispatches due events to a pool of workers, but all
+// simulation state (users, partners, restaurants, orders, reviews, the RNG and
+// CurrentTime) is guarded by a single mutex. Workers hold it while an event is
+// processed and serialised and release it before the message is written to the
+// output, so only I/O runs in parallel. The main loop holds it while running a
+// time step. Code that reads or mutates state from outside the event loop must
+// go through WithStateLock.
 type Simulator struct {
 	Config                      *models.Config
 	Users                       []*models.User
@@ -31,6 +42,43 @@ type Simulator struct {
 	CurrentTime                 time.Time
 	Rng                         *rand.Rand
 	EventQueue                  *models.EventQueue
+	Weather                     models.WeatherCondition
+	RegionWeather               []models.WeatherCondition // One per configured weather region
+
+	stateMu                 sync.Mutex
+	lastRestaurantMetricsAt time.Time
+	restaurantWindows       map[string]*restaurantWindow
+	distances               *distanceReport
+
+	lastWeatherObservationAt time.Time
+	queueMonitor             queueMonitor
+	assignmentStats          assignmentStats
+	daily                    dailySummaries
+	notified                 map[string]bool // Notifications already sent, by order ID and type
+	location                 *time.Location
+
+	lastRatingRecomputeAt time.Time
+	ratingBaselines       map[string]ratingBaseline
+	homepageScheduled     map[int]bool               // homepage_features entries already started
+	reportedLocations     map[string]models.Location // Last partner position emitted, by partner ID
+	lastPingAt            map[string]time.Time       // When each partner's last location ping was emitted, with partner_status_pings
+	rejectedRestaurants   map[string]string          // Restaurant that just auto-rejected each user's order, until they reorder
+	outage                *models.PlatformOutage     // Current or most recent platform outage
+	outageEnded           bool
+	etaAccuracy           map[string]*etaAccuracy // Delivery estimate error by partner experience tier
+	prepTimeHistory       map[string][]float64    // Recent realized prep times in minutes, by restaurant ID
+	segmentRatings        map[string]*ratingTotals
+	cuisineRatings        map[string]*ratingTotals
+	piiRng                *rand.Rand        // Draws random tokens for pii_scrub_mode "randomize", apart from the simulation's own stream
+	growth                *factories.Stream // Generates users and partners joining mid-run, apart from the initial entity groups
+
+	unreviewedOrders map[string]*models.OrderReviewLink // Delivered orders with no review yet, with review_links_unreviewed
+
+	throttle *throttledOutput  // Set when topic_rate_limits wraps the output
+	output   OutputDestination // Used in place of the configured destination when set
+
+	writtenStatus map[string]int       // Furthest lifecycle status written per order ID
+	droppedOrders map[string]time.Time // When each finished order still in writtenStatus was removed from Orders
 }
 
 func NewSimulator(config *models.Config) *Simulator {
@@ -39,14 +87,32 @@ func NewSimulator(config *models.Config) *Simulator {
 		CurrentTime:      config.StartDate,
 		Restaurants:      make(map[string]*models.Restaurant),
 		MenuItems:        make(map[string]*models.MenuItem),
-		Rng:              rand.New(rand.NewSource(time.Now().UnixNano())),
-		Users:            make([]*models.User, config.InitialUsers),
+		Rng:              rand.New(rand.NewSource(runtimeSeed(config))),
 		DeliveryPartners: make([]*models.DeliveryPartner, config.InitialPartners),
 		EventQueue:       models.NewEventQueue(),
+		growth:           factories.NewStream(config.EntitySeedValue(), "growth"),
 	}
+	sim.calibrateDemand()
+	sim.Users = make([]*models.User, config.InitialUsers)
 	return sim
 }
 
+// runtimeSeed seeds the RNG behind simulation behaviour: the configured seed,
+// or the clock when none is set
+func runtimeSeed(config *models.Config) int64 {
+	if config.Seed != 0 {
+		return int64(config.Seed)
+	}
+	return time.Now().UnixNano()
+}
+
+// WithStateLock runs fn while holding the simulation state lock.
+func (s *Simulator) WithStateLock(fn func()) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	fn()
+}
+
 func (s *Simulator) initializeData() error {
 	userFactory := &factories.UserFactory{}
 	restaurantFactory := &factories.RestaurantFactory{}
@@ -69,15 +135,19 @@ func (s *Simulator) initializeData() error {
 	menuItemBatch := make([]*models.MenuItem, 0, batchSize)
 	deliveryPartnerBatch := make([]*models.DeliveryPartner, 0, batchSize)
 
+	// each entity group gets its own reproducible stream, independent of the runtime RNG
+	entitySeed := s.Config.EntitySeedValue()
+
 	// initialise users
 	log.Printf("Generating %d initial users...", s.Config.InitialUsers)
+	factories.Seed(entitySeed, "users")
 	for i := 0; i < s.Config.InitialUsers; i++ {
 		user := userFactory.CreateUser(s.Config)
 		s.Users[i] = user
 		userBatch = append(userBatch, user)
 
 		// flush batch if full
-		if len(userBatch) >= batchSize {
+		if pgOutput != nil && len(userBatch) >= batchSize {
 			if err := pgOutput.BatchInsertUsers(userBatch); err != nil {
 				return fmt.Errorf("failed to batch insert users: %w", err)
 			}
@@ -85,7 +155,7 @@ func (s *Simulator) initializeData() error {
 		}
 	}
 	// insert remaining users
-	if len(userBatch) > 0 {
+	if pgOutput != nil && len(userBatch) > 0 {
 		if err := pgOutput.BatchInsertUsers(userBatch); err != nil {
 			return fmt.Errorf("failed to batch insert remaining users: %w", err)
 		}
@@ -93,19 +163,22 @@ func (s *Simulator) initializeData() error {
 
 	// initialise restaurants
 	log.Printf("Generating %d initial restaurants...", s.Config.InitialRestaurants)
+	factories.Seed(entitySeed, "restaurants")
+	restaurantOrder := make([]*models.Restaurant, 0, s.Config.InitialRestaurants)
 	for i := 0; i < s.Config.InitialRestaurants; i++ {
 		restaurant := restaurantFactory.CreateRestaurant(s.Config)
 		s.Restaurants[restaurant.ID] = restaurant
+		restaurantOrder = append(restaurantOrder, restaurant)
 		restaurantBatch = append(restaurantBatch, restaurant)
 
-		if len(restaurantBatch) >= batchSize {
+		if pgOutput != nil && len(restaurantBatch) >= batchSize {
 			if err := pgOutput.BatchInsertRestaurants(restaurantBatch); err != nil {
 				return fmt.Errorf("failed to batch insert restaurants: %w", err)
 			}
 			restaurantBatch = restaurantBatch[:0]
 		}
 	}
-	if len(restaurantBatch) > 0 {
+	if pgOutput != nil && len(restaurantBatch) > 0 {
 		if err := pgOutput.BatchInsertRestaurants(restaurantBatch); err != nil {
 			return fmt.Errorf("failed to batch insert remaining restaurants: %w", err)
 		}
@@ -113,19 +186,21 @@ func (s *Simulator) initializeData() error {
 
 	// initialise delivery partners
 	log.Printf("Generating %d initial delivery partners...", s.Config.InitialPartners)
+	factories.Seed(entitySeed, "partners")
 	for i := 0; i < s.Config.InitialPartners; i++ {
 		partner := deliveryPartnerFactory.CreateDeliveryPartner(s.Config)
+		s.updatePartnerExperience(partner)
 		s.DeliveryPartners[i] = partner
 		deliveryPartnerBatch = append(deliveryPartnerBatch, partner)
 
-		if len(deliveryPartnerBatch) >= batchSize {
+		if pgOutput != nil && len(deliveryPartnerBatch) >= batchSize {
 			if err := pgOutput.BatchInsertDeliveryPartners(deliveryPartnerBatch); err != nil {
 				return fmt.Errorf("failed to batch insert delivery partners: %w", err)
 			}
 			deliveryPartnerBatch = deliveryPartnerBatch[:0]
 		}
 	}
-	if len(deliveryPartnerBatch) > 0 {
+	if pgOutput != nil && len(deliveryPartnerBatch) > 0 {
 		if err := pgOutput.BatchInsertDeliveryPartners(deliveryPartnerBatch); err != nil {
 			return fmt.Errorf("failed to batch insert remaining delivery partners: %w", err)
 		}
@@ -133,19 +208,23 @@ func (s *Simulator) initializeData() error {
 
 	// initialise menu items
 	log.Printf("Generating menu items for restaurants...")
-	fake := faker.New()
+	factories.Seed(entitySeed, "menus")
 	totalMenuItems := 0
-	for restaurantID, restaurant := range s.Restaurants {
-		itemCount := fake.IntBetween(10, 30)
+	// generate in creation order rather than map order so menus are reproducible
+	for _, restaurant := range restaurantOrder {
+		restaurantID := restaurant.ID
+		itemCount := factories.MenuSize(s.Config, restaurant.Cuisines)
 		log.Printf("Generating %d menu items for restaurant %s", itemCount, restaurantID)
+		priceFactor := s.competitionPriceFactor(restaurant)
 
 		for i := 0; i < itemCount; i++ {
 			menuItem := menuItemFactory.CreateMenuItem(restaurant, s.Config)
+			menuItem.Price = math.Round(menuItem.Price*priceFactor*100) / 100
 			s.MenuItems[menuItem.ID] = &menuItem
 			s.Restaurants[restaurantID].MenuItems = append(s.Restaurants[restaurantID].MenuItems, menuItem.ID)
 			menuItemBatch = append(menuItemBatch, &menuItem)
 
-			if len(menuItemBatch) >= batchSize {
+			if pgOutput != nil && len(menuItemBatch) >= batchSize {
 				if err := pgOutput.BatchInsertMenuItems(menuItemBatch); err != nil {
 					log.Printf("Failed to insert batch of menu items: %v", err)
 					return fmt.Errorf("failed to batch insert menu items: %w", err)
@@ -157,7 +236,7 @@ func (s *Simulator) initializeData() error {
 		}
 	}
 
-	if len(menuItemBatch) > 0 {
+	if pgOutput != nil && len(menuItemBatch) > 0 {
 		if err := pgOutput.BatchInsertMenuItems(menuItemBatch); err != nil {
 			log.Printf("Failed to insert final batch of menu items: %v", err)
 			return fmt.Errorf("failed to batch insert remaining menu items: %w", err)
@@ -198,7 +277,8 @@ func (s *Simulator) growUsers() {
 	if newUsersToAdd > 0 {
 		userFactory := &factories.UserFactory{}
 		for i := 0; i < newUsersToAdd; i++ {
-			newUser := userFactory.CreateUser(s.Config)
+			var newUser *models.User
+			s.growth.Generate(func() { newUser = userFactory.CreateUser(s.Config) })
 			s.Users = append(s.Users, newUser)
 
 			// schedule the first order for this new user
@@ -239,20 +319,46 @@ func (s *Simulator) processEvent(event *models.Event) {
 		s.handleUpdateRestaurantStatus(event.Data.(*models.Restaurant))
 	case models.EventGenerateReview:
 		s.handleGenerateReview(event.Data.(*models.Order))
+	case models.EventComplaintCheck:
+		s.handleComplaintCheck(event.Data.(*models.Order), event.Time)
+	case models.EventPartnerGhosted:
+		s.handlePartnerGhosted(event.Data.(*models.PartnerGhosting))
+	case models.EventOrderAcceptance:
+		s.handleOrderAcceptance(event.Data.(*models.OrderAcceptance))
+	case models.EventNotification:
+		s.handleReviewReminder(event.Data.(*models.Notification))
 
 	}
 }
 
 func (s *Simulator) simulateTimeStep() {
 	s.updateTrafficConditions()
+	s.updateWeather()
+	s.updatePlatformOutage()
 	s.generateOrders()
 	s.updateOrderStatuses()
 	s.updateDeliveryPartnerLocations()
 	s.updateUserBehaviour()
 	s.updateRestaurantStatus()
+	s.scheduleRestaurantMetrics()
+	s.schedulePartnerShiftSummaries()
+	s.scheduleWeatherObservations()
+	s.scheduleDailySummaries()
+	s.scheduleRatingRecomputation()
+	s.updateRestaurantAvailability()
+	s.updateHomepageFeatures()
+	s.updateVirality()
+	s.returnOfflinePartners()
+	s.scheduleCashDrops()
+	s.scheduleDualApping()
+	s.enforcePartnerRatings()
+	s.accruePartnerPay()
 	if s.Config.UserGrowthRate > 0 {
 		s.growUsers()
 	}
+	if s.Config.PartnerGrowthRate > 0 {
+		s.growPartners()
+	}
 }
 
 func (s *Simulator) showProgress(eventsCount int) {
@@ -266,33 +372,77 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 	var topic string
 
 	baseEvent := NewBaseEvent(event.Type, event.Time)
+	baseEvent.TraceID = s.orderTraceID(eventOrderID(event))
+
+	if order := lifecycleOrder(event); order != nil && s.staleOrderStatus(order) {
+		// a later status has already been written for this order
+		return models.EventMessage{}, nil
+	}
 
 	switch event.Type {
 	case models.EventPlaceOrder:
+		if s.platformDown() {
+			// the order failed; see deferOrderAttempt
+			return models.EventMessage{}, nil
+		}
 		user := event.Data.(*models.User)
-		order, err := s.createAndAddOrder(user)
+		order, err := s.createAndAddOrder(user, s.orderPlacementTime(event.Time))
 		if err != nil {
 			return models.EventMessage{}, fmt.Errorf("failed to create order: %w", err)
 		}
+		if order == nil {
+			// the cart was abandoned
+			return models.EventMessage{}, nil
+		}
 
-		eventData = OrderPlacedEvent{
+		placed := OrderPlacedEvent{
 			ID:                order.ID,
 			CustomerID:        user.ID,
 			RestaurantID:      order.RestaurantID,
 			DeliveryPartnerID: order.DeliveryPartnerID,
+			RestaurantIDs:     pickupStops(order),
 			ItemIDs:           order.Items,
 			TotalAmount:       order.TotalAmount,
 			DeliveryCost:      order.DeliveryCost,
+			DistanceFee:       order.DistanceFee,
+			TaxRate:           order.TaxRate,
+			TaxAmount:         order.TaxAmount,
+			ServiceFeeRate:    order.ServiceFeeRate,
+			ServiceFee:        order.ServiceFee,
+			ToppedUp:          order.ToppedUp,
+			Tip:               order.Tip,
 			PaymentMethod:     order.PaymentMethod,
 			OrderPlacedAt:     order.OrderPlacedAt,
 			DeliveryAddress:   order.Address,
+			Instruction:       order.DeliveryInstruction,
+			DeliveryNote:      order.DeliveryNote,
+			SchemaVersion:     baseEvent.SchemaVersion,
+			EventVersion:      baseEvent.EventVersion,
 		}
 
+		if s.Config.EnrichOrderEvents {
+			s.enrichOrderPlacedEvent(&placed, order, user)
+		}
+		placed.HomepageFeatured = order.HomepageFeatured
+		placed.Viral = order.Viral
+		placed.TraceID = s.orderTraceID(order.ID)
+		if order.ReorderOf != "" {
+			placed.IsReorder = true
+			placed.ReorderOf = &order.ReorderOf
+		}
+		if upsell := order.Upsell; upsell != nil {
+			placed.UpsellShown = true
+			placed.UpsellType = &upsell.Type
+			placed.UpsellItemID = &upsell.ItemID
+			placed.UpsellAccepted = upsell.Accepted
+		}
+		eventData = placed
+
 		topic = "order_placed_events"
 
 	case models.EventPrepareOrder:
 		order := event.Data.(*models.Order)
-		eventData = map[string]interface{}{
+		prepEvent := map[string]interface{}{
 			"order_id":        order.ID,
 			"user_id":         order.CustomerID,
 			"restaurant_id":   order.RestaurantID,
@@ -301,13 +451,43 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 			"prep_start_time": order.PrepStartTime,
 			"total_amount":    order.TotalAmount,
 			"status":          order.Status,
+			"schema_version":  baseEvent.SchemaVersion,
+			"event_version":   baseEvent.EventVersion,
+		}
+		if baseEvent.TraceID != "" {
+			prepEvent["trace_id"] = baseEvent.TraceID
 		}
+		if restaurant := s.getRestaurant(order.RestaurantID); restaurant != nil && restaurant.KitchenIncident != nil {
+			prepEvent["kitchen_incident"] = restaurant.KitchenIncident.Type
+		}
+		eventData = prepEvent
 		topic = "order_preparation_events"
 
+	case models.EventPrepProgress:
+		progress := event.Data.(*models.PrepProgress)
+		order := progress.Order
+		if order.Status != models.OrderStatusPreparing {
+			// the order finished early or was cancelled, nothing to report
+			return models.EventMessage{}, nil
+		}
+		baseEvent.RestaurantID = order.RestaurantID
+		baseEvent.UserID = order.CustomerID
+
+		eventData = OrderPrepProgressEvent{
+			BaseEvent:        baseEvent,
+			OrderID:          order.ID,
+			Status:           order.Status,
+			PrepStartTime:    order.PrepStartTime,
+			EstimatedReadyAt: progress.EstimatedReadyAt,
+			ProgressPercent:  prepProgressPercent(progress, event.Time),
+		}
+		topic = "order_prep_progress_events"
+
 	case models.EventOrderReady:
 		order := event.Data.(*models.Order)
 		baseEvent.RestaurantID = order.RestaurantID
 		baseEvent.UserID = order.CustomerID
+		s.attachConditions(&baseEvent, orderLocation(order))
 
 		eventData = OrderReadyEvent{
 			BaseEvent:       baseEvent,
@@ -323,13 +503,17 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 		baseEvent.RestaurantID = order.RestaurantID
 		baseEvent.DeliveryID = order.DeliveryPartnerID
 		baseEvent.UserID = order.CustomerID
+		s.attachConditions(&baseEvent, orderLocation(order))
 
-		eventData = DeliveryPartnerAssignmentEvent{
-			BaseEvent:           baseEvent,
-			OrderID:             order.ID,
-			Status:              order.Status,
-			EstimatedPickupTime: order.EstimatedPickupTime,
+		assignment := DeliveryPartnerAssignmentEvent{
+			BaseEvent:             baseEvent,
+			OrderID:               order.ID,
+			Status:                order.Status,
+			EstimatedPickupTime:   order.EstimatedPickupTime,
+			EstimatedDeliveryTime: order.EstimatedDeliveryTime,
 		}
+		assignment.ETAMinMinutes, assignment.ETAMaxMinutes = s.displayedETARange(order.EstimatedDeliveryTime, event.Time)
+		eventData = assignment
 		topic = "delivery_partner_assignment_events"
 
 	case models.EventPickUpOrder:
@@ -337,14 +521,17 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 		baseEvent.RestaurantID = order.RestaurantID
 		baseEvent.DeliveryID = order.DeliveryPartnerID
 		baseEvent.UserID = order.CustomerID
+		s.attachConditions(&baseEvent, orderLocation(order))
 
-		eventData = OrderPickupEvent{
+		pickup := OrderPickupEvent{
 			BaseEvent:             baseEvent,
 			OrderID:               order.ID,
 			Status:                order.Status,
 			PickupTime:            order.PickupTime,
 			EstimatedDeliveryTime: order.EstimatedDeliveryTime,
 		}
+		pickup.ETAMinMinutes, pickup.ETAMaxMinutes = s.displayedETARange(order.EstimatedDeliveryTime, event.Time)
+		eventData = pickup
 		topic = "order_pickup_events"
 
 	case models.EventUpdatePartnerLocation:
@@ -353,17 +540,29 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 		if partner == nil {
 			return models.EventMessage{}, fmt.Errorf("partner not found: %s", update.PartnerID)
 		}
+		if s.duplicatePing(update.PartnerID, event.Time) || s.pingLost() {
+			return models.EventMessage{}, nil
+		}
+		reported, reportedAt, ok := s.reportedPartnerPing(update)
+		if !ok {
+			// the ping never reached the platform
+			return models.EventMessage{}, nil
+		}
 
 		eventData = PartnerLocationUpdateEvent{
 			Timestamp:         event.Time,
 			EventType:         event.Type,
 			DeliveryPartnerID: update.PartnerID,
 			OrderID:           update.OrderID,
-			NewLocation:       models.Location{Lat: update.NewLocation.Lat, Lon: update.NewLocation.Lon},
+			NewLocation:       reported,
 			CurrentOrder:      partner.CurrentOrderID,
 			Status:            partner.Status,
-			UpdateTime:        s.CurrentTime,
-			Speed:             update.Speed,
+			UpdateTime:        reportedAt,
+			Speed:             s.Config.OutputSpeed(update.Speed),
+			CashOnHand:        math.Round(partner.CashOnHand*100) / 100,
+			TraceID:           baseEvent.TraceID,
+			SchemaVersion:     baseEvent.SchemaVersion,
+			EventVersion:      baseEvent.EventVersion,
 		}
 		topic = "partner_location_events"
 
@@ -375,6 +574,7 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 		}
 		baseEvent.UserID = order.CustomerID
 		baseEvent.RestaurantID = order.RestaurantID
+		s.attachConditions(&baseEvent, orderLocation(order))
 
 		eventData = OrderInTransitEvent{
 			BaseEvent:             baseEvent,
@@ -417,18 +617,36 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 		baseEvent.RestaurantID = order.RestaurantID
 		baseEvent.DeliveryID = order.DeliveryPartnerID
 		baseEvent.UserID = order.CustomerID
+		s.attachConditions(&baseEvent, orderLocation(order))
 
-		eventData = OrderDeliveryEvent{
+		deliveryEvent := OrderDeliveryEvent{
 			BaseEvent:             baseEvent,
 			OrderID:               order.ID,
 			Status:                order.Status,
 			EstimatedDeliveryTime: order.EstimatedDeliveryTime,
 			ActualDeliveryTime:    order.ActualDeliveryTime,
+			Instruction:           order.DeliveryInstruction,
+			DoorDwellSeconds:      order.DoorDwell.Seconds(),
+		}
+		if partner := s.getDeliveryPartner(order.DeliveryPartnerID); partner != nil {
+			deliveryEvent.PartnerExperienceTier = models.ExperienceTier(partner.Experience)
+		}
+		if !order.EstimatedDeliveryTime.IsZero() {
+			deliveryEvent.ETAErrorSeconds = order.ActualDeliveryTime.Sub(order.EstimatedDeliveryTime).Seconds()
+		}
+		if order.CustomerRating > 0 {
+			rating := s.Config.OutputRating(order.CustomerRating)
+			deliveryEvent.CustomerRating = &rating
 		}
+		eventData = deliveryEvent
 		topic = "order_delivery_events"
 
 	case models.EventCancelOrder:
 		order := event.Data.(*models.Order)
+		if order.Status != models.OrderStatusCancelled {
+			// delivered before the cancellation took effect
+			return models.EventMessage{}, nil
+		}
 		baseEvent.RestaurantID = order.RestaurantID
 		baseEvent.UserID = order.CustomerID
 
@@ -437,6 +655,8 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 			OrderID:          order.ID,
 			Status:           order.Status,
 			CancellationTime: s.CurrentTime,
+			CancelledBy:      order.CancelledBy,
+			Reason:           order.CancellationReason,
 		}
 		topic = "order_cancellation_events"
 
@@ -457,6 +677,8 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 			EventType:      &eventType,
 			UserID:         &userId,
 			OrderFrequency: &orderFrequency,
+			SchemaVersion:  baseEvent.SchemaVersion,
+			EventVersion:   baseEvent.EventVersion,
 		}
 
 		// only include LastOrderTime if it's not the zero value
@@ -481,7 +703,14 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 			BaseEvent:       baseEvent,
 			Capacity:        int32(capacity),
 			CurrentCapacity: int32(capacity),
+			BaseCapacity:    int32(s.baseCapacity(restaurant)),
 			PrepTime:        prepTime,
+			AvgPrepTime:     restaurant.AvgPrepTime,
+			Degraded:        restaurant.KitchenIncident != nil,
+			KitchenIncident: kitchenIncidentType(restaurant),
+			PaymentMethods:  restaurant.AcceptedPaymentMethods,
+			BadActor:        restaurant.BadActor,
+			Reliability:     math.Round(calculateReliabilityScore(restaurant)*1000) / 1000,
 		}
 		topic = "restaurant_status_events"
 
@@ -493,26 +722,410 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 		// create the review
 		review := s.createReview(order)
 
+		// update ratings based on the review
+		s.updateRatings(&review)
+
 		// add the review to the simulator's reviews
 		s.Reviews = append(s.Reviews, review)
+		s.scheduleReviewEdit(review)
+		s.linkReview(order, &review)
 
-		// update ratings based on the review
-		s.updateRatings(review)
-
-		eventData = ReviewEvent{
+		reviewEvent := ReviewEvent{
 			BaseEvent:         baseEvent,
 			ReviewID:          review.ID,
 			OrderID:           review.OrderID,
 			CustomerID:        review.CustomerID,
 			DeliveryPartnerID: review.DeliveryPartnerID,
-			FoodRating:        review.FoodRating,
-			DeliveryRating:    review.DeliveryRating,
-			OverallRating:     review.OverallRating,
+			FoodRating:        s.outputReviewRating(review.FoodRating),
+			DeliveryRating:    s.outputReviewRating(review.DeliveryRating),
+			OverallRating:     s.Config.OutputRating(review.OverallRating),
 			Comment:           review.Comment,
 			CreatedAt:         review.CreatedAt,
+			UpdatedAt:         review.UpdatedAt,
 			OrderTotal:        order.TotalAmount,
 			DeliveryTime:      order.ActualDeliveryTime.Sub(order.OrderPlacedAt).Milliseconds(),
+			RestaurantReplied: review.RestaurantReplied,
 		}
+		eventData = s.withItemRatings(reviewEvent, &review)
+		topic = "review_events"
+
+	case models.EventRestaurantMetrics:
+		metrics := event.Data.(*models.RestaurantMetrics)
+		baseEvent.RestaurantID = metrics.RestaurantID
+		eventData = RestaurantMetricsEvent{
+			BaseEvent:         baseEvent,
+			WindowStart:       metrics.WindowStart,
+			WindowEnd:         metrics.WindowEnd,
+			OrdersPlaced:      int32(metrics.OrdersPlaced),
+			OrdersCompleted:   int32(metrics.OrdersCompleted),
+			OrdersCancelled:   int32(metrics.OrdersCancelled),
+			CompletionRate:    metrics.CompletionRate,
+			EstimatedPrepTime: metrics.EstimatedPrepTime,
+			ActualPrepTime:    metrics.ActualPrepTime,
+			LateOrders:        int32(metrics.LateOrders),
+			EarlyOrders:       int32(metrics.EarlyOrders),
+			PickupEfficiency:  metrics.PickupEfficiency,
+		}
+		topic = "restaurant_metrics_events"
+
+	case models.EventAbandonCart:
+		cart := event.Data.(*models.AbandonedCart)
+		baseEvent.UserID = cart.UserID
+		baseEvent.RestaurantID = cart.RestaurantID
+		eventData = AbandonedCartEvent{
+			BaseEvent:   baseEvent,
+			CartID:      cart.ID,
+			ItemIDs:     cart.Items,
+			Subtotal:    cart.Subtotal,
+			DeliveryFee: cart.DeliveryFee,
+			Reason:      cart.Reason,
+		}
+		topic = "abandoned_cart_events"
+
+	case models.EventFeaturedDishStarted, models.EventFeaturedDishEnded:
+		featured := event.Data.(*models.FeaturedDish)
+		baseEvent.RestaurantID = featured.RestaurantID
+		eventData = FeaturedDishEvent{
+			BaseEvent:     baseEvent,
+			MenuItemID:    featured.MenuItemID,
+			FeaturedUntil: featured.Until,
+		}
+		topic = "restaurant_menu_events"
+
+	case models.EventWeatherObservation:
+		weather := event.Data.(*models.WeatherCondition)
+		observation := WeatherObservationEvent{
+			BaseEvent:     baseEvent,
+			Condition:     s.Config.WeatherLabel(weather.Condition),
+			Temperature:   math.Round(s.Config.OutputTemperature(weather.Temperature)*10) / 10,
+			WindSpeed:     math.Round(s.Config.OutputSpeed(weather.WindSpeed)*10) / 10,
+			Humidity:      weather.Humidity,
+			Precipitation: math.Round(s.Config.OutputPrecipitation(weather.Precipitation)*100) / 100,
+		}
+		if weather.Region != "" {
+			observation.Region = &weather.Region
+		}
+		eventData = observation
+		topic = "weather_observation_events"
+
+	case models.EventComplaintCheck:
+		// internal bookkeeping only, any resulting ticket is its own event
+		return models.EventMessage{}, nil
+
+	case models.EventSupportTicket:
+		ticket := event.Data.(*models.SupportTicket)
+		baseEvent.UserID = ticket.CustomerID
+		baseEvent.RestaurantID = ticket.RestaurantID
+		eventData = SupportTicketEvent{
+			BaseEvent:   baseEvent,
+			TicketID:    ticket.ID,
+			OrderID:     ticket.OrderID,
+			Category:    ticket.Category,
+			Resolution:  ticket.Resolution,
+			Amount:      ticket.Amount,
+			MinutesLate: ticket.MinutesLate,
+		}
+		topic = "support_ticket_events"
+
+	case models.EventPartnerGhosted:
+		ghosting := event.Data.(*models.PartnerGhosting)
+		if !ghosting.Ghosted {
+			return models.EventMessage{}, nil
+		}
+		baseEvent.DeliveryID = ghosting.PartnerID
+		baseEvent.RestaurantID = ghosting.RestaurantID
+		baseEvent.UserID = ghosting.CustomerID
+		ghostingEvent := PartnerGhostingEvent{
+			BaseEvent:       baseEvent,
+			OrderID:         ghosting.OrderID,
+			AssignedAt:      ghosting.AssignedAt,
+			MinutesAssigned: math.Round(event.Time.Sub(ghosting.AssignedAt).Minutes()*10) / 10,
+		}
+		if partner := s.getDeliveryPartner(ghosting.PartnerID); partner != nil {
+			ghostingEvent.Ghostings = int32(partner.Ghostings)
+			ghostingEvent.Reliability = math.Round(partnerReliability(partner)*1000) / 1000
+		}
+		eventData = ghostingEvent
+		topic = "delivery_partner_ghosting_events"
+
+	case models.EventHomepageFeatureStarted, models.EventHomepageFeatureEnded:
+		feature := event.Data.(*models.HomepageFeature)
+		baseEvent.RestaurantID = feature.RestaurantID
+		eventData = HomepageFeatureEvent{
+			BaseEvent:     baseEvent,
+			FeaturedFrom:  feature.Start,
+			FeaturedUntil: feature.Until,
+			Boost:         s.Config.HomepageFeatureBoost,
+			Scheduled:     feature.Scheduled,
+		}
+		topic = "restaurant_promotion_events"
+
+	case models.EventRestaurantViral:
+		spike := event.Data.(*models.ViralSpike)
+		baseEvent.RestaurantID = spike.RestaurantID
+		eventData = RestaurantViralEvent{
+			BaseEvent:     baseEvent,
+			Magnitude:     spike.Magnitude,
+			HalfLifeHours: spike.HalfLife.Hours(),
+		}
+		topic = "restaurant_virality_events"
+
+	case models.EventOrderReviewLink:
+		link := event.Data.(*models.OrderReviewLink)
+		baseEvent.UserID = link.CustomerID
+		baseEvent.RestaurantID = link.RestaurantID
+		linkEvent := OrderReviewLinkEvent{
+			BaseEvent:   baseEvent,
+			OrderID:     link.OrderID,
+			DeliveredAt: link.DeliveredAt,
+		}
+		if link.ReviewID != "" {
+			linkEvent.ReviewID = &link.ReviewID
+			linkEvent.ReviewedAt = &link.ReviewedAt
+		}
+		eventData = linkEvent
+		topic = "order_review_link_events"
+
+	case models.EventPlatformOutageStarted, models.EventPlatformOutageEnded:
+		outage := event.Data.(*models.PlatformOutage)
+		outageEvent := PlatformOutageEvent{
+			BaseEvent: baseEvent,
+			OutageID:  outage.ID,
+			Reason:    outage.Reason,
+			StartedAt: outage.Start,
+			EndsAt:    outage.Until,
+		}
+		if event.Type == models.EventPlatformOutageEnded {
+			outageEvent.MissedOrders = int32(outage.MissedOrders)
+			outageEvent.RetryOrders = int32(outage.Retried)
+		}
+		eventData = outageEvent
+		topic = "platform_outage_events"
+
+	case models.EventOrderAcceptance:
+		acceptance := event.Data.(*models.OrderAcceptance)
+		baseEvent.UserID = acceptance.CustomerID
+		baseEvent.RestaurantID = acceptance.RestaurantID
+		acceptanceEvent := OrderAcceptanceEvent{
+			BaseEvent:      baseEvent,
+			OrderID:        acceptance.OrderID,
+			Status:         models.AcceptanceStatusAccepted,
+			LatencySeconds: acceptance.Latency.Seconds(),
+			SLASeconds:     acceptance.SLA.Seconds(),
+			PlacedAt:       acceptance.PlacedAt,
+		}
+		if acceptance.Rejected {
+			acceptanceEvent.Status = models.AcceptanceStatusAutoRejected
+			acceptanceEvent.LatencySeconds = acceptance.SLA.Seconds()
+			acceptanceEvent.SLABreached = true
+		}
+		eventData = acceptanceEvent
+		topic = "order_acceptance_events"
+
+	case models.EventPartnerCashDrop:
+		drop := event.Data.(*models.PartnerCashDrop)
+		baseEvent.DeliveryID = drop.PartnerID
+		eventData = PartnerCashDropEvent{
+			BaseEvent: baseEvent,
+			Amount:    math.Round(drop.Amount*100) / 100,
+			Limit:     s.Config.PartnerCashLimit,
+			BackAt:    drop.Until,
+		}
+		topic = "delivery_partner_cash_events"
+
+	case models.EventPartnerDualApp:
+		away := event.Data.(*models.PartnerDualApp)
+		baseEvent.DeliveryID = away.PartnerID
+		eventData = PartnerDualAppEvent{
+			BaseEvent: baseEvent,
+			Reason:    partnerAbsenceOtherApp,
+			BackAt:    away.Until,
+		}
+		topic = "delivery_partner_absence_events"
+
+	case models.EventRestaurantHours:
+		change := event.Data.(*models.RestaurantHoursChange)
+		baseEvent.RestaurantID = change.RestaurantID
+		hoursEvent := RestaurantHoursEvent{
+			BaseEvent:  baseEvent,
+			Status:     change.Status,
+			Reason:     change.Reason,
+			OpenAt:     change.Until,
+			OpenOrders: int32(change.OpenOrders),
+		}
+		eventData = hoursEvent
+		topic = "restaurant_hours_events"
+
+	case models.EventRatingRecomputed:
+		recomputation := event.Data.(*models.RatingRecomputation)
+		topic = "restaurant_rating_events"
+		if recomputation.EntityType == models.SummaryEntityPartners {
+			baseEvent.DeliveryID = recomputation.EntityID
+			topic = "partner_rating_events"
+		} else {
+			baseEvent.RestaurantID = recomputation.EntityID
+		}
+		previous := s.Config.OutputRating(recomputation.PreviousRating)
+		rating := s.Config.OutputRating(recomputation.Rating)
+		eventData = RatingRecomputedEvent{
+			BaseEvent:      baseEvent,
+			PreviousRating: previous,
+			Rating:         rating,
+			Correction:     math.Round((rating-previous)*10) / 10,
+			Reviews:        int32(recomputation.Reviews),
+		}
+
+	case models.EventNotification:
+		notification := event.Data.(*models.Notification)
+		if notification.Type == models.NotificationReviewReminder && notification.Order.ReviewReminderID != notification.ID {
+			// the user reviewed the order before the reminder was due, or
+			// was already reminded about it
+			return models.EventMessage{}, nil
+		}
+		baseEvent.UserID = notification.UserID
+		baseEvent.RestaurantID = notification.Order.RestaurantID
+		eventData = NotificationEvent{
+			BaseEvent:        baseEvent,
+			NotificationID:   notification.ID,
+			OrderID:          notification.Order.ID,
+			NotificationType: notification.Type,
+			Channel:          notification.Channel,
+		}
+		topic = "notification_events"
+
+	case models.EventRefundInitiated, models.EventRefundCompleted:
+		refund := event.Data.(*models.Refund)
+		baseEvent.UserID = refund.CustomerID
+		baseEvent.RestaurantID = refund.RestaurantID
+		status := models.RefundStatusInitiated
+		if event.Type == models.EventRefundCompleted {
+			status = models.RefundStatusCompleted
+		}
+		eventData = RefundEvent{
+			BaseEvent:     baseEvent,
+			RefundID:      refund.ID,
+			OrderID:       refund.OrderID,
+			Status:        status,
+			Reason:        refund.Reason,
+			PaymentMethod: refund.PaymentMethod,
+			Amount:        refund.Amount,
+			InitiatedAt:   refund.InitiatedAt,
+			SettlesAt:     refund.SettlesAt,
+		}
+		topic = "refund_events"
+
+	case models.EventOrderSettlement:
+		settlement := event.Data.(*models.OrderSettlement)
+		baseEvent.UserID = settlement.CustomerID
+		baseEvent.RestaurantID = settlement.RestaurantID
+		baseEvent.DeliveryID = settlement.PartnerID
+		eventData = OrderSettlementEvent{
+			BaseEvent:        baseEvent,
+			OrderID:          settlement.OrderID,
+			Subtotal:         settlement.Subtotal,
+			Discount:         settlement.Discount,
+			Tax:              settlement.Tax,
+			ServiceFee:       settlement.ServiceFee,
+			DeliveryFee:      settlement.DeliveryFee,
+			Tip:              settlement.Tip,
+			TotalAmount:      settlement.TotalAmount,
+			CommissionRate:   settlement.CommissionRate,
+			Commission:       settlement.Commission,
+			RestaurantPayout: settlement.RestaurantPayout,
+			PartnerPay:       settlement.PartnerPay,
+			PlatformNet:      settlement.PlatformNet,
+		}
+		topic = "order_settlement_events"
+
+	case models.EventPartnerDeactivated:
+		deactivation := event.Data.(*models.PartnerDeactivation)
+		baseEvent.DeliveryID = deactivation.PartnerID
+		eventData = PartnerDeactivationEvent{
+			BaseEvent:       baseEvent,
+			Rating:          math.Round(s.Config.OutputRating(deactivation.Rating)*100) / 100,
+			TotalRatings:    int32(deactivation.TotalRatings),
+			Threshold:       s.Config.OutputRating(s.Config.PartnerDeactivationRating),
+			HandedOffOrders: deactivation.HandedOffOrders,
+		}
+		topic = "delivery_partner_deactivation_events"
+
+	case models.EventDailySummary:
+		summary := event.Data.(*models.DailySummary)
+		topic = "restaurant_daily_summary_events"
+		if summary.EntityType == models.SummaryEntityPartners {
+			baseEvent.DeliveryID = summary.EntityID
+			topic = "partner_daily_summary_events"
+		} else {
+			baseEvent.RestaurantID = summary.EntityID
+		}
+		dailyEvent := DailySummaryEvent{
+			BaseEvent:      baseEvent,
+			Date:           summary.Date.Format("2006-01-02"),
+			Orders:         int32(summary.Orders),
+			Completed:      int32(summary.Completed),
+			Cancelled:      int32(summary.Cancelled),
+			CompletionRate: math.Round(summary.CompletionRate*1000) / 1000,
+			LateDeliveries: int32(summary.LateDeliveries),
+			Revenue:        summary.Revenue,
+			Ratings:        int32(summary.Ratings),
+			Partial:        summary.Partial,
+		}
+		if summary.Ratings > 0 {
+			rating := math.Round(s.Config.OutputRating(summary.AvgRating)*100) / 100
+			dailyEvent.AvgRating = &rating
+		}
+		eventData = dailyEvent
+
+	case models.EventPartnerShiftSummary:
+		stats := event.Data.(*models.PartnerShiftStats)
+		baseEvent.DeliveryID = stats.PartnerID
+		eventData = s.newPartnerShiftSummaryEvent(baseEvent, stats)
+		topic = "delivery_partner_shift_events"
+
+	case models.EventPartnerPayout:
+		payout := event.Data.(*models.PartnerPayout)
+		baseEvent.DeliveryID = payout.PartnerID
+		eventData = PartnerPayoutEvent{
+			BaseEvent:      baseEvent,
+			PayModel:       payout.PayModel,
+			ShiftStart:     payout.ShiftStart,
+			ShiftEnd:       payout.ShiftEnd,
+			OnlineMinutes:  math.Round(payout.OnlineMinutes*10) / 10,
+			Deliveries:     int32(payout.Deliveries),
+			DeliveryPay:    math.Round(payout.DeliveryPay*100) / 100,
+			HourlyPay:      math.Round(payout.HourlyPay*100) / 100,
+			GuaranteeTopUp: math.Round(payout.GuaranteeTopUp*100) / 100,
+			Tips:           math.Round(payout.Tips*100) / 100,
+			Total:          math.Round(payout.Total()*100) / 100,
+		}
+		topic = "delivery_partner_payout_events"
+
+	case models.EventEditReview:
+		review := event.Data.(*models.Review)
+		s.editReview(review, event.Time)
+		baseEvent.RestaurantID = review.RestaurantID
+		baseEvent.DeliveryID = review.DeliveryPartnerID
+		baseEvent.UserID = review.CustomerID
+
+		reviewEvent := ReviewEvent{
+			BaseEvent:         baseEvent,
+			ReviewID:          review.ID,
+			OrderID:           review.OrderID,
+			CustomerID:        review.CustomerID,
+			DeliveryPartnerID: review.DeliveryPartnerID,
+			FoodRating:        s.outputReviewRating(review.FoodRating),
+			DeliveryRating:    s.outputReviewRating(review.DeliveryRating),
+			OverallRating:     s.Config.OutputRating(review.OverallRating),
+			Comment:           review.Comment,
+			CreatedAt:         review.CreatedAt,
+			UpdatedAt:         review.UpdatedAt,
+			RestaurantReplied: review.RestaurantReplied,
+		}
+		if order := s.getOrderByID(review.OrderID); order != nil {
+			reviewEvent.OrderTotal = order.TotalAmount
+			reviewEvent.DeliveryTime = order.ActualDeliveryTime.Sub(order.OrderPlacedAt).Milliseconds()
+		}
+		eventData = s.withItemRatings(reviewEvent, review)
 		topic = "review_events"
 
 	default:
@@ -525,6 +1138,11 @@ func (s *Simulator) serializeEvent(event models.Event) (models.EventMessage, err
 		log.Printf("Error serializing event: %v", err)
 		return models.EventMessage{}, err
 	}
+	if len(s.Config.PIIScrubFields) > 0 {
+		if data, err = s.scrubPII(data); err != nil {
+			return models.EventMessage{}, err
+		}
+	}
 
 	// return the event message
 	return models.EventMessage{
@@ -544,6 +1162,16 @@ func (s *Simulator) cleanupSimulationState() {
 
 	// check and correct partner statuses
 	for i, partner := range s.DeliveryPartners {
+		if partner.Status == models.PartnerStatusDeactivated || partner.Status == models.PartnerStatusOffline {
+			// offline and deactivated partners hold no orders; offline ones
+			// come back on their own once their break is over
+			continue
+		}
+		if partner.Status == models.PartnerStatusDelivering {
+			// handing over an order that is already complete, and freed by
+			// its delivery event
+			continue
+		}
 		if partner.Status != models.PartnerStatusAvailable {
 			order := s.getOrderByID(partner.CurrentOrderID)
 			if order == nil || order.DeliveryPartnerID != partner.ID {
@@ -596,6 +1224,20 @@ func (s *Simulator) cleanupSimulationState() {
 
 // event handlers
 func (s *Simulator) handlePlaceOrder(user *models.User) {
+	_, replacing := s.rejectedRestaurants[user.ID]
+	if s.platformDown() {
+		// the order fails; a replacement for an auto-rejected order is lost with it
+		delete(s.rejectedRestaurants, user.ID)
+		if !replacing {
+			s.deferOrderAttempt(user)
+		}
+		return
+	}
+	if replacing {
+		// the user's next order is already scheduled
+		return
+	}
+
 	// Schedule next order for this user
 	nextOrderTime := s.generateNextOrderTime(user)
 	s.EventQueue.Enqueue(&models.Event{
@@ -617,19 +1259,21 @@ func (s *Simulator) handlePrepareOrder(order *models.Order) {
 
 	// update order status
 	order.Status = models.OrderStatusPreparing
+	s.notifyUser(order, models.NotificationPreparing)
 
 	// estimate prep time
 	prepTime := s.estimatePrepTime(restaurant, order.Items)
 
 	// add some variability to prep time
 	variability := 0.2 // 20% variability
-	actualPrepTime := prepTime * (1 + (s.Rng.Float64()*2-1)*variability)
+	noise := s.Rng.Float64()*2 - 1
+	actualPrepTime := prepTime * (1 + noise*variability) * s.prepTimeOutlierFactor(noise)
 
 	// calculate when the order will be ready
 	readyTime := s.CurrentTime.Add(time.Duration(actualPrepTime) * time.Minute)
 
 	// ensure prep time is reasonable
-	maxPrepTime := 2 * time.Hour
+	maxPrepTime := s.Config.OrderMaxPrepTime
 	if readyTime.Sub(s.CurrentTime) > maxPrepTime {
 		readyTime = s.CurrentTime.Add(maxPrepTime)
 	}
@@ -646,6 +1290,13 @@ func (s *Simulator) handlePrepareOrder(order *models.Order) {
 	// update restaurant orders
 	restaurant.CurrentOrders = append(restaurant.CurrentOrders, *order)
 
+	if s.maybeCancelByRestaurant(order, restaurant, readyTime) {
+		log.Printf("Order %s accepted by restaurant %s, which will cancel it", order.ID, restaurant.ID)
+		return
+	}
+
+	s.schedulePrepProgress(order, prepTime)
+
 	// schedule the next event (order ready)
 	s.EventQueue.Enqueue(&models.Event{
 		Time: readyTime,
@@ -670,6 +1321,7 @@ func (s *Simulator) handleOrderReady(order *models.Order) {
 
 	// Update order status
 	order.Status = models.OrderStatusReady
+	s.recordPrepTime(restaurant, order)
 
 	// Log the event
 	log.Printf("Order %s is ready for pickup at %s", order.ID, s.CurrentTime.Format(time.RFC3339))
@@ -697,7 +1349,7 @@ func (s *Simulator) handleOrderReady(order *models.Order) {
 
 	// Schedule the next event (pickup)
 	// We'll set a timeout for pickup. If not picked up within this time, we'll reassign the order
-	pickupTimeout := s.CurrentTime.Add(15 * time.Minute)
+	pickupTimeout := s.CurrentTime.Add(s.Config.PickupTimeout)
 	s.EventQueue.Enqueue(&models.Event{
 		Time: pickupTimeout,
 		Type: models.EventPickUpOrder,
@@ -723,11 +1375,11 @@ func (s *Simulator) handleAssignDeliveryPartner(event *models.Event) {
 		return
 	}
 
-	availablePartners := s.getAvailablePartnersNear(restaurant.Location)
+	availablePartners := s.partnersWithCashRoom(s.getAvailablePartnersNear(restaurant.Location), order)
 
 	if len(availablePartners) == 0 {
 		// if no partners are available, schedule a retry
-		retryTime := s.CurrentTime.Add(2 * time.Minute)
+		retryTime := s.CurrentTime.Add(s.Config.AssignmentRetryInterval)
 		s.EventQueue.Enqueue(&models.Event{
 			Time: retryTime,
 			Type: models.EventAssignDeliveryPartner,
@@ -738,14 +1390,29 @@ func (s *Simulator) handleAssignDeliveryPartner(event *models.Event) {
 		return
 	}
 
-	// select the best partner (for now, just select randomly)
-	selectedPartner := availablePartners[s.Rng.Intn(len(availablePartners))]
+	// offer the order to partners (for now, in random order) until one accepts
+	selectedPartner := s.selectAcceptingPartner(availablePartners, order)
+	if selectedPartner == nil {
+		retryTime := s.CurrentTime.Add(s.Config.AssignmentRetryInterval)
+		s.EventQueue.Enqueue(&models.Event{
+			Time: retryTime,
+			Type: models.EventAssignDeliveryPartner,
+			Data: order,
+		})
+		log.Printf("All nearby delivery partners declined order %s, scheduling retry at %s",
+			order.ID, retryTime.Format(time.RFC3339))
+		return
+	}
 
 	// update both order and partner atomically
 	if err := s.assignPartnerToOrder(selectedPartner, order); err != nil {
 		log.Printf("Error assigning partner to order: %v", err)
 		return
 	}
+	s.recordAssignmentWait(order)
+	s.maybeScheduleGhosting(selectedPartner, order)
+	s.recordFirstPickup(selectedPartner, order)
+	s.pingOnStatusChange(selectedPartner, order.ID)
 
 	// calculate estimated pickup time
 	estimatedPickupTime := s.estimateArrivalTime(selectedPartner.CurrentLocation, restaurant.Location)
@@ -782,7 +1449,7 @@ func (s *Simulator) handlePickUpOrder(event *models.Event) {
 	}
 
 	// check if the delivery partner is at the restaurant
-	restaurant := s.getRestaurant(order.RestaurantID)
+	restaurant := s.pickupRestaurant(order)
 	if restaurant == nil {
 		log.Printf("Error: Restaurant not found for order %s", order.ID)
 		return
@@ -801,12 +1468,24 @@ func (s *Simulator) handlePickUpOrder(event *models.Event) {
 		return
 	}
 
+	// a multi-restaurant order is only picked up at its last restaurant
+	if s.advancePickup(order, partner) {
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.estimateArrivalTime(partner.CurrentLocation, s.pickupRestaurant(order).Location),
+			Type: models.EventPickUpOrder,
+			Data: order,
+		})
+		return
+	}
+
 	// update order status
 	order.Status = models.OrderStatusPickedUp
 	order.PickupTime = s.CurrentTime
+	s.recordMetricsPickup(order)
 
 	// update delivery partner status
 	partner.Status = models.PartnerStatusEnRouteDelivery
+	s.pingOnStatusChange(partner, order.ID)
 
 	// trigger the "order in transit" event
 	s.EventQueue.Enqueue(&models.Event{
@@ -820,7 +1499,7 @@ func (s *Simulator) handlePickUpOrder(event *models.Event) {
 	order.EstimatedDeliveryTime = estimatedDeliveryTime
 
 	// schedule the first check event
-	nextCheckTime := s.CurrentTime.Add(5 * time.Minute) // check every 5 minutes
+	nextCheckTime := s.CurrentTime.Add(s.Config.InTransitCheckInterval)
 	s.EventQueue.Enqueue(&models.Event{
 		Time: nextCheckTime,
 		Type: models.EventCheckDeliveryStatus,
@@ -832,8 +1511,20 @@ func (s *Simulator) handlePickUpOrder(event *models.Event) {
 }
 
 func (s *Simulator) handleCancelOrder(order *models.Order) {
+	if order.Status == models.OrderStatusCancelled || order.Status == models.OrderStatusDelivered {
+		return
+	}
+	wasPreparing := order.Status == models.OrderStatusPreparing
+	if order.CancelledBy == "" {
+		order.CancelledBy = models.CancelledByCustomer
+	}
+
 	// update order status
 	order.Status = models.OrderStatusCancelled
+	s.recordDailyCancellation(order)
+	s.recordMetricsCancellation(order)
+	s.refundCancelledOrder(order)
+	s.forgetNotifications(order)
 
 	// if a delivery partner was assigned, update their status
 	if order.DeliveryPartnerID != "" {
@@ -845,9 +1536,12 @@ func (s *Simulator) handleCancelOrder(order *models.Order) {
 	}
 
 	// if the order was being prepared, update restaurant status
-	if order.Status == models.OrderStatusPreparing {
+	if wasPreparing {
 		restaurant := s.getRestaurant(order.RestaurantID)
 		if restaurant != nil {
+			if order.CancelledBy == models.CancelledByRestaurant {
+				s.recordRestaurantCancellation(restaurant)
+			}
 			// Remove the order from the restaurant's current orders
 			for i, currentOrder := range restaurant.CurrentOrders {
 				if currentOrder.ID == order.ID {
@@ -882,11 +1576,11 @@ func (s *Simulator) handleCheckDeliveryStatus(order *models.Order) {
 		duration := s.CurrentTime.Sub(partner.LastUpdateTime)
 
 		// move the partner towards the customer
-		partner.CurrentLocation = s.moveTowards(partner.CurrentLocation, user.Location, duration)
+		partner.CurrentLocation = s.movePartner(partner, user.Location, duration)
 		partner.LastUpdateTime = s.CurrentTime
 
 		// order is still in transit, schedule next check
-		nextCheckTime := s.CurrentTime.Add(5 * time.Minute)
+		nextCheckTime := s.CurrentTime.Add(s.Config.InTransitCheckInterval)
 		s.EventQueue.Enqueue(&models.Event{
 			Time: nextCheckTime,
 			Type: models.EventCheckDeliveryStatus,
@@ -932,6 +1626,7 @@ func (s *Simulator) handleOrderInTransit(order *models.Order) {
 		log.Printf("Error: Delivery partner not found for order %s", order.ID)
 		return
 	}
+	s.notifyUser(order, models.NotificationOutForDelivery)
 
 	// ensure we're not scheduling the same event again
 	if order.Status != models.OrderStatusInTransit {
@@ -939,7 +1634,7 @@ func (s *Simulator) handleOrderInTransit(order *models.Order) {
 		order.InTransitTime = s.CurrentTime
 
 		// schedule a check event
-		nextCheckTime := s.CurrentTime.Add(5 * time.Minute) // check every 5 minutes
+		nextCheckTime := s.CurrentTime.Add(s.Config.InTransitCheckInterval)
 		s.EventQueue.Enqueue(&models.Event{
 			Time: nextCheckTime,
 			Type: models.EventCheckDeliveryStatus,
@@ -954,6 +1649,13 @@ func (s *Simulator) handleOrderInTransit(order *models.Order) {
 }
 
 func (s *Simulator) handleDeliverOrder(order *models.Order) {
+	// the order was completed when the partner reached the door, and this
+	// event marks the end of the handover
+	if order.Status == models.OrderStatusDelivered {
+		s.finishHandover(order)
+		return
+	}
+
 	// get the delivery partner
 	partner := s.getDeliveryPartner(order.DeliveryPartnerID)
 	if partner == nil {
@@ -970,36 +1672,33 @@ func (s *Simulator) handleDeliverOrder(order *models.Order) {
 
 	// update order status
 	order.Status = models.OrderStatusDelivered
-	order.ActualDeliveryTime = s.CurrentTime
-
-	// update delivery partner status
-	partner.Status = models.PartnerStatusAvailable
-	partner.CurrentOrderID = ""
+	order.ActualDeliveryTime = s.CurrentTime.Add(s.navigationDelay(order) + s.deliveryHandlingTime(order) + s.sampleDoorDwell(order))
+	s.recordDailyDelivery(order)
+	s.recordMetricsDelivery(order)
+	s.recordETAAccuracy(order)
+	s.recordPartnerDelivery(partner, order)
+	s.settleOrder(order, partner)
+	s.trackUnreviewed(order)
+	s.notifyDelivered(order)
+	if s.Config.PartnerRatesCustomers {
+		s.recordCustomerRating(order, user)
+	}
 
 	// generate a review event
 	s.EventQueue.Enqueue(&models.Event{
-		Time: s.CurrentTime.Add(30 * time.Minute), // Assume user leaves review after 30 minutes
+		Time: order.ActualDeliveryTime.Add(30 * time.Minute), // Assume user leaves review after 30 minutes
 		Type: models.EventGenerateReview,
 		Data: order,
 	})
+	s.scheduleComplaintCheck(order)
 
 	log.Printf("Order %s delivered to user %s at %s",
-		order.ID, user.ID, s.CurrentTime.Format(time.RFC3339))
+		order.ID, user.ID, order.ActualDeliveryTime.Format(time.RFC3339))
 
-	// ensure this event is being serialized and written
-	eventMsg, err := s.serializeEvent(models.Event{
-		Time: s.CurrentTime,
-		Type: models.EventDeliverOrder,
-		Data: order,
-	})
-	if err != nil {
-		log.Printf("Error serializing delivery event: %v", err)
-	} else {
-		output := s.determineOutputDestination()
-		if err := output.WriteMessage(eventMsg.Topic, eventMsg.Message); err != nil {
-			log.Printf("Failed to write delivery message: %v", err)
-		}
-	}
+	// the partner stays at the door until the handover is done; the delivery
+	// event is emitted then, through the queue so it is written by the
+	// order's worker after any earlier events for the same order
+	s.startHandover(partner, order)
 }
 
 func (s *Simulator) handleUpdateUserBehaviour(update *models.UserBehaviourUpdate) {
@@ -1025,61 +1724,97 @@ func (s *Simulator) handleGenerateReview(order *models.Order) {
 	// Set the ReviewGenerated flag to true
 	order.ReviewGenerated = true
 
-	log.Printf("Review generation for order %s scheduled. %.1f", order.ID)
+	log.Printf("Review generation for order %s scheduled", order.ID)
 }
 
-func (s *Simulator) Run() {
-	output := s.determineOutputDestination()
+// Run generates the initial data and simulates events until the configured end
+// date. It returns an error if the output or initial data cannot be set up, or
+// if the output fails to close. Errors serialising or writing individual events
+// are logged, counted and summarised at the end rather than stopping the run.
+func (s *Simulator) Run() (err error) {
+	output, err := s.determineOutputDestination()
+	if err != nil {
+		return err
+	}
+	s.throttle, _ = output.(*throttledOutput)
 	defer func() {
 		if closer, ok := output.(io.Closer); ok {
-			err := closer.Close()
-			if err != nil {
-				log.Printf("Error closing output: %v", err)
-				return
+			if closeErr := closer.Close(); closeErr != nil && err == nil {
+				err = fmt.Errorf("failed to close output: %w", closeErr)
 			}
 		}
 	}()
 
-	s.initializeData()
+	if err := s.writeSchemaManifest(); err != nil {
+		return err
+	}
+
+	if err := s.initializeData(); err != nil {
+		return fmt.Errorf("failed to initialise simulation data: %w", err)
+	}
 	log.Printf("Simulation starts from %s to %s\n", s.CurrentTime.Format(time.RFC3339), s.Config.EndDate.Format(time.RFC3339))
 
 	ticker := time.NewTicker(1 * time.Millisecond)
 	defer ticker.Stop()
 
-	var eventsCount int
+	var eventsCount, serializeErrors, writeErrors int
 	var eventsCountMutex sync.Mutex
-
-	// create a worker pool
-	numWorkers := runtime.NumCPU() // use number of CPUs as worker count
-	jobs := make(chan *models.Event, numWorkers)
+	dispatched := 0
+	started := time.Now()
+
+	// create a worker pool; each worker owns its own queue so that events
+	// sharing a partition key are processed and written in dequeue order
+	numWorkers := s.Config.Workers
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	workerQueues := make([]chan *models.Event, numWorkers)
 	var wg sync.WaitGroup
 
 	// start worker goroutines
 	for i := 0; i < numWorkers; i++ {
+		workerQueues[i] = make(chan *models.Event, 100)
 		wg.Add(1)
-		go func() {
+		go func(jobs <-chan *models.Event) {
 			defer wg.Done()
 			for event := range jobs {
+				s.stateMu.Lock()
 				s.processEvent(event)
 				eventMsg, err := s.serializeEvent(*event)
+				s.stateMu.Unlock()
 				if err != nil {
 					log.Printf("Error serializing event: %v", err)
+					eventsCountMutex.Lock()
+					serializeErrors++
+					eventsCountMutex.Unlock()
 					continue
 				}
-				if err := output.WriteMessage(eventMsg.Topic, eventMsg.Message); err != nil {
-					log.Printf("Failed to write message: %v", err)
+				if eventMsg.Topic == "" {
+					// the event had nothing to report by the time it was due
+					continue
+				}
+				writeErr := output.WriteMessage(eventMsg.Topic, eventMsg.Message)
+				if writeErr != nil {
+					log.Printf("Failed to write message: %v", writeErr)
 				}
 				eventsCountMutex.Lock()
 				eventsCount++
+				if writeErr != nil {
+					writeErrors++
+				}
 				eventsCountMutex.Unlock()
 			}
-		}()
+		}(workerQueues[i])
 	}
 
 	totalDuration := s.Config.EndDate.Sub(s.CurrentTime)
 	bar := progressbar.Default(100)
 
 	for s.CurrentTime.Before(s.Config.EndDate) {
+		if s.Config.MaxEvents > 0 && dispatched >= s.Config.MaxEvents {
+			log.Printf("Reached max_events (%d), stopping simulation at %s", s.Config.MaxEvents, s.CurrentTime.Format(time.RFC3339))
+			break
+		}
 		select {
 		case <-ticker.C:
 			// process any events that are due
@@ -1090,11 +1825,15 @@ func (s *Simulator) Run() {
 				}
 				batch := s.EventQueue.DequeueBatch(100)
 				for _, event := range batch {
-					jobs <- event // send event to worker pool
+					workerQueues[workerIndex(event, numWorkers)] <- event // send event to its worker
 				}
+				dispatched += len(batch)
 			}
+			s.stateMu.Lock()
+
 			// run time-step simulation
 			s.simulateTimeStep()
+			s.checkQueueDepth()
 
 			// cancel stale orders and cleanup simulation state
 			s.cancelStaleOrders()
@@ -1110,7 +1849,9 @@ func (s *Simulator) Run() {
 			bar.Set(int(progress * 100))
 
 			// advance simulation time
-			s.CurrentTime = s.CurrentTime.Add(10 * time.Minute)
+			s.CurrentTime = s.CurrentTime.Add(simulationTimeStep)
+
+			s.stateMu.Unlock()
 
 		default:
 			// if there are no events to process and no time has passed,
@@ -1118,9 +1859,27 @@ func (s *Simulator) Run() {
 			time.Sleep(1 * time.Millisecond)
 		}
 	}
-	// close the jobs channel and wait for all workers to finish
-	close(jobs)
+	// close the worker queues and wait for all workers to finish
+	for _, jobs := range workerQueues {
+		close(jobs)
+	}
 	wg.Wait()
+	if s.Config.ReviewLinksUnreviewed {
+		eventsCount += s.writeUnreviewedLinks(output)
+	}
+	if len(s.Config.DailySummaryEntities) > 0 {
+		eventsCount += s.writeOpenDailySummaries(output)
+	}
 
 	log.Printf("Simulation completed at %s\n", time.Now().UTC().Format(time.RFC3339))
+	log.Printf("Processed %d events: %d failed to serialise, %d failed to write", eventsCount+serializeErrors, serializeErrors, writeErrors)
+	elapsed := time.Since(started)
+	log.Printf("Throughput: %.0f events/sec over %s", float64(eventsCount+serializeErrors)/elapsed.Seconds(), elapsed.Round(time.Millisecond))
+	log.Printf("Peak event queue depth: %d at %s, %d events left undispatched",
+		s.queueMonitor.peak, s.queueMonitor.peakAt.Format(time.RFC3339), s.EventQueue.Len())
+	s.logAssignmentStats()
+	s.logETAAccuracy()
+	s.logSegmentRatings()
+	s.logCuisineRatings()
+	return s.writeDistanceReport()
 }
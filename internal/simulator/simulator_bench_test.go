@@ -0,0 +1,34 @@
+package simulator
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+// BenchmarkRun runs a fixed-seed simulation for a bounded number of events and
+// reports throughput, so the effect of a change on the Run/simulateTimeStep
+// path can be compared with `go test -bench Run -benchmem ./internal/simulator/`.
+func BenchmarkRun(b *testing.B) {
+	const maxEvents = 50000
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	var events int
+	var elapsed time.Duration
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		config := testConfig(b, map[string]interface{}{
+			"end_date":   "2024-03-08T00:00:00Z",
+			"max_events": maxEvents,
+		})
+		b.StartTimer()
+
+		started := time.Now()
+		events += len(runRecorded(b, config))
+		elapsed += time.Since(started)
+	}
+	b.ReportMetric(float64(events)/elapsed.Seconds(), "events/sec")
+}
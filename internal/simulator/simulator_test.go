@@ -0,0 +1,93 @@
+package simulator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// testConfig loads a small seeded simulation config, with overrides applied
+// on top, through the same loader the CLI uses
+func testConfig(tb testing.TB, overrides map[string]interface{}) *models.Config {
+	tb.Helper()
+	settings := map[string]interface{}{
+		"seed":                    42,
+		"start_date":              "2024-03-01T00:00:00Z",
+		"end_date":                "2024-03-02T00:00:00Z",
+		"initial_users":           3000,
+		"initial_restaurants":     30,
+		"initial_partners":        40,
+		"order_frequency":         0.4,
+		"city_name":               "Stoke-on-Trent",
+		"city_latitude":           53.002666,
+		"city_longitude":          -2.179404,
+		"urban_radius":            10.0,
+		"near_location_threshold": 50.0,
+		"hotspot_radius":          2.0,
+		"partner_move_speed":      40.0,
+		"location_precision":      0.0001,
+		"output_destination":      "local",
+		"output_format":           "json",
+		"workers":                 4,
+		"max_events":              3000,
+	}
+	for key, value := range overrides {
+		settings[key] = value
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	path := filepath.Join(tb.TempDir(), "config.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		tb.Fatal(err)
+	}
+	config, err := models.LoadConfig(path)
+	if err != nil {
+		tb.Fatalf("loading test config: %v", err)
+	}
+	if err := config.LoadReviewData("../../data/restaurant_reviews.tsv"); err != nil {
+		tb.Fatal(err)
+	}
+	if err := config.LoadMenuDishData("../../data/menu_dishes.csv"); err != nil {
+		tb.Fatal(err)
+	}
+	return config
+}
+
+// recordedMessage is one message written to a recordingOutput
+type recordedMessage struct {
+	topic string
+	msg   []byte
+}
+
+// recordingOutput keeps every message in the order it was written
+type recordingOutput struct {
+	mu       sync.Mutex
+	messages []recordedMessage
+}
+
+func (r *recordingOutput) WriteMessage(topic string, msg []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, recordedMessage{topic: topic, msg: append([]byte(nil), msg...)})
+	return nil
+}
+
+func (r *recordingOutput) Close() error { return nil }
+
+// runRecorded runs a simulation and returns everything it wrote
+func runRecorded(tb testing.TB, config *models.Config) []recordedMessage {
+	tb.Helper()
+	out := &recordingOutput{}
+	sim := NewSimulator(config)
+	sim.output = out
+	if err := sim.Run(); err != nil {
+		tb.Fatalf("Run: %v", err)
+	}
+	return out.messages
+}
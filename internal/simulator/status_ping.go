@@ -0,0 +1,42 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"time"
+)
+
+// pingOnStatusChange emits the partner's position as their status changes, so
+// their trajectory always has a point at assignment, pickup and delivery
+// rather than only at the scheduled pings either side
+func (s *Simulator) pingOnStatusChange(partner *models.DeliveryPartner, orderID string) {
+	if !s.Config.PartnerStatusPings {
+		return
+	}
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventUpdatePartnerLocation,
+		Data: &models.PartnerLocationUpdate{
+			PartnerID:   partner.ID,
+			OrderID:     orderID,
+			NewLocation: partner.CurrentLocation,
+			Speed:       partner.Speed,
+		},
+	})
+}
+
+// duplicatePing reports whether a location ping for the partner was already
+// emitted at this instant. Only checked with partner_status_pings, where a
+// status ping can land on the same instant as a scheduled one.
+func (s *Simulator) duplicatePing(partnerID string, at time.Time) bool {
+	if !s.Config.PartnerStatusPings {
+		return false
+	}
+	if s.lastPingAt == nil {
+		s.lastPingAt = make(map[string]time.Time)
+	}
+	if last, ok := s.lastPingAt[partnerID]; ok && last.Equal(at) {
+		return true
+	}
+	s.lastPingAt[partnerID] = at
+	return false
+}
@@ -0,0 +1,164 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"sort"
+	"time"
+)
+
+// scheduleComplaintCheck queues a check, some time after delivery, for whether
+// the customer raises a support ticket about the order
+func (s *Simulator) scheduleComplaintCheck(order *models.Order) {
+	if s.Config.ComplaintRate <= 0 {
+		return
+	}
+	delay := time.Duration(20+s.Rng.Intn(100)) * time.Minute
+	s.EventQueue.Enqueue(&models.Event{
+		Time: order.ActualDeliveryTime.Add(delay),
+		Type: models.EventComplaintCheck,
+		Data: order,
+	})
+}
+
+// handleComplaintCheck decides whether the customer complains about a
+// delivered order and, if so, queues a support ticket. Late deliveries, big or
+// fragile orders and poorly rated food all make a complaint more likely, and
+// an order that arrived spilled is always reported.
+func (s *Simulator) handleComplaintCheck(order *models.Order, at time.Time) {
+	minutesLate := math.Max(0, order.ActualDeliveryTime.Sub(order.EstimatedDeliveryTime).Minutes())
+	fragility := s.orderFragility(order)
+
+	if s.arrivedSpilled(order, fragility) {
+		s.raiseComplaint(order, models.ComplaintDamagedItem, minutesLate, at)
+		return
+	}
+
+	probability := s.Config.ComplaintRate
+	if minutesLate > 0 {
+		probability *= math.Min(1+minutesLate/15, 4)
+	}
+	if len(order.Items) > 4 {
+		probability *= 1.5
+	}
+	if multiplier := s.Config.FragilityComplaintMultiplier; multiplier > 1 {
+		probability *= 1 + fragility*(multiplier-1)
+	}
+	if review := s.findOrderReview(order); review != nil && review.FoodRating > 0 {
+		switch {
+		case review.FoodRating <= 2:
+			probability *= 3
+		case review.FoodRating >= 4.5:
+			probability *= 0.5
+		}
+	}
+	if s.Rng.Float64() >= math.Min(probability, 0.9) {
+		return
+	}
+
+	s.raiseComplaint(order, s.selectComplaintCategory(minutesLate, fragility), minutesLate, at)
+}
+
+// raiseComplaint resolves a complaint and queues its support ticket
+func (s *Simulator) raiseComplaint(order *models.Order, category string, minutesLate float64, at time.Time) {
+	resolution, amount := s.resolveComplaint(order, category)
+	if resolution == models.ResolutionRefund {
+		s.initiateRefund(order, amount, models.RefundReasonComplaint, at)
+	}
+	s.EventQueue.Enqueue(&models.Event{
+		Time: at,
+		Type: models.EventSupportTicket,
+		Data: &models.SupportTicket{
+			ID:           generateID(),
+			OrderID:      order.ID,
+			CustomerID:   order.CustomerID,
+			RestaurantID: order.RestaurantID,
+			Category:     category,
+			Resolution:   resolution,
+			Amount:       amount,
+			MinutesLate:  math.Round(minutesLate*10) / 10,
+			CreatedAt:    at,
+		},
+	})
+}
+
+// selectComplaintCategory picks what the customer complains about. Late
+// orders are more likely to be reported late or cold, and fragile ones damaged.
+func (s *Simulator) selectComplaintCategory(minutesLate, fragility float64) string {
+	weights := s.Config.ComplaintCategoryWeights()
+	categories := make([]string, 0, len(weights))
+	for category := range weights {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	adjusted := make([]float64, len(categories))
+	total := 0.0
+	for i, category := range categories {
+		adjusted[i] = weights[category]
+		if minutesLate > 10 {
+			switch category {
+			case models.ComplaintLateDelivery:
+				adjusted[i] *= 3
+			case models.ComplaintColdFood:
+				adjusted[i] *= 2
+			}
+		}
+		if category == models.ComplaintDamagedItem {
+			adjusted[i] *= 1 + fragility*3
+		}
+		total += adjusted[i]
+	}
+
+	roll := s.Rng.Float64() * total
+	for i, weight := range adjusted {
+		if roll < weight {
+			return categories[i]
+		}
+		roll -= weight
+	}
+	return categories[len(categories)-1]
+}
+
+// resolveComplaint decides how support handles a complaint. Missing and wrong
+// items are usually refunded at the price of one item, other complaints are
+// more often settled with account credit.
+func (s *Simulator) resolveComplaint(order *models.Order, category string) (string, float64) {
+	itemValue := 0.0
+	if len(order.Items) > 0 {
+		itemValue = s.calculateSubtotal(order.Items) / float64(len(order.Items))
+	}
+
+	roll := s.Rng.Float64()
+	switch category {
+	case models.ComplaintWrongItem, models.ComplaintMissingItem, models.ComplaintDamagedItem:
+		if roll < 0.65 {
+			return models.ResolutionRefund, math.Round(itemValue*100) / 100
+		}
+		if roll < 0.9 {
+			return models.ResolutionCredit, math.Round(itemValue*100) / 100
+		}
+	default:
+		if roll < 0.2 {
+			return models.ResolutionRefund, math.Round(order.DeliveryCost*100) / 100
+		}
+		if roll < 0.7 {
+			return models.ResolutionCredit, 5
+		}
+	}
+	return models.ResolutionNone, 0
+}
+
+// findOrderReview returns the review left for an order, if any. Reviews are
+// appended in time order, so the search stops at reviews older than the delivery.
+func (s *Simulator) findOrderReview(order *models.Order) *models.Review {
+	for i := len(s.Reviews) - 1; i >= 0; i-- {
+		if s.Reviews[i].CreatedAt.Before(order.ActualDeliveryTime) {
+			break
+		}
+		if s.Reviews[i].OrderID == order.ID {
+			return &s.Reviews[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// tokenBucket limits one topic to a steady rate of messages per second,
+// allowing bursts of up to a second's worth, and at least one message
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+
+	throttled int // messages delayed until a token was free
+	dropped   int // messages discarded in drop mode
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	return &tokenBucket{rate: rate, tokens: burstSize(rate), last: time.Now()}
+}
+
+// burstSize is how many tokens a bucket can hold. Rates below one a second
+// still need a whole token to send anything.
+func burstSize(rate float64) float64 {
+	return math.Max(rate, 1)
+}
+
+// reserve takes a token and returns how long to wait before sending. In drop
+// mode no token is borrowed and ok is false when none is free.
+func (b *tokenBucket) reserve(drop bool) (wait time.Duration, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if burst := burstSize(b.rate); b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	if drop {
+		b.dropped++
+		return 0, false
+	}
+	// borrow the token; later callers queue up behind this one
+	b.tokens--
+	b.throttled++
+	return time.Duration(-b.tokens / b.rate * float64(time.Second)), true
+}
+
+// ThrottleStats counts the messages a rate-limited topic held back or dropped
+type ThrottleStats struct {
+	Throttled int // Messages delayed until the rate allowed them
+	Dropped   int // Messages discarded in drop mode
+}
+
+// throttledOutput caps the rate of messages per topic sent to a streaming
+// output, either holding messages back until the rate allows or dropping them
+type throttledOutput struct {
+	next    OutputDestination
+	drop    bool
+	buckets map[string]*tokenBucket
+}
+
+// newThrottledOutput wraps out with the configured per-topic rate limits, or
+// returns it unchanged when there are none
+func newThrottledOutput(out OutputDestination, config *models.Config) OutputDestination {
+	if len(config.TopicRateLimits) == 0 {
+		return out
+	}
+	t := &throttledOutput{
+		next:    out,
+		drop:    config.ThrottleMode == models.ThrottleModeDrop,
+		buckets: make(map[string]*tokenBucket, len(config.TopicRateLimits)),
+	}
+	for topic, rate := range config.TopicRateLimits {
+		if rate > 0 {
+			t.buckets[topic] = newTokenBucket(rate)
+		}
+	}
+	return t
+}
+
+func (t *throttledOutput) WriteMessage(topic string, msg []byte) error {
+	if bucket, ok := t.buckets[topic]; ok {
+		wait, ok := bucket.reserve(t.drop)
+		if !ok {
+			return nil
+		}
+		time.Sleep(wait)
+	}
+	return t.next.WriteMessage(topic, msg)
+}
+
+func (t *throttledOutput) Close() error {
+	t.logStats()
+	return t.next.Close()
+}
+
+// Stats returns how many messages each limited topic has delayed or dropped so far
+func (t *throttledOutput) Stats() map[string]ThrottleStats {
+	stats := make(map[string]ThrottleStats, len(t.buckets))
+	for topic, bucket := range t.buckets {
+		bucket.mu.Lock()
+		stats[topic] = ThrottleStats{Throttled: bucket.throttled, Dropped: bucket.dropped}
+		bucket.mu.Unlock()
+	}
+	return stats
+}
+
+// logStats reports how many messages each limited topic delayed or dropped
+func (t *throttledOutput) logStats() {
+	stats := t.Stats()
+	topics := make([]string, 0, len(stats))
+	for topic := range stats {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	for _, topic := range topics {
+		log.Printf("Rate limit on %s (%.1f/s): %d throttled, %d dropped", topic, t.buckets[topic].rate, stats[topic].Throttled, stats[topic].Dropped)
+	}
+}
+
+// ThrottleStats returns the per-topic counts of messages held back or dropped
+// by topic_rate_limits during the last Run, or nil if no topic was limited
+func (s *Simulator) ThrottleStats() map[string]ThrottleStats {
+	if s.throttle == nil {
+		return nil
+	}
+	return s.throttle.Stats()
+}
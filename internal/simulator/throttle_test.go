@@ -0,0 +1,43 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketAllowsOneMessageBelowOnePerSecond(t *testing.T) {
+	bucket := newTokenBucket(0.5)
+	if wait, ok := bucket.reserve(true); !ok || wait != 0 {
+		t.Fatalf("first message at 0.5/s: got wait %s, ok %v; want it sent at once", wait, ok)
+	}
+
+	// a long idle spell refills the bucket to one token, not to the rate
+	bucket.last = bucket.last.Add(-time.Hour)
+	if wait, ok := bucket.reserve(true); !ok || wait != 0 {
+		t.Fatalf("message after idling: got wait %s, ok %v; want it sent at once", wait, ok)
+	}
+	if _, ok := bucket.reserve(true); ok {
+		t.Fatal("second message straight after: want it dropped")
+	}
+}
+
+func TestThrottledOutputStats(t *testing.T) {
+	out := &throttledOutput{
+		next:    discardOutput{},
+		drop:    true,
+		buckets: map[string]*tokenBucket{"orders": newTokenBucket(1)},
+	}
+	for i := 0; i < 3; i++ {
+		if err := out.WriteMessage("orders", []byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := out.Stats()["orders"]; got.Dropped != 2 || got.Throttled != 0 {
+		t.Errorf("stats = %+v, want 2 dropped", got)
+	}
+}
+
+type discardOutput struct{}
+
+func (discardOutput) WriteMessage(string, []byte) error { return nil }
+func (discardOutput) Close() error                      { return nil }
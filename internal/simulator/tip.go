@@ -0,0 +1,13 @@
+package simulator
+
+import "math"
+
+// sampleTip picks the tip a customer adds at checkout. Customers who tip give
+// between 5% and 20% of the item subtotal, rounded to the nearest 0.50.
+func (s *Simulator) sampleTip(subtotal float64) float64 {
+	if s.Config.TipProbability <= 0 || s.Rng.Float64() >= s.Config.TipProbability {
+		return 0
+	}
+	share := 0.05 + s.Rng.Float64()*0.15
+	return math.Round(subtotal*share*2) / 2
+}
@@ -0,0 +1,99 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+// maxValueDeclineRate caps how often partners turn down a low-value order
+const maxValueDeclineRate = 0.95
+
+// orderValue is what a delivery is worth to the partner: the tip plus the delivery fee
+func orderValue(order *models.Order) float64 {
+	return order.Tip + order.DeliveryCost
+}
+
+// valueDeclineRate is the chance a partner declines an order given its value.
+// With tip_priority_weight set, partners cherry-pick: orders worth more than
+// the running average are declined less often and cheaper ones more often.
+func (s *Simulator) valueDeclineRate(order *models.Order) float64 {
+	rate := s.Config.PartnerDeclineRate
+	weight := s.Config.TipPriorityWeight
+	if weight <= 0 || order == nil {
+		return rate
+	}
+
+	value := orderValue(order)
+	stats := &s.assignmentStats
+	if stats.meanValue == 0 {
+		stats.meanValue = value
+	}
+	stats.meanValue += (value - stats.meanValue) * 0.05
+
+	if value <= 0 {
+		return maxValueDeclineRate
+	}
+	rate *= math.Pow(stats.meanValue/value, weight)
+	return math.Min(rate, maxValueDeclineRate)
+}
+
+// sortByOrderValue orders indexes into s.Orders so the most valuable orders
+// are offered to partners first
+func (s *Simulator) sortByOrderValue(indexes []int) {
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return orderValue(&s.Orders[indexes[a]]) > orderValue(&s.Orders[indexes[b]])
+	})
+}
+
+// assignmentStats tracks how long ready orders wait for a partner against
+// their tip, to report how strongly tips buy faster pickups
+type assignmentStats struct {
+	meanValue float64 // running average order value offered to partners
+
+	n, sumTip, sumWait, sumTipWait, sumTip2, sumWait2 float64
+}
+
+// recordAssignmentWait notes how long an order waited for a partner once it was ready
+func (s *Simulator) recordAssignmentWait(order *models.Order) {
+	readyAt := order.PickupTime
+	if readyAt.IsZero() {
+		readyAt = order.OrderPlacedAt
+	}
+	wait := math.Max(0, s.CurrentTime.Sub(readyAt).Minutes())
+
+	stats := &s.assignmentStats
+	stats.n++
+	stats.sumTip += order.Tip
+	stats.sumWait += wait
+	stats.sumTipWait += order.Tip * wait
+	stats.sumTip2 += order.Tip * order.Tip
+	stats.sumWait2 += wait * wait
+}
+
+// tipWaitCorrelation is the Pearson correlation between tip and pickup wait,
+// or 0 when there isn't enough variation to tell
+func (a *assignmentStats) tipWaitCorrelation() float64 {
+	if a.n < 2 {
+		return 0
+	}
+	cov := a.sumTipWait - a.sumTip*a.sumWait/a.n
+	varTip := a.sumTip2 - a.sumTip*a.sumTip/a.n
+	varWait := a.sumWait2 - a.sumWait*a.sumWait/a.n
+	if varTip <= 0 || varWait <= 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varTip*varWait)
+}
+
+func (s *Simulator) logAssignmentStats() {
+	stats := &s.assignmentStats
+	if stats.n == 0 {
+		return
+	}
+	avgWait := time.Duration(stats.sumWait / stats.n * float64(time.Minute))
+	log.Printf("Partner assignment: %d orders, average wait %s once ready, tip/wait correlation %.3f",
+		int(stats.n), avgWait.Round(time.Second), stats.tipWaitCorrelation())
+}
@@ -0,0 +1,48 @@
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"strconv"
+)
+
+// orderTraceID is the trace ID shared by every event about an order. It is
+// derived from the order ID, so events that only carry the order ID, or whose
+// order has already been cleaned up, still get the same one.
+func (s *Simulator) orderTraceID(orderID string) string {
+	if !s.Config.OrderTraceIDs || orderID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strconv.Itoa(s.Config.Seed) + ":trace:" + orderID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// eventOrderID is the order an event is about, empty if it isn't about one
+func eventOrderID(event models.Event) string {
+	switch data := event.Data.(type) {
+	case *models.Order:
+		return data.ID
+	case *models.Notification:
+		return data.Order.ID
+	case *models.PrepProgress:
+		return data.Order.ID
+	case *models.PartnerGhosting:
+		return data.OrderID
+	case *models.PartnerLocationUpdate:
+		return data.OrderID
+	case *models.OrderAcceptance:
+		return data.OrderID
+	case *models.Review:
+		return data.OrderID
+	case *models.Refund:
+		return data.OrderID
+	case *models.SupportTicket:
+		return data.OrderID
+	case *models.OrderSettlement:
+		return data.OrderID
+	case *models.OrderReviewLink:
+		return data.OrderID
+	}
+	return ""
+}
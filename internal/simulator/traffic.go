@@ -0,0 +1,26 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+)
+
+const (
+	// suburbanTrafficFactor scales traffic density outside the urban area
+	suburbanTrafficFactor = 0.6
+	// minTrafficSpeedFactor stops heavy traffic bringing partners to a halt
+	minTrafficSpeedFactor = 0.2
+)
+
+// trafficSpeed is a partner's speed in km/h when setting off from loc at the
+// current time. Traffic slows partners by up to traffic_speed_impact at full
+// density, and is lighter outside the urban area. Movement and ETAs both use
+// it so that partners arrive roughly when they were expected to.
+func (s *Simulator) trafficSpeed(loc models.Location) float64 {
+	density := s.currentTrafficDensity()
+	if !s.isUrbanArea(loc) {
+		density *= suburbanTrafficFactor
+	}
+	factor := math.Max(minTrafficSpeedFactor, 1-s.Config.TrafficSpeedImpact*density)
+	return s.Config.PartnerMoveSpeed * factor
+}
@@ -0,0 +1,53 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+// meanTripTime averages estimated trip times from a to b over many draws,
+// since each estimate carries random variability
+func meanTripTime(s *Simulator, a, b models.Location) time.Duration {
+	const trips = 2000
+	var total time.Duration
+	for i := 0; i < trips; i++ {
+		total += s.estimateArrivalTime(a, b).Sub(s.CurrentTime)
+	}
+	return total / trips
+}
+
+func TestRushHourTrafficLengthensTrips(t *testing.T) {
+	config := &models.Config{
+		Seed:               3,
+		StartDate:          time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC),
+		CityLat:            53.0,
+		CityLon:            -2.18,
+		UrbanRadius:        10,
+		PartnerMoveSpeed:   30,
+		TrafficSpeedImpact: 0.5,
+	}
+	s := NewSimulator(config)
+	from := models.Location{Lat: 53.0, Lon: -2.18}
+	to := models.Location{Lat: 53.03, Lon: -2.18}
+
+	s.TrafficConditions = []models.TrafficCondition{{Density: 0.1}}
+	quiet := meanTripTime(s, from, to)
+	s.TrafficConditions = []models.TrafficCondition{{Density: 0.9}}
+	rushHour := meanTripTime(s, from, to)
+
+	// speed factors are 1-0.5*0.1 and 1-0.5*0.9, so the trip takes 0.95/0.55 as long
+	want := 0.95 / 0.55
+	if ratio := rushHour.Seconds() / quiet.Seconds(); ratio < want*0.95 || ratio > want*1.05 {
+		t.Errorf("rush-hour trip %s vs quiet %s, ratio %.2f; want about %.2f", rushHour, quiet, ratio, want)
+	}
+}
+
+func TestTrafficNeverStopsPartners(t *testing.T) {
+	s := NewSimulator(&models.Config{CityLat: 53.0, CityLon: -2.18, UrbanRadius: 10, PartnerMoveSpeed: 30, TrafficSpeedImpact: 2})
+	s.TrafficConditions = []models.TrafficCondition{{Density: 1}}
+	if got, floor := s.trafficSpeed(models.Location{Lat: 53.0, Lon: -2.18}), 30*minTrafficSpeedFactor; got != floor {
+		t.Errorf("speed in gridlock = %v km/h, want the %v km/h floor", got, floor)
+	}
+}
@@ -15,6 +15,15 @@ type BaseEvent struct {
 	UserID       string `json:"userId,omitempty" parquet:"name=userId,type=BYTE_ARRAY,convertedtype=UTF8"`
 	RestaurantID string `json:"restaurantId,omitempty" parquet:"name=restaurantId,type=BYTE_ARRAY,convertedtype=UTF8"`
 	DeliveryID   string `json:"deliveryPartnerId,omitempty" parquet:"name=deliveryPartnerId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	TraceID      string `json:"traceId,omitempty" parquet:"name=traceId,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"` // Shared by every event about the same order, set with order_trace_ids
+
+	SchemaVersion int32 `json:"schemaVersion" parquet:"name=schemaVersion,type=INT32"`
+	EventVersion  int32 `json:"eventVersion" parquet:"name=eventVersion,type=INT32"`
+
+	// conditions snapshot, set on order lifecycle events
+	Weather        *string  `json:"weather,omitempty" parquet:"name=weather,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+	Temperature    *float64 `json:"temperature,omitempty" parquet:"name=temperature,type=DOUBLE,repetitiontype=OPTIONAL"`
+	TrafficDensity *float64 `json:"trafficDensity,omitempty" parquet:"name=trafficDensity,type=DOUBLE,repetitiontype=OPTIONAL"`
 }
 
 // OrderPlacedEvent represents an order being placed
@@ -23,12 +32,44 @@ type OrderPlacedEvent struct {
 	CustomerID        string         `json:"customerId,omitempty" parquet:"name=customerId,type=BYTE_ARRAY,convertedtype=UTF8"`
 	RestaurantID      string         `json:"restaurantId,omitempty" parquet:"name=restaurantId,type=BYTE_ARRAY,convertedtype=UTF8"`
 	DeliveryPartnerID string         `json:"deliveryPartnerId,omitempty" parquet:"name=deliveryPartnerId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	RestaurantIDs     []string       `json:"restaurantIds" parquet:"name=restaurantIds,type=BYTE_ARRAY,convertedtype=UTF8"`
 	ItemIDs           []string       `json:"itemIds" parquet:"name=itemIds,type=BYTE_ARRAY,convertedtype=UTF8"`
 	TotalAmount       float64        `json:"totalAmount" parquet:"name=totalAmount,type=DOUBLE"`
 	DeliveryCost      float64        `json:"deliveryCost" parquet:"name=deliveryCost,type=DOUBLE"`
+	DistanceFee       float64        `json:"distanceFee" parquet:"name=distanceFee,type=DOUBLE"`
+	ToppedUp          bool           `json:"toppedUp" parquet:"name=toppedUp,type=BOOLEAN"`
+	Tip               float64        `json:"tip" parquet:"name=tip,type=DOUBLE"`
 	PaymentMethod     string         `json:"paymentMethod"  parquet:"name=paymentMethod,type=BYTE_ARRAY,convertedtype=UTF8"`
 	OrderPlacedAt     time.Time      `json:"orderPlacedAt" parquet:"name=orderPlacedAt,type=INT64"`
 	DeliveryAddress   models.Address `json:"deliveryAddress" parquet:"name=newLocation,type=STRUCT"`
+	Instruction       string         `json:"deliveryInstruction" parquet:"name=deliveryInstruction,type=BYTE_ARRAY,convertedtype=UTF8"`
+	DeliveryNote      string         `json:"deliveryNote" parquet:"name=deliveryNote,type=BYTE_ARRAY,convertedtype=UTF8"`
+	SchemaVersion     int32          `json:"schemaVersion" parquet:"name=schemaVersion,type=INT32"`
+	EventVersion      int32          `json:"eventVersion" parquet:"name=eventVersion,type=INT32"`
+
+	// Denormalized restaurant and user fields, set when enrich_order_events is on
+	RestaurantName     *string  `json:"restaurantName,omitempty" parquet:"name=restaurantName,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+	RestaurantCuisines []string `json:"restaurantCuisines,omitempty" parquet:"name=restaurantCuisines,type=BYTE_ARRAY,convertedtype=UTF8"`
+	UserSegment        *string  `json:"userSegment,omitempty" parquet:"name=userSegment,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+
+	// Checkout add-on suggestion; the type and item are only set when one was shown
+	UpsellShown    bool    `json:"upsellShown" parquet:"name=upsellShown,type=BOOLEAN"`
+	UpsellType     *string `json:"upsellType,omitempty" parquet:"name=upsellType,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+	UpsellItemID   *string `json:"upsellItemId,omitempty" parquet:"name=upsellItemId,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+	UpsellAccepted bool    `json:"upsellAccepted" parquet:"name=upsellAccepted,type=BOOLEAN"`
+
+	HomepageFeatured bool `json:"homepageFeatured" parquet:"name=homepageFeatured,type=BOOLEAN"` // Ground truth: the restaurant was being promoted
+	Viral            bool `json:"viral" parquet:"name=viral,type=BOOLEAN"`                       // Ground truth: the restaurant was viral
+
+	TaxRate        float64 `json:"taxRate" parquet:"name=taxRate,type=DOUBLE"`
+	TaxAmount      float64 `json:"taxAmount" parquet:"name=taxAmount,type=DOUBLE"`
+	ServiceFeeRate float64 `json:"serviceFeeRate" parquet:"name=serviceFeeRate,type=DOUBLE"`
+	ServiceFee     float64 `json:"serviceFee" parquet:"name=serviceFee,type=DOUBLE"`
+
+	TraceID string `json:"traceId,omitempty" parquet:"name=traceId,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+
+	IsReorder bool    `json:"isReorder" parquet:"name=isReorder,type=BOOLEAN"`
+	ReorderOf *string `json:"reorderOf,omitempty" parquet:"name=reorderOf,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"` // Order the customer repeated
 }
 
 // OrderPreparationEvent represents an order being prepared
@@ -40,6 +81,16 @@ type OrderPreparationEvent struct {
 	DeliveryAddress models.Address `json:"deliveryAddress" parquet:"name=newLocation,type=STRUCT"`
 }
 
+// OrderPrepProgressEvent reports how far through preparation an order is
+type OrderPrepProgressEvent struct {
+	BaseEvent
+	OrderID          string    `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Status           string    `json:"status" parquet:"name=status,type=BYTE_ARRAY,convertedtype=UTF8"`
+	PrepStartTime    time.Time `json:"prepStartTime" parquet:"name=prepStartTime,type=INT64"`
+	EstimatedReadyAt time.Time `json:"estimatedReadyAt" parquet:"name=estimatedReadyAt,type=INT64"`
+	ProgressPercent  float64   `json:"progressPercent" parquet:"name=progressPercent,type=DOUBLE"`
+}
+
 // OrderReadyEvent represents an order being ready for pickup
 type OrderReadyEvent struct {
 	BaseEvent
@@ -55,6 +106,11 @@ type DeliveryPartnerAssignmentEvent struct {
 	OrderID             string    `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
 	Status              string    `json:"status" parquet:"name=status,type=BYTE_ARRAY,convertedtype=UTF8"`
 	EstimatedPickupTime time.Time `json:"estimatedPickupTime" parquet:"name=estimatedPickupTime,type=INT64"`
+
+	// Delivery estimate; the rounded range is only set with eta_display_bucket
+	EstimatedDeliveryTime time.Time `json:"estimatedDeliveryTime" parquet:"name=estimatedDeliveryTime,type=INT64"`
+	ETAMinMinutes         *int32    `json:"etaMinMinutes,omitempty" parquet:"name=etaMinMinutes,type=INT32,repetitiontype=OPTIONAL"`
+	ETAMaxMinutes         *int32    `json:"etaMaxMinutes,omitempty" parquet:"name=etaMaxMinutes,type=INT32,repetitiontype=OPTIONAL"`
 }
 
 // OrderPickupEvent represents an order being picked up by a delivery partner
@@ -64,6 +120,10 @@ type OrderPickupEvent struct {
 	Status                string    `json:"status" parquet:"name=status,type=BYTE_ARRAY,convertedtype=UTF8"`
 	PickupTime            time.Time `json:"pickupTime" parquet:"name=pickupTime,type=INT64"`
 	EstimatedDeliveryTime time.Time `json:"estimatedDeliveryTime" parquet:"name=estimatedDeliveryTime,type=INT64"`
+
+	// Rounded delivery estimate as shown to the customer, only set with eta_display_bucket
+	ETAMinMinutes *int32 `json:"etaMinMinutes,omitempty" parquet:"name=etaMinMinutes,type=INT32,repetitiontype=OPTIONAL"`
+	ETAMaxMinutes *int32 `json:"etaMaxMinutes,omitempty" parquet:"name=etaMaxMinutes,type=INT32,repetitiontype=OPTIONAL"`
 }
 
 // PartnerLocationUpdateEvent represents an update to a delivery partner's location
@@ -77,6 +137,10 @@ type PartnerLocationUpdateEvent struct {
 	Status            string          `json:"status" parquet:"name=status,type=BYTE_ARRAY,convertedtype=BYTE_ARRAY,convertedtype=UTF8"`
 	UpdateTime        time.Time       `json:"updateTime" parquet:"name=updateTime,type=INT64"`
 	Speed             float64         `json:"speed,omitempty" parquet:"name=speed,type=DOUBLE,repetitiontype=OPTIONAL"`
+	CashOnHand        float64         `json:"cashOnHand" parquet:"name=cashOnHand,type=DOUBLE"`
+	TraceID           string          `json:"traceId,omitempty" parquet:"name=traceId,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+	SchemaVersion     int32           `json:"schemaVersion" parquet:"name=schemaVersion,type=INT32"`
+	EventVersion      int32           `json:"eventVersion" parquet:"name=eventVersion,type=INT32"`
 }
 
 // OrderInTransitEvent represents an order being in transit
@@ -139,6 +203,13 @@ type OrderDeliveryEvent struct {
 	Status                string    `json:"status" parquet:"name=status,type=BYTE_ARRAY,convertedtype=UTF8"`
 	EstimatedDeliveryTime time.Time `json:"estimatedDeliveryTime" parquet:"name=estimatedDeliveryTime,type=INT64"`
 	ActualDeliveryTime    time.Time `json:"actualDeliveryTime" parquet:"name=actualDeliveryTime,type=INT64"`
+	Instruction           string    `json:"deliveryInstruction" parquet:"name=deliveryInstruction,type=BYTE_ARRAY,convertedtype=UTF8"`
+	CustomerRating        *float64  `json:"customerRating,omitempty" parquet:"name=customerRating,type=DOUBLE,repetitiontype=OPTIONAL"`
+
+	DoorDwellSeconds float64 `json:"doorDwellSeconds" parquet:"name=doorDwellSeconds,type=DOUBLE"` // Time at the door finding it and handing over, included in actualDeliveryTime
+
+	PartnerExperienceTier string  `json:"partnerExperienceTier" parquet:"name=partnerExperienceTier,type=BYTE_ARRAY,convertedtype=UTF8"`
+	ETAErrorSeconds       float64 `json:"etaErrorSeconds" parquet:"name=etaErrorSeconds,type=DOUBLE"` // Actual minus estimated delivery time; positive when late
 }
 
 // OrderCancellationEvent represents an order being cancelled
@@ -147,6 +218,8 @@ type OrderCancellationEvent struct {
 	OrderID          string    `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
 	Status           string    `json:"status" parquet:"name=status,type=BYTE_ARRAY,convertedtype=UTF8"`
 	CancellationTime time.Time `json:"cancellationTime" parquet:"name=cancellationTime,type=INT64"`
+	CancelledBy      string    `json:"cancelledBy" parquet:"name=cancelledBy,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Reason           string    `json:"reason" parquet:"name=reason,type=BYTE_ARRAY,convertedtype=UTF8"`
 }
 
 // UserBehaviourUpdateEvent represents an update to a user's behaviour
@@ -156,15 +229,262 @@ type UserBehaviourUpdateEvent struct {
 	UserID         *string   `json:"userId" parquet:"name=userId,type=BYTE_ARRAY,convertedtype=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
 	OrderFrequency *float64  `json:"orderFrequency" parquet:"name=orderFrequency,type=DOUBLE,repetitiontype=OPTIONAL"`
 	LastOrderTime  time.Time `json:"lastOrderTime,omitempty" parquet:"name=lastOrderTime,type=INT64,repetitiontype=OPTIONAL"`
+	SchemaVersion  int32     `json:"schemaVersion" parquet:"name=schemaVersion,type=INT32"`
+	EventVersion   int32     `json:"eventVersion" parquet:"name=eventVersion,type=INT32"`
 }
 
 // RestaurantStatusUpdateEvent represents an update to a restaurant's status
 type RestaurantStatusUpdateEvent struct {
 	BaseEvent
-	Capacity        int32   `json:"capacity" parquet:"name=capacity,type=INT32"`
-	CurrentCapacity int32   `json:"current_capacity" parquet:"name=current_capacity,type=INT32"`
-	OrdersInQueue   int32   `json:"orders_in_queue" parquet:"name=orders_in_queue,type=INT32"`
-	PrepTime        float64 `json:"prep_time" parquet:"name=prep_time,type=DOUBLE"`
+	Capacity        int32    `json:"capacity" parquet:"name=capacity,type=INT32"`
+	CurrentCapacity int32    `json:"current_capacity" parquet:"name=current_capacity,type=INT32"`
+	BaseCapacity    int32    `json:"base_capacity" parquet:"name=base_capacity,type=INT32"`
+	OrdersInQueue   int32    `json:"orders_in_queue" parquet:"name=orders_in_queue,type=INT32"`
+	PrepTime        float64  `json:"prep_time" parquet:"name=prep_time,type=DOUBLE"`
+	AvgPrepTime     float64  `json:"avg_prep_time" parquet:"name=avg_prep_time,type=DOUBLE"`
+	Degraded        bool     `json:"degraded" parquet:"name=degraded,type=BOOLEAN"`
+	KitchenIncident string   `json:"kitchen_incident,omitempty" parquet:"name=kitchen_incident,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+	PaymentMethods  []string `json:"accepted_payment_methods" parquet:"name=accepted_payment_methods,type=BYTE_ARRAY,convertedtype=UTF8"`
+	BadActor        bool     `json:"bad_actor" parquet:"name=bad_actor,type=BOOLEAN"`
+	Reliability     float64  `json:"reliability" parquet:"name=reliability,type=DOUBLE"`
+}
+
+// RestaurantMetricsEvent represents a periodic snapshot of a restaurant's order performance
+type RestaurantMetricsEvent struct {
+	BaseEvent
+	WindowStart       time.Time `json:"windowStart" parquet:"name=windowStart,type=INT64"`
+	WindowEnd         time.Time `json:"windowEnd" parquet:"name=windowEnd,type=INT64"`
+	OrdersPlaced      int32     `json:"ordersPlaced" parquet:"name=ordersPlaced,type=INT32"`
+	OrdersCompleted   int32     `json:"ordersCompleted" parquet:"name=ordersCompleted,type=INT32"`
+	OrdersCancelled   int32     `json:"ordersCancelled" parquet:"name=ordersCancelled,type=INT32"`
+	CompletionRate    float64   `json:"completionRate" parquet:"name=completionRate,type=DOUBLE"`
+	EstimatedPrepTime float64   `json:"estimatedPrepTime" parquet:"name=estimatedPrepTime,type=DOUBLE"`
+	ActualPrepTime    float64   `json:"actualPrepTime" parquet:"name=actualPrepTime,type=DOUBLE"`
+	LateOrders        int32     `json:"lateOrders" parquet:"name=lateOrders,type=INT32"`
+	EarlyOrders       int32     `json:"earlyOrders" parquet:"name=earlyOrders,type=INT32"`
+	PickupEfficiency  float64   `json:"pickupEfficiency" parquet:"name=pickupEfficiency,type=DOUBLE"`
+}
+
+// PartnerShiftSummaryEvent summarises a delivery partner's activity over a
+// shift. Summary fields not listed in partner_shift_summary_fields are omitted.
+type PartnerShiftSummaryEvent struct {
+	BaseEvent
+	ShiftStart     time.Time `json:"shiftStart" parquet:"name=shiftStart,type=INT64"`
+	ShiftEnd       time.Time `json:"shiftEnd" parquet:"name=shiftEnd,type=INT64"`
+	Offers         *int32    `json:"offers,omitempty" parquet:"name=offers,type=INT32,repetitiontype=OPTIONAL"`
+	Declined       *int32    `json:"declined,omitempty" parquet:"name=declined,type=INT32,repetitiontype=OPTIONAL"`
+	AcceptanceRate *float64  `json:"acceptanceRate,omitempty" parquet:"name=acceptanceRate,type=DOUBLE,repetitiontype=OPTIONAL"`
+	Deliveries     *int32    `json:"deliveries,omitempty" parquet:"name=deliveries,type=INT32,repetitiontype=OPTIONAL"`
+	IdleMinutes    *float64  `json:"idleMinutes,omitempty" parquet:"name=idleMinutes,type=DOUBLE,repetitiontype=OPTIONAL"`
+	DistanceKm     *float64  `json:"distanceKm,omitempty" parquet:"name=distanceKm,type=DOUBLE,repetitiontype=OPTIONAL"`
+	Experience     *float64  `json:"experience,omitempty" parquet:"name=experience,type=DOUBLE,repetitiontype=OPTIONAL"`
+
+	HomeToFirstPickupKm *float64 `json:"homeToFirstPickupKm,omitempty" parquet:"name=homeToFirstPickupKm,type=DOUBLE,repetitiontype=OPTIONAL"`
+}
+
+// AbandonedCartEvent represents a basket that was built but never checked out
+type AbandonedCartEvent struct {
+	BaseEvent
+	CartID      string   `json:"cartId" parquet:"name=cartId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	ItemIDs     []string `json:"itemIds" parquet:"name=itemIds,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Subtotal    float64  `json:"subtotal" parquet:"name=subtotal,type=DOUBLE"`
+	DeliveryFee float64  `json:"deliveryFee" parquet:"name=deliveryFee,type=DOUBLE"`
+	Reason      string   `json:"reason" parquet:"name=reason,type=BYTE_ARRAY,convertedtype=UTF8"`
+}
+
+// FeaturedDishEvent marks a restaurant starting or stopping promotion of a dish
+type FeaturedDishEvent struct {
+	BaseEvent
+	MenuItemID    string    `json:"menuItemId" parquet:"name=menuItemId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	FeaturedUntil time.Time `json:"featuredUntil" parquet:"name=featuredUntil,type=INT64"`
+}
+
+// WeatherObservationEvent is a periodic city-wide weather reading
+type WeatherObservationEvent struct {
+	BaseEvent
+	Condition     string  `json:"condition" parquet:"name=condition,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Temperature   float64 `json:"temperature" parquet:"name=temperature,type=DOUBLE"`
+	WindSpeed     float64 `json:"windSpeed" parquet:"name=windSpeed,type=DOUBLE"`
+	Humidity      float64 `json:"humidity" parquet:"name=humidity,type=DOUBLE"`
+	Precipitation float64 `json:"precipitation" parquet:"name=precipitation,type=DOUBLE"`
+	Region        *string `json:"region,omitempty" parquet:"name=region,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+}
+
+// SupportTicketEvent is a customer complaint about a delivered order and how it was resolved
+type SupportTicketEvent struct {
+	BaseEvent
+	TicketID    string  `json:"ticketId" parquet:"name=ticketId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	OrderID     string  `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Category    string  `json:"category" parquet:"name=category,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Resolution  string  `json:"resolution" parquet:"name=resolution,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Amount      float64 `json:"amount" parquet:"name=amount,type=DOUBLE"`
+	MinutesLate float64 `json:"minutesLate" parquet:"name=minutesLate,type=DOUBLE"`
+}
+
+// DailySummaryEvent aggregates a restaurant's or partner's activity over a local calendar day
+type DailySummaryEvent struct {
+	BaseEvent
+	Date           string   `json:"date" parquet:"name=date,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Orders         int32    `json:"orders" parquet:"name=orders,type=INT32"`
+	Completed      int32    `json:"completed" parquet:"name=completed,type=INT32"`
+	Cancelled      int32    `json:"cancelled" parquet:"name=cancelled,type=INT32"`
+	CompletionRate float64  `json:"completionRate" parquet:"name=completionRate,type=DOUBLE"`
+	LateDeliveries int32    `json:"lateDeliveries" parquet:"name=lateDeliveries,type=INT32"`
+	Revenue        float64  `json:"revenue" parquet:"name=revenue,type=DOUBLE"`
+	AvgRating      *float64 `json:"avgRating,omitempty" parquet:"name=avgRating,type=DOUBLE,repetitiontype=OPTIONAL"`
+	Ratings        int32    `json:"ratings" parquet:"name=ratings,type=INT32"`
+	Partial        bool     `json:"partial" parquet:"name=partial,type=BOOLEAN"` // The simulation ended before the day did
+}
+
+// PartnerGhostingEvent records a partner abandoning an order they accepted, before pickup
+type PartnerGhostingEvent struct {
+	BaseEvent
+	OrderID         string    `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	AssignedAt      time.Time `json:"assignedAt" parquet:"name=assignedAt,type=INT64"`
+	MinutesAssigned float64   `json:"minutesAssigned" parquet:"name=minutesAssigned,type=DOUBLE"`
+	Ghostings       int32     `json:"ghostings" parquet:"name=ghostings,type=INT32"`
+	Reliability     float64   `json:"reliability" parquet:"name=reliability,type=DOUBLE"`
+}
+
+// PlatformOutageEvent marks the start or end of a platform-wide outage
+type PlatformOutageEvent struct {
+	BaseEvent
+	OutageID     string    `json:"outageId" parquet:"name=outageId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Reason       string    `json:"reason" parquet:"name=reason,type=BYTE_ARRAY,convertedtype=UTF8"`
+	StartedAt    time.Time `json:"startedAt" parquet:"name=startedAt,type=INT64"`
+	EndsAt       time.Time `json:"endsAt" parquet:"name=endsAt,type=INT64"`
+	MissedOrders int32     `json:"missedOrders" parquet:"name=missedOrders,type=INT32"` // Set on the end marker
+	RetryOrders  int32     `json:"retryOrders" parquet:"name=retryOrders,type=INT32"`   // Missed orders that will be placed again, set on the end marker
+}
+
+// OrderAcceptanceEvent records a restaurant accepting a new order, or the
+// platform auto-rejecting it once the acceptance SLA has passed
+type OrderAcceptanceEvent struct {
+	BaseEvent
+	OrderID        string    `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Status         string    `json:"status" parquet:"name=status,type=BYTE_ARRAY,convertedtype=UTF8"`
+	LatencySeconds float64   `json:"latencySeconds" parquet:"name=latencySeconds,type=DOUBLE"` // Time from placement to acceptance, or to rejection when the SLA was breached
+	SLASeconds     float64   `json:"slaSeconds" parquet:"name=slaSeconds,type=DOUBLE"`
+	SLABreached    bool      `json:"slaBreached" parquet:"name=slaBreached,type=BOOLEAN"`
+	PlacedAt       time.Time `json:"placedAt" parquet:"name=placedAt,type=INT64"`
+}
+
+// HomepageFeatureEvent marks the platform starting or stopping promotion of a restaurant
+type HomepageFeatureEvent struct {
+	BaseEvent
+	FeaturedFrom  time.Time `json:"featuredFrom" parquet:"name=featuredFrom,type=INT64"`
+	FeaturedUntil time.Time `json:"featuredUntil" parquet:"name=featuredUntil,type=INT64"`
+	Boost         float64   `json:"boost" parquet:"name=boost,type=DOUBLE"`
+	Scheduled     bool      `json:"scheduled" parquet:"name=scheduled,type=BOOLEAN"`
+}
+
+// RestaurantViralEvent marks a restaurant going viral, the ground truth behind
+// the demand spike that follows
+type RestaurantViralEvent struct {
+	BaseEvent
+	Magnitude     float64 `json:"magnitude" parquet:"name=magnitude,type=DOUBLE"`         // Peak selection score multiplier
+	HalfLifeHours float64 `json:"halfLifeHours" parquet:"name=halfLifeHours,type=DOUBLE"` // Hours for the extra demand to halve
+}
+
+// PartnerCashDropEvent records a partner handing in the cash they collected
+type PartnerCashDropEvent struct {
+	BaseEvent
+	Amount float64   `json:"amount" parquet:"name=amount,type=DOUBLE"`
+	Limit  float64   `json:"limit" parquet:"name=limit,type=DOUBLE"`
+	BackAt time.Time `json:"backAt" parquet:"name=backAt,type=INT64"`
+}
+
+// PartnerDualAppEvent records a partner going offline to work a job from
+// another delivery app
+type PartnerDualAppEvent struct {
+	BaseEvent
+	Reason string    `json:"reason" parquet:"name=reason,type=BYTE_ARRAY,convertedtype=UTF8"`
+	BackAt time.Time `json:"backAt" parquet:"name=backAt,type=INT64"`
+}
+
+// RestaurantHoursEvent records a restaurant going offline mid-day or reopening
+type RestaurantHoursEvent struct {
+	BaseEvent
+	Status     string    `json:"status" parquet:"name=status,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Reason     string    `json:"reason,omitempty" parquet:"name=reason,type=BYTE_ARRAY,convertedtype=UTF8"`
+	OpenAt     time.Time `json:"openAt" parquet:"name=openAt,type=INT64"` // Expected reopening when going offline, the reopening itself otherwise
+	OpenOrders int32     `json:"openOrders" parquet:"name=openOrders,type=INT32"`
+}
+
+// RatingRecomputedEvent is a restaurant's or partner's rating after it was
+// rebuilt from all of its reviews
+type RatingRecomputedEvent struct {
+	BaseEvent
+	PreviousRating float64 `json:"previousRating" parquet:"name=previousRating,type=DOUBLE"`
+	Rating         float64 `json:"rating" parquet:"name=rating,type=DOUBLE"`
+	Correction     float64 `json:"correction" parquet:"name=correction,type=DOUBLE"`
+	Reviews        int32   `json:"reviews" parquet:"name=reviews,type=INT32"`
+}
+
+// NotificationEvent is a message sent to a user about one of their orders
+type NotificationEvent struct {
+	BaseEvent
+	NotificationID   string `json:"notificationId" parquet:"name=notificationId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	OrderID          string `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	NotificationType string `json:"notificationType" parquet:"name=notificationType,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Channel          string `json:"channel" parquet:"name=channel,type=BYTE_ARRAY,convertedtype=UTF8"`
+}
+
+// PartnerPayoutEvent is what a partner earned over a shift
+type PartnerPayoutEvent struct {
+	BaseEvent
+	PayModel       string    `json:"payModel" parquet:"name=payModel,type=BYTE_ARRAY,convertedtype=UTF8"`
+	ShiftStart     time.Time `json:"shiftStart" parquet:"name=shiftStart,type=INT64"`
+	ShiftEnd       time.Time `json:"shiftEnd" parquet:"name=shiftEnd,type=INT64"`
+	OnlineMinutes  float64   `json:"onlineMinutes" parquet:"name=onlineMinutes,type=DOUBLE"`
+	Deliveries     int32     `json:"deliveries" parquet:"name=deliveries,type=INT32"`
+	DeliveryPay    float64   `json:"deliveryPay" parquet:"name=deliveryPay,type=DOUBLE"`
+	HourlyPay      float64   `json:"hourlyPay" parquet:"name=hourlyPay,type=DOUBLE"`
+	GuaranteeTopUp float64   `json:"guaranteeTopUp" parquet:"name=guaranteeTopUp,type=DOUBLE"`
+	Tips           float64   `json:"tips" parquet:"name=tips,type=DOUBLE"`
+	Total          float64   `json:"total" parquet:"name=total,type=DOUBLE"`
+}
+
+// RefundEvent tracks a refund from initiation to settlement; completed events
+// are emitted at the settlement time
+type RefundEvent struct {
+	BaseEvent
+	RefundID      string    `json:"refundId" parquet:"name=refundId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	OrderID       string    `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Status        string    `json:"status" parquet:"name=status,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Reason        string    `json:"reason" parquet:"name=reason,type=BYTE_ARRAY,convertedtype=UTF8"`
+	PaymentMethod string    `json:"paymentMethod" parquet:"name=paymentMethod,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Amount        float64   `json:"amount" parquet:"name=amount,type=DOUBLE"`
+	InitiatedAt   time.Time `json:"initiatedAt" parquet:"name=initiatedAt,type=INT64"`
+	SettlesAt     time.Time `json:"settlesAt" parquet:"name=settlesAt,type=INT64"`
+}
+
+// OrderSettlementEvent splits a delivered order's total and tip between the
+// restaurant, the partner, tax and the platform
+type OrderSettlementEvent struct {
+	BaseEvent
+	OrderID          string  `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	Subtotal         float64 `json:"subtotal" parquet:"name=subtotal,type=DOUBLE"`
+	Discount         float64 `json:"discount" parquet:"name=discount,type=DOUBLE"`
+	Tax              float64 `json:"tax" parquet:"name=tax,type=DOUBLE"`
+	ServiceFee       float64 `json:"serviceFee" parquet:"name=serviceFee,type=DOUBLE"`
+	DeliveryFee      float64 `json:"deliveryFee" parquet:"name=deliveryFee,type=DOUBLE"`
+	Tip              float64 `json:"tip" parquet:"name=tip,type=DOUBLE"`
+	TotalAmount      float64 `json:"totalAmount" parquet:"name=totalAmount,type=DOUBLE"`
+	CommissionRate   float64 `json:"commissionRate" parquet:"name=commissionRate,type=DOUBLE"`
+	Commission       float64 `json:"commission" parquet:"name=commission,type=DOUBLE"`
+	RestaurantPayout float64 `json:"restaurantPayout" parquet:"name=restaurantPayout,type=DOUBLE"`
+	PartnerPay       float64 `json:"partnerPay" parquet:"name=partnerPay,type=DOUBLE"`
+	PlatformNet      float64 `json:"platformNet" parquet:"name=platformNet,type=DOUBLE"`
+}
+
+// PartnerDeactivationEvent records the platform deactivating a low-rated partner
+type PartnerDeactivationEvent struct {
+	BaseEvent
+	Rating          float64  `json:"rating" parquet:"name=rating,type=DOUBLE"`
+	TotalRatings    int32    `json:"totalRatings" parquet:"name=totalRatings,type=INT32"`
+	Threshold       float64  `json:"threshold" parquet:"name=threshold,type=DOUBLE"`
+	HandedOffOrders []string `json:"handedOffOrders" parquet:"name=handedOffOrders,type=BYTE_ARRAY,convertedtype=UTF8"`
 }
 
 // ReviewEvent represents a review being generated
@@ -174,13 +494,28 @@ type ReviewEvent struct {
 	OrderID           string    `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
 	CustomerID        string    `json:"customerId" parquet:"name=customerId,type=BYTE_ARRAY,convertedtype=UTF8"`
 	DeliveryPartnerID string    `json:"deliveryPartnerId" parquet:"name=deliveryPartnerId,type=BYTE_ARRAY,convertedtype=UTF8"`
-	FoodRating        float64   `json:"foodRating" parquet:"name=foodRating,type=DOUBLE"`
-	DeliveryRating    float64   `json:"deliveryRating" parquet:"name=deliveryRating,type=DOUBLE"`
+	FoodRating        *float64  `json:"foodRating" parquet:"name=foodRating,type=DOUBLE,repetitiontype=OPTIONAL"`         // nil when the customer only rated the delivery
+	DeliveryRating    *float64  `json:"deliveryRating" parquet:"name=deliveryRating,type=DOUBLE,repetitiontype=OPTIONAL"` // nil when the customer only rated the food
 	OverallRating     float64   `json:"overallRating" parquet:"name=overallRating,type=DOUBLE"`
 	Comment           string    `json:"comment" parquet:"name=comment,type=BYTE_ARRAY,convertedtype=UTF8"`
 	CreatedAt         time.Time `json:"createdAt" parquet:"name=createdAt,type=INT64"`
+	UpdatedAt         time.Time `json:"updatedAt" parquet:"name=updatedAt,type=INT64"`
 	OrderTotal        float64   `json:"orderTotal" parquet:"name=orderTotal,type=DOUBLE"`
 	DeliveryTime      int64     `json:"deliveryTime" parquet:"name=deliveryTime,type=INT64"`
+	RestaurantReplied bool      `json:"restaurantReplied" parquet:"name=restaurantReplied,type=BOOLEAN"`
+
+	ItemRatingIDs []string  `json:"itemRatingIds,omitempty" parquet:"name=itemRatingIds,type=BYTE_ARRAY,convertedtype=UTF8"` // Dishes rated individually
+	ItemRatings   []float64 `json:"itemRatings,omitempty" parquet:"name=itemRatings,type=DOUBLE"`                            // Rating of each dish in itemRatingIds
+}
+
+// OrderReviewLinkEvent ties a delivered order to its review, with a null
+// reviewId for orders never reviewed
+type OrderReviewLinkEvent struct {
+	BaseEvent
+	OrderID     string     `json:"orderId" parquet:"name=orderId,type=BYTE_ARRAY,convertedtype=UTF8"`
+	ReviewID    *string    `json:"reviewId" parquet:"name=reviewId,type=BYTE_ARRAY,convertedtype=UTF8,repetitiontype=OPTIONAL"`
+	DeliveredAt time.Time  `json:"deliveredAt" parquet:"name=deliveredAt,type=INT64"`
+	ReviewedAt  *time.Time `json:"reviewedAt" parquet:"name=reviewedAt,type=INT64,repetitiontype=OPTIONAL"`
 }
 
 func GetSchema(eventType string) (*schema.SchemaHandler, error) {
@@ -214,6 +549,52 @@ func GetSchema(eventType string) (*schema.SchemaHandler, error) {
 		sh, err = schema.NewSchemaHandlerFromStruct(new(RestaurantStatusUpdateEvent))
 	case "review_events":
 		sh, err = schema.NewSchemaHandlerFromStruct(new(ReviewEvent))
+	case "restaurant_metrics_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(RestaurantMetricsEvent))
+	case "delivery_partner_shift_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(PartnerShiftSummaryEvent))
+	case "order_prep_progress_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(OrderPrepProgressEvent))
+	case "restaurant_menu_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(FeaturedDishEvent))
+	case "weather_observation_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(WeatherObservationEvent))
+	case "abandoned_cart_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(AbandonedCartEvent))
+	case "support_ticket_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(SupportTicketEvent))
+	case "delivery_partner_ghosting_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(PartnerGhostingEvent))
+	case "notification_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(NotificationEvent))
+	case "delivery_partner_payout_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(PartnerPayoutEvent))
+	case "refund_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(RefundEvent))
+	case "order_settlement_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(OrderSettlementEvent))
+	case "restaurant_virality_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(RestaurantViralEvent))
+	case "order_review_link_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(OrderReviewLinkEvent))
+	case "delivery_partner_deactivation_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(PartnerDeactivationEvent))
+	case "restaurant_daily_summary_events", "partner_daily_summary_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(DailySummaryEvent))
+	case "platform_outage_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(PlatformOutageEvent))
+	case "order_acceptance_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(OrderAcceptanceEvent))
+	case "restaurant_promotion_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(HomepageFeatureEvent))
+	case "delivery_partner_cash_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(PartnerCashDropEvent))
+	case "delivery_partner_absence_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(PartnerDualAppEvent))
+	case "restaurant_hours_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(RestaurantHoursEvent))
+	case "restaurant_rating_events", "partner_rating_events":
+		sh, err = schema.NewSchemaHandlerFromStruct(new(RatingRecomputedEvent))
 	default:
 		return nil, fmt.Errorf("unknown event type: %s", eventType)
 	}
@@ -229,7 +610,9 @@ func GetSchema(eventType string) (*schema.SchemaHandler, error) {
 
 func NewBaseEvent(eventType string, timestamp time.Time) BaseEvent {
 	return BaseEvent{
-		Timestamp: timestamp.Unix(),
-		EventType: eventType,
+		Timestamp:     timestamp.Unix(),
+		EventType:     eventType,
+		SchemaVersion: SchemaVersion,
+		EventVersion:  EventVersion(eventType),
 	}
 }
@@ -0,0 +1,68 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"sort"
+)
+
+// offerUpsell suggests an add-on at checkout for upsell_rate of baskets: the
+// restaurant's most popular suitable item of a configured type the basket
+// doesn't already have. The customer takes it at the type's acceptance rate,
+// scaled for their segment, and an accepted add-on is added to the basket.
+func (s *Simulator) offerUpsell(restaurant *models.Restaurant, user *models.User, items []string) ([]string, *models.Upsell) {
+	if s.Config.UpsellRate <= 0 || s.Rng.Float64() >= s.Config.UpsellRate {
+		return items, nil
+	}
+
+	inBasket := make(map[string]bool, len(items))
+	for _, id := range items {
+		if item := s.getMenuItem(id); item != nil {
+			inBasket[item.Type] = true
+		}
+	}
+
+	rates := s.Config.UpsellAcceptanceRates()
+	types := make([]string, 0, len(rates))
+	for itemType := range rates {
+		if !inBasket[itemType] {
+			types = append(types, itemType)
+		}
+	}
+	sort.Strings(types)
+	s.Rng.Shuffle(len(types), func(i, j int) { types[i], types[j] = types[j], types[i] })
+
+	for _, itemType := range types {
+		suggestion := s.upsellCandidate(restaurant, user, itemType)
+		if suggestion == nil {
+			continue
+		}
+		acceptance := math.Min(1, rates[itemType]*s.Config.UpsellSegmentMultiplier(s.userSegment(user)))
+		upsell := &models.Upsell{
+			Type:     itemType,
+			ItemID:   suggestion.ID,
+			Accepted: s.Rng.Float64() < acceptance,
+		}
+		if upsell.Accepted {
+			items = append(items, suggestion.ID)
+		}
+		return items, upsell
+	}
+	return items, nil
+}
+
+// upsellCandidate is the restaurant's most popular item of itemType the user
+// can eat, or nil if it has none
+func (s *Simulator) upsellCandidate(restaurant *models.Restaurant, user *models.User, itemType string) *models.MenuItem {
+	var best *models.MenuItem
+	for _, id := range restaurant.MenuItems {
+		item := s.getMenuItem(id)
+		if item == nil || item.Type != itemType || s.hasConflictingIngredients(item, user.DietaryRestrictions) {
+			continue
+		}
+		if best == nil || item.Popularity*s.featuredDishBoost(restaurant, item) > best.Popularity*s.featuredDishBoost(restaurant, best) {
+			best = item
+		}
+	}
+	return best
+}
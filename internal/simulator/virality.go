@@ -0,0 +1,95 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// viralityFadeShare is the share of its peak extra demand below which a
+	// viral spike is over
+	viralityFadeShare = 0.05
+
+	// viralBuzzRating and viralOverloadRating shift the food rating of orders
+	// placed during a spike: buzz when the order still arrived on time,
+	// disappointment when the rush made it late
+	viralBuzzRating     = 0.3
+	viralOverloadRating = -0.6
+)
+
+// viralityBoost is the selection score multiplier a restaurant has from going
+// viral, decaying from virality_magnitude to 1; 1 if it isn't viral
+func (s *Simulator) viralityBoost(restaurant *models.Restaurant) float64 {
+	if restaurant.ViralFrom.IsZero() || s.Config.ViralityHalfLife <= 0 {
+		return 1
+	}
+	elapsed := s.CurrentTime.Sub(restaurant.ViralFrom)
+	if elapsed < 0 {
+		return 1
+	}
+	decay := math.Pow(0.5, float64(elapsed)/float64(s.Config.ViralityHalfLife))
+	return 1 + (s.Config.ViralityMagnitude-1)*decay
+}
+
+// viral reports whether the restaurant is in a viral spike right now
+func (s *Simulator) viral(restaurant *models.Restaurant) bool {
+	return !restaurant.ViralFrom.IsZero()
+}
+
+// updateVirality ends spikes that have faded and, at virality_rate per day,
+// sends a random restaurant viral
+func (s *Simulator) updateVirality() {
+	if s.Config.ViralityRate <= 0 || len(s.Restaurants) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(s.Restaurants))
+	for id := range s.Restaurants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	fadedBelow := 1 + (s.Config.ViralityMagnitude-1)*viralityFadeShare
+	for _, id := range ids {
+		restaurant := s.Restaurants[id]
+		if s.viral(restaurant) && s.viralityBoost(restaurant) < fadedBelow {
+			log.Printf("Restaurant %s is no longer viral", restaurant.ID)
+			restaurant.ViralFrom = time.Time{}
+		}
+	}
+
+	if s.Rng.Float64() >= s.Config.ViralityRate*simulationTimeStep.Hours()/24 {
+		return
+	}
+	restaurant := s.Restaurants[ids[s.Rng.Intn(len(ids))]]
+	if s.viral(restaurant) || restaurantOffline(restaurant) {
+		return
+	}
+	restaurant.ViralFrom = s.CurrentTime
+	log.Printf("Restaurant %s went viral", restaurant.ID)
+	s.EventQueue.Enqueue(&models.Event{
+		Time: s.CurrentTime,
+		Type: models.EventRestaurantViral,
+		Data: &models.ViralSpike{
+			RestaurantID: restaurant.ID,
+			Start:        s.CurrentTime,
+			Magnitude:    s.Config.ViralityMagnitude,
+			HalfLife:     s.Config.ViralityHalfLife,
+		},
+	})
+}
+
+// viralRatingShift is the stars added to the food rating of an order placed
+// during a viral spike: buzz if it arrived on time, disappointment if the
+// rush made it late
+func viralRatingShift(order *models.Order) float64 {
+	if !order.Viral {
+		return 0
+	}
+	if order.ActualDeliveryTime.After(order.EstimatedDeliveryTime) {
+		return viralOverloadRating
+	}
+	return viralBuzzRating
+}
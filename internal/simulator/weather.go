@@ -0,0 +1,180 @@
+package simulator
+
+import (
+	"github.com/chrisdamba/foodatasim/internal/models"
+	"math"
+	"time"
+)
+
+// weatherChangesPerDay is how often, on average, the weather condition changes
+const weatherChangesPerDay = 4.0
+
+// updateWeather advances the city-wide weather, and that of each weather
+// region, by one time step. The condition changes a few times a day, and
+// temperature follows the season and time of day.
+func (s *Simulator) updateWeather() {
+	s.advanceWeather(&s.Weather, models.WeatherRegion{})
+
+	regions := s.Config.WeatherRegions
+	if len(s.RegionWeather) != len(regions) {
+		s.RegionWeather = make([]models.WeatherCondition, len(regions))
+		for i, region := range regions {
+			s.RegionWeather[i].Region = region.Name
+		}
+	}
+	// regions change independently, so a storm can hit one while another stays clear
+	for i, region := range regions {
+		s.advanceWeather(&s.RegionWeather[i], region)
+	}
+}
+
+func (s *Simulator) advanceWeather(weather *models.WeatherCondition, region models.WeatherRegion) {
+	stepDays := simulationTimeStep.Hours() / 24
+	if weather.Condition == "" || s.Rng.Float64() < weatherChangesPerDay*stepDays {
+		weather.Condition = s.sampleWeatherCondition(s.CurrentTime, region.RainChanceOffset)
+	}
+	weather.Temperature = s.sampleTemperature(s.CurrentTime, weather.Condition) + region.TemperatureOffset
+
+	// rain falls as snow when it is cold enough
+	if weather.Condition == models.WeatherRain && weather.Temperature < 1 {
+		weather.Condition = models.WeatherSnow
+	} else if weather.Condition == models.WeatherSnow && weather.Temperature > 3 {
+		weather.Condition = models.WeatherRain
+	}
+
+	s.sampleAtmosphere(weather)
+}
+
+// getCurrentWeather is the weather at a location: that of the nearest
+// weather region, or the city-wide weather if no regions are configured
+func (s *Simulator) getCurrentWeather(loc models.Location) models.WeatherCondition {
+	if len(s.RegionWeather) == 0 {
+		return s.Weather
+	}
+	nearest := 0
+	nearestDistance := math.Inf(1)
+	for i, region := range s.Config.WeatherRegions {
+		if i >= len(s.RegionWeather) {
+			break
+		}
+		if distance := s.calculateDistance(loc, region.Location); distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+	return s.RegionWeather[nearest]
+}
+
+// sampleAtmosphere fills in wind, humidity and precipitation to match the
+// weather condition
+func (s *Simulator) sampleAtmosphere(weather *models.WeatherCondition) {
+	wind, humidity, precipitation := 8.0, 60.0, 0.0
+	switch weather.Condition {
+	case models.WeatherCloudy:
+		wind, humidity = 12, 72
+	case models.WeatherRain:
+		wind, humidity = 18, 88
+		precipitation = 0.5 + s.Rng.ExpFloat64()*1.5
+	case models.WeatherSnow:
+		wind, humidity = 15, 85
+		precipitation = 0.2 + s.Rng.ExpFloat64()*0.8
+	}
+	weather.WindSpeed = math.Round(math.Max(0, wind+s.Rng.NormFloat64()*wind/3)*10) / 10
+	weather.Humidity = math.Round(math.Max(20, math.Min(100, humidity+s.Rng.NormFloat64()*8)))
+	weather.Precipitation = math.Round(precipitation*10) / 10
+}
+
+// scheduleWeatherObservations queues a full weather observation, one per
+// weather region if any are configured, each time the observation interval
+// elapses, regardless of order activity
+func (s *Simulator) scheduleWeatherObservations() {
+	interval := s.Config.WeatherObservationInterval
+	if interval <= 0 {
+		return
+	}
+	if !s.lastWeatherObservationAt.IsZero() && s.CurrentTime.Sub(s.lastWeatherObservationAt) < interval {
+		return
+	}
+	observations := s.RegionWeather
+	if len(observations) == 0 {
+		observations = []models.WeatherCondition{s.Weather}
+	}
+	for _, weather := range observations {
+		observation := weather
+		s.EventQueue.Enqueue(&models.Event{
+			Time: s.CurrentTime,
+			Type: models.EventWeatherObservation,
+			Data: &observation,
+		})
+	}
+	s.lastWeatherObservationAt = s.CurrentTime
+}
+
+func (s *Simulator) sampleWeatherCondition(t time.Time, rainChanceOffset float64) string {
+	// wetter in autumn and winter
+	rainChance := 0.2
+	switch s.localTime(t).Month() {
+	case time.October, time.November, time.December, time.January, time.February:
+		rainChance = 0.35
+	}
+	rainChance = math.Max(0, math.Min(0.65, rainChance+rainChanceOffset))
+
+	r := s.Rng.Float64()
+	switch {
+	case r < rainChance:
+		return models.WeatherRain
+	case r < rainChance+0.35:
+		return models.WeatherCloudy
+	default:
+		return models.WeatherClear
+	}
+}
+
+func (s *Simulator) sampleTemperature(t time.Time, condition string) float64 {
+	climate := s.Config.ClimateProfile()
+	t = s.localTime(t)
+
+	// seasonal mean peaking in mid July (mid January south of the equator for
+	// a configured climate), plus a daily cycle peaking mid afternoon
+	peak := time.Date(t.Year(), time.July, 15, 0, 0, 0, 0, t.Location())
+	if s.Config.Climate != "" && s.Config.CityLat < 0 {
+		peak = time.Date(t.Year(), time.January, 15, 0, 0, 0, 0, t.Location())
+	}
+	seasonal := climate.MeanTemperature + climate.SeasonalAmplitude*math.Cos((yearFraction(t)-yearFraction(peak))*2*math.Pi)
+	daily := climate.DiurnalRange / 2 * math.Cos(float64(t.Hour()-15)/24*2*math.Pi)
+
+	switch condition {
+	case models.WeatherCloudy:
+		daily *= 0.5
+	case models.WeatherRain, models.WeatherSnow:
+		seasonal -= 2
+		daily *= 0.3
+	}
+
+	temperature := seasonal + daily + (s.Rng.Float64()-0.5)*2
+	return math.Round(temperature*10) / 10
+}
+
+// currentTrafficDensity returns the latest city-wide traffic density
+func (s *Simulator) currentTrafficDensity() float64 {
+	if len(s.TrafficConditions) == 0 {
+		return s.generateTrafficDensity(s.CurrentTime)
+	}
+	return s.TrafficConditions[0].Density
+}
+
+// attachConditions records the weather at loc and the traffic at the time of
+// an order lifecycle event on its base event
+func (s *Simulator) attachConditions(baseEvent *BaseEvent, loc models.Location) {
+	current := s.getCurrentWeather(loc)
+	weather := s.Config.WeatherLabel(current.Condition)
+	temperature := math.Round(s.Config.OutputTemperature(current.Temperature)*10) / 10
+	traffic := math.Round(s.currentTrafficDensity()*1000) / 1000
+	baseEvent.Weather = &weather
+	baseEvent.Temperature = &temperature
+	baseEvent.TrafficDensity = &traffic
+}
+
+// orderLocation is where an order is delivered to
+func orderLocation(order *models.Order) models.Location {
+	return models.Location{Lat: order.Address.Latitude, Lon: order.Address.Longitude}
+}
@@ -0,0 +1,77 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisdamba/foodatasim/internal/models"
+)
+
+func TestEveryWeatherConditionIsGenerated(t *testing.T) {
+	s := NewSimulator(&models.Config{Seed: 42, Climate: models.ClimateHumidContinental, CityLat: 45})
+	seen := make(map[string]bool)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for s.CurrentTime = start; s.CurrentTime.Before(start.AddDate(1, 0, 0)); s.CurrentTime = s.CurrentTime.Add(simulationTimeStep) {
+		s.updateWeather()
+		seen[s.Weather.Condition] = true
+	}
+	for _, condition := range models.WeatherConditions {
+		if !seen[condition] {
+			t.Errorf("condition %q never generated over a year", condition)
+		}
+		delete(seen, condition)
+	}
+	for condition := range seen {
+		t.Errorf("generated condition %q is not in models.WeatherConditions", condition)
+	}
+}
+
+func TestWeatherConditionEffects(t *testing.T) {
+	s := NewSimulator(&models.Config{Seed: 42})
+	tests := []struct {
+		condition string
+		wind      float64 // mean wind speed, km/h
+		wet       bool
+		extreme   bool
+	}{
+		{models.WeatherClear, 8, false, false},
+		{models.WeatherCloudy, 12, false, false},
+		{models.WeatherRain, 18, true, false},
+		{models.WeatherSnow, 15, true, true},
+	}
+	if len(tests) != len(models.WeatherConditions) {
+		t.Fatalf("test covers %d conditions, taxonomy has %d", len(tests), len(models.WeatherConditions))
+	}
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			const samples = 2000
+			var wind, precipitation float64
+			for i := 0; i < samples; i++ {
+				weather := models.WeatherCondition{Condition: tt.condition, Temperature: 10}
+				s.sampleAtmosphere(&weather)
+				wind += weather.WindSpeed
+				precipitation += weather.Precipitation
+			}
+			if mean := wind / samples; mean < tt.wind-1 || mean > tt.wind+1 {
+				t.Errorf("mean wind %.1f km/h, want about %.0f", mean, tt.wind)
+			}
+			if wet := precipitation > 0; wet != tt.wet {
+				t.Errorf("total precipitation %.1f mm, want wet=%v", precipitation, tt.wet)
+			}
+			weather := models.WeatherCondition{Condition: tt.condition, Temperature: 10}
+			if got := extremeWeather(weather); got != tt.extreme {
+				t.Errorf("extremeWeather = %v, want %v", got, tt.extreme)
+			}
+		})
+	}
+}
+
+func TestWeatherLabel(t *testing.T) {
+	cfg := &models.Config{WeatherConditionLabels: map[string]string{models.WeatherRain: "precipitation"}}
+	if got := cfg.WeatherLabel(models.WeatherRain); got != "precipitation" {
+		t.Errorf("relabelled rain = %q, want %q", got, "precipitation")
+	}
+	if got := cfg.WeatherLabel(models.WeatherSnow); got != models.WeatherSnow {
+		t.Errorf("unlabelled snow = %q, want it unchanged", got)
+	}
+}